@@ -17,32 +17,116 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 
 	"github.com/Ridecell/ridecell-operator/pkg/apis"
+	configv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/config/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	operatorconfig "github.com/Ridecell/ridecell-operator/pkg/config"
 	"github.com/Ridecell/ridecell-operator/pkg/controller"
+	occomponents "github.com/Ridecell/ridecell-operator/pkg/controller/operatorconfig/components"
+	"github.com/Ridecell/ridecell-operator/pkg/health"
+	"github.com/Ridecell/ridecell-operator/pkg/logging"
+	"github.com/Ridecell/ridecell-operator/pkg/templates"
+	"github.com/Ridecell/ridecell-operator/pkg/webhook"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 )
 
+var disableWebhooks bool
+var enableDefaulting bool
+var templatesDir string
+var logJSON bool
+var logLevel int
+var namespaces string
+var namespaceSelector string
+var metricsAddr string
+var maxConcurrentReconciles string
+var leaderElectionID string
+var healthAddr string
+
+func init() {
+	flag.BoolVar(&disableWebhooks, "disable-webhooks", false, "don't run the admission webhook server, useful when running outside the cluster")
+	flag.BoolVar(&enableDefaulting, "enable-defaulting-webhook", false, "persist defaults into new SummonPlatforms on create")
+	flag.BoolVar(&logJSON, "log-json", false, "write structured logs as JSON")
+	flag.IntVar(&logLevel, "log-level", 0, "verbosity of structured logs, higher is more verbose")
+	flag.DurationVar(&components.ReconcileTimeout, "reconcile-timeout", components.ReconcileTimeout, "maximum time for one reconcile of an object")
+	flag.DurationVar(&components.ComponentTimeout, "component-timeout", components.ComponentTimeout, "default maximum time for a single component within a reconcile")
+	flag.StringVar(&templatesDir, "templates-dir", "", "load templates from this copy of pkg/ instead of the built-in assets and reload them on change")
+	flag.StringVar(&namespaces, "namespaces", "", "comma-separated namespaces to reconcile objects in, defaults to all")
+	flag.StringVar(&namespaceSelector, "namespace-selector", "", "only reconcile objects in namespaces with labels matching this selector, like \"env=dev,!canary\"")
+	flag.StringVar(&healthAddr, "health-addr", ":8081", "address the /healthz and /readyz probe and /dependencies report endpoints bind to, \"0\" to disable")
+	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "address the Prometheus metrics endpoint binds to, \"0\" to disable")
+	flag.IntVar(&components.DefaultMaxConcurrentReconciles, "default-max-concurrent-reconciles", components.DefaultMaxConcurrentReconciles, "workers per controller when not set by -max-concurrent-reconciles")
+	flag.StringVar(&maxConcurrentReconciles, "max-concurrent-reconciles", "", "workers for specific controllers, like \"summon-platform=4,rds=2\"")
+	flag.IntVar(&components.OperatorScope.ShardIndex, "shard-index", 0, "which shard of namespaces this replica reconciles, from 0 to shard-count-1")
+	flag.IntVar(&components.OperatorScope.ShardCount, "shard-count", 0, "split namespaces by hash between this many replicas, 0 to disable sharding")
+	flag.StringVar(&occomponents.ConfigName, "operator-config", occomponents.ConfigName, "name of the cluster-scoped OperatorConfig to read settings from")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "", "name of the leader election lock, replicas running side by side need different ones. Defaults to one per namespace scope and shard")
+}
+
 func main() {
 	flag.Parse()
 
+	// Set up structured logging for both our reconcilers and controller-runtime.
+	logging.SetLogger(logging.New(logJSON, logLevel))
+	logf.SetLogger(logging.Log)
+
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// Work out which objects this replica is responsible for.
+	components.OperatorScope.Namespaces = components.ParseNamespaces(namespaces)
+	if namespaceSelector != "" {
+		components.OperatorScope.NamespaceSelector, err = labels.Parse(namespaceSelector)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := components.OperatorScope.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	components.MaxConcurrentReconciles, err = components.ParseMaxConcurrentReconciles(maxConcurrentReconciles)
+	if err != nil {
+		log.Fatal(err)
+	}
+	options := manager.Options{
+		LeaderElection:     true,
+		LeaderElectionID:   leaderElectionID,
+		MetricsBindAddress: metricsAddr,
+	}
+	if options.LeaderElectionID == "" {
+		options.LeaderElectionID = components.OperatorScope.LeaderElectionID()
+	}
+
 	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{LeaderElection: true})
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	stop := signals.SetupSignalHandler()
+
+	// Load templates from disk, if requested.
+	if templatesDir != "" {
+		if err := templates.UseDir(templatesDir, stop); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Loading templates from %s.", templatesDir)
+	}
+
 	log.Printf("Registering Components.")
 
 	// Setup Scheme for all resources
@@ -50,13 +134,53 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Load the OperatorConfig before anything reads settings, so a bad one stops us here rather
+	// than half way through a reconcile. The manager's cache isn't running yet, so read directly.
+	directClient, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		log.Fatal(err)
+	}
+	operatorConfig := &configv1beta1.OperatorConfig{}
+	err = directClient.Get(context.Background(), types.NamespacedName{Name: occomponents.ConfigName}, operatorConfig)
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Fatal(err)
+	} else if err != nil {
+		log.Printf("No OperatorConfig %s found, using environment variables.", occomponents.ConfigName)
+	} else {
+		loaded, err := occomponents.Load(context.Background(), directClient, operatorConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+		operatorconfig.SetCurrent(loaded)
+		log.Printf("Loaded OperatorConfig %s.", occomponents.ConfigName)
+	}
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Fatal(err)
 	}
 
+	// Setup all admission webhooks
+	if !disableWebhooks {
+		if err := webhook.AddToManager(mgr, webhook.Options{Defaulting: enableDefaulting}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Serve liveness and readiness probes, plus an informational report on our external dependencies.
+	if healthAddr != "0" {
+		healthServer := health.NewServer()
+		health.AddReadinessChecks(healthServer.Readiness, mgr.GetCache())
+		health.AddDependencyChecks(healthServer.Dependencies)
+		go func() {
+			if err := healthServer.Start(healthAddr, stop); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	log.Printf("Starting the Cmd.")
 
 	// Start the Cmd
-	log.Fatal(mgr.Start(signals.SetupSignalHandler()))
+	log.Fatal(mgr.Start(stop))
 }
@@ -0,0 +1,271 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Plan renders every template for a top object and shows how it differs from what is
+// currently in the cluster, without changing anything.
+//
+//	plan summon-dev/foo-dev
+//	plan -f foo-dev.yml -empty
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis"
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/controller/postgresdatabase"
+	pgdbcomponents "github.com/Ridecell/ridecell-operator/pkg/controller/postgresdatabase/components"
+	"github.com/Ridecell/ridecell-operator/pkg/controller/rabbitmq_vhost"
+	vhostcomponents "github.com/Ridecell/ridecell-operator/pkg/controller/rabbitmq_vhost/components"
+	"github.com/Ridecell/ridecell-operator/pkg/controller/summon"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+	"github.com/Ridecell/ridecell-operator/pkg/templates"
+)
+
+// Extra template data for one template, the same as its component would pass.
+type planDataFunc func(ctx *components.ComponentContext, path string) (map[string]interface{}, error)
+
+// The top object types we know how to plan.
+type planTarget struct {
+	newObject func() runtime.Object
+	templates http.FileSystem
+	defaults  func() components.Component
+	planData  planDataFunc
+}
+
+var targets = map[string]planTarget{
+	"SummonPlatform": {
+		newObject: func() runtime.Object { return &summonv1beta1.SummonPlatform{} },
+		templates: summon.Templates,
+		defaults:  func() components.Component { return summoncomponents.NewDefaults() },
+		planData:  summoncomponents.PlanData,
+	},
+	"PostgresDatabase": {
+		newObject: func() runtime.Object { return &dbv1beta1.PostgresDatabase{} },
+		templates: postgresdatabase.Templates,
+		defaults:  func() components.Component { return pgdbcomponents.NewDefaults() },
+		planData:  skipTemplates(map[string]string{"extension.yml.tpl": "rendered once per extension"}),
+	},
+	"RabbitmqVhost": {
+		newObject: func() runtime.Object { return &dbv1beta1.RabbitmqVhost{} },
+		templates: rabbitmq_vhost.Templates,
+		defaults:  func() components.Component { return vhostcomponents.NewDefaults() },
+		planData:  skipTemplates(nil),
+	},
+}
+
+// Plan data for targets whose templates need no extra data, skipping the ones which can't be planned.
+func skipTemplates(reasons map[string]string) planDataFunc {
+	return func(_ *components.ComponentContext, path string) (map[string]interface{}, error) {
+		reason, ok := reasons[path]
+		if ok {
+			return nil, errors.Wrap(components.ErrPlanSkipped, reason)
+		}
+		return nil, nil
+	}
+}
+
+var kind string
+var filename string
+var emptyCluster bool
+var output string
+var showUnchanged bool
+
+func init() {
+	flag.StringVar(&kind, "kind", "SummonPlatform", "kind of the top object to plan")
+	flag.StringVar(&filename, "f", "", "read the top object from a YAML file instead of the cluster")
+	flag.BoolVar(&emptyCluster, "empty", false, "compare against an empty cluster instead of the current one")
+	flag.StringVar(&output, "o", "text", "output format, text or json")
+	flag.BoolVar(&showUnchanged, "show-unchanged", false, "include unchanged objects in text output")
+}
+
+func main() {
+	flag.Parse()
+
+	target, ok := targets[kind]
+	if !ok {
+		log.Fatalf("Unknown kind %s", kind)
+	}
+	if output != "text" && output != "json" {
+		log.Fatal(`-o must be "text" or "json"`)
+	}
+
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		log.Fatal(err)
+	}
+
+	// Only connect to the cluster when we need it.
+	var c client.Client
+	if !emptyCluster || filename == "" {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		mapper, err := apiutil.NewDiscoveryRESTMapper(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		c, err = client.New(cfg, client.Options{Scheme: scheme.Scheme, Mapper: mapper})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Load the top object.
+	top := target.newObject()
+	if filename != "" {
+		raw, err := ioutil.ReadFile(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		top, _, err = scheme.Codecs.UniversalDeserializer().Decode(raw, nil, top)
+		if err != nil {
+			log.Fatalf("Unable to parse %s: %s", filename, err)
+		}
+	} else {
+		if flag.NArg() != 1 {
+			log.Fatal("Usage: plan [flags] namespace/name")
+		}
+		parts := strings.SplitN(flag.Arg(0), "/", 2)
+		if len(parts) != 2 {
+			log.Fatalf("Object must be given as namespace/name, got %s", flag.Arg(0))
+		}
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: parts[0], Name: parts[1]}, top)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Nothing in here writes, but use a fake client for empty mode so it really can't.
+	if emptyCluster {
+		c = fake.NewFakeClient(top)
+	}
+	ctx := components.NewContext(top, c, scheme.Scheme, target.templates)
+
+	// Fill in defaults the same way the controller does.
+	res, err := target.defaults().Reconcile(ctx)
+	if err != nil {
+		log.Fatalf("Error running defaults: %s", err)
+	}
+	if res.StatusModifier != nil {
+		err = res.StatusModifier(ctx.Top)
+		if err != nil {
+			log.Fatalf("Error running defaults status modifier: %s", err)
+		}
+	}
+
+	paths, err := templates.List(target.templates)
+	if err != nil {
+		log.Fatal(err)
+	}
+	changes := []components.PlannedChange{}
+	for _, path := range paths {
+		extra, err := target.planData(ctx, path)
+		if errors.Cause(err) == components.ErrPlanSkipped {
+			changes = append(changes, components.PlannedChange{Template: path, Action: components.PlanSkipped, Err: err})
+			continue
+		} else if err != nil {
+			changes = append(changes, components.PlannedChange{Template: path, Action: components.PlanError, Err: err})
+			continue
+		}
+		changes = append(changes, ctx.Plan(path, extra))
+	}
+
+	if output == "json" {
+		printJSON(changes)
+	} else {
+		printText(changes)
+	}
+}
+
+// JSON form of a PlannedChange, since errors don't serialize.
+type jsonChange struct {
+	Template  string                `json:"template"`
+	Kind      string                `json:"kind,omitempty"`
+	Namespace string                `json:"namespace,omitempty"`
+	Name      string                `json:"name,omitempty"`
+	Action    components.PlanAction `json:"action"`
+	Diff      string                `json:"diff,omitempty"`
+	Error     string                `json:"error,omitempty"`
+}
+
+func printJSON(changes []components.PlannedChange) {
+	out := []jsonChange{}
+	for _, change := range changes {
+		jc := jsonChange{
+			Template:  change.Template,
+			Kind:      change.Kind,
+			Namespace: change.Namespace,
+			Name:      change.Name,
+			Action:    change.Action,
+			Diff:      change.Diff,
+		}
+		if change.Err != nil {
+			jc.Error = change.Err.Error()
+		}
+		out = append(out, jc)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(out)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func printText(changes []components.PlannedChange) {
+	counts := map[components.PlanAction]int{}
+	for _, change := range changes {
+		counts[change.Action]++
+		switch change.Action {
+		case components.PlanError:
+			fmt.Printf("! %s: %s\n\n", change.Template, change.Err)
+		case components.PlanUnchanged:
+			if showUnchanged {
+				fmt.Printf("= %s %s/%s (%s)\n\n", change.Kind, change.Namespace, change.Name, change.Template)
+			}
+		case components.PlanSkipped:
+			fmt.Printf("~ %s skipped: %s\n\n", change.Template, change.Err)
+		case components.PlanEmpty:
+			if showUnchanged {
+				fmt.Printf("= %s rendered nothing\n\n", change.Template)
+			}
+		default:
+			fmt.Printf("%s %s %s/%s (%s)\n%s\n", change.Action, change.Kind, change.Namespace, change.Name, change.Template, change.Diff)
+		}
+	}
+	fmt.Printf("Plan: %d to create, %d to update, %d unchanged, %d empty, %d skipped, %d errors.\n", counts[components.PlanCreate], counts[components.PlanUpdate], counts[components.PlanUnchanged], counts[components.PlanEmpty], counts[components.PlanSkipped], counts[components.PlanError])
+}
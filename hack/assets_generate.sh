@@ -20,7 +20,7 @@ func main() {
   err := vfsgen.Generate($2.Templates, vfsgen.Options{
     PackageName:  "$2",
     BuildTags:    "release",
-    VariableName: "Templates",
+    VariableName: "assets",
     Filename:     "zz_generated.templates.go",
   })
   if err != nil {
@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // ElasticSearchSpec defines the desired state of ElasticSearch
@@ -48,6 +50,9 @@ type ElasticSearchStatus struct {
 	Status         string `json:"status"`
 	Message        string `json:"message"`
 	DomainEndpoint string `json:"domainEndpoint"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
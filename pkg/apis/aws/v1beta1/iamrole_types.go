@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // IAMRoleSpec defines the desired state of IAMRole
@@ -33,6 +35,9 @@ type IAMRoleStatus struct {
 	Status   string `json:"status"`
 	Message  string `json:"message"`
 	RoleName string `json:"roleName"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // IAMUserSpec defines the desired state of IAMUser
@@ -31,6 +33,9 @@ type IAMUserSpec struct {
 type IAMUserStatus struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
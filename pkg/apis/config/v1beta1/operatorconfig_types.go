@@ -0,0 +1,105 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+)
+
+// The OperatorConfig the operator reads, unless overridden by the -operator-config flag.
+const DefaultOperatorConfigName = "default"
+
+// SecretKeyRef points at one key of a secret in any namespace.
+type SecretKeyRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+// OperatorConfigSpec defines the desired state of OperatorConfig
+type OperatorConfigSpec struct {
+	// Plain settings keyed by name, like DEPLOY_STAT_URL. See pkg/config for the known names.
+	// Anything not set falls back to the environment variable of the same name.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+	// Settings read from secrets, required for credentials like SLACK_API_KEY.
+	// +optional
+	SecretSettings map[string]SecretKeyRef `json:"secretSettings,omitempty"`
+	// Named feature gates to turn on or off, like Finalizers.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// SettingStatus reports where one setting is coming from, never its value.
+type SettingStatus struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// OperatorConfigStatus defines the observed state of OperatorConfig
+type OperatorConfigStatus struct {
+	// Overall object status
+	Status string `json:"status,omitempty"`
+
+	// Message related to the current status.
+	Message string `json:"message,omitempty"`
+
+	// The metadata.generation the settings in effect were loaded from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Source of every known setting.
+	// +optional
+	Settings []SettingStatus `json:"settings,omitempty"`
+	// State of every known feature gate.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatorConfig is the Schema for the operatorconfigs API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.status",description="object status"
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigSpec   `json:"spec,omitempty"`
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatorConfigList contains a list of OperatorConfig
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+}
@@ -19,6 +19,8 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // PostgresDatabaseSpec defines the desired state of PostgresDatabase
@@ -55,6 +57,9 @@ type PostgresDatabaseStatus struct {
 	AdminConnection       PostgresConnection `json:"adminConnection"`
 	SharedUsers           SharedUsersStatus  `json:"sharedUsers"`
 	RDSInstanceID         string             `json:"rdsInstanceId,omitempty"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // PostgresExtensionSpec defines the desired state of PostgresExtension
@@ -31,6 +33,9 @@ type PostgresExtensionSpec struct {
 type PostgresExtensionStatus struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
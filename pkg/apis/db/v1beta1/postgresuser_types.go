@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // PostgresUserSpec defines the desired state of PostgresUser
@@ -31,6 +33,9 @@ type PostgresUserStatus struct {
 	Status     string             `json:"status"`
 	Message    string             `json:"message"`
 	Connection PostgresConnection `json:"connection"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 type RabbitmqPolicy struct {
@@ -46,6 +48,9 @@ type RabbitmqVhostStatus struct {
 	Status     string                   `json:"status"`
 	Message    string                   `json:"message"`
 	Connection RabbitmqStatusConnection `json:"connection,omitempty"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
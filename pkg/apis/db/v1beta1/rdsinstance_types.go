@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // RDSInstanceSpec defines the desired state of RDS
@@ -43,6 +45,9 @@ type RDSInstanceStatus struct {
 	Connection      PostgresConnection `json:"rdsConnection"`
 	InstanceID      string             `json:"instanceID"`
 	SecurityGroupID string             `json:"securityGroupID"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
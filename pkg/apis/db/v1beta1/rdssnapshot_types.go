@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // RDSSnapshotSpec defines the desired state of RDSSnapshot
@@ -37,6 +39,9 @@ type RDSSnapshotStatus struct {
 	Status     string `json:"status"`
 	Message    string `json:"message"`
 	SnapshotID string `json:"snapshotId"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 )
 
@@ -33,6 +34,14 @@ func (pe *PostgresExtension) SetErrorStatus(errorMsg string) {
 	pe.Status.Message = errorMsg
 }
 
+func (pe *PostgresExtension) GetConditions() []conditions.Condition {
+	return pe.Status.Conditions
+}
+
+func (pe *PostgresExtension) SetConditions(conds []conditions.Condition) {
+	pe.Status.Conditions = conds
+}
+
 func (po *PostgresOperatorDatabase) GetStatus() components.Status {
 	return po.Status
 }
@@ -46,6 +55,14 @@ func (po *PostgresOperatorDatabase) SetErrorStatus(errorMsg string) {
 	po.Status.Message = errorMsg
 }
 
+func (po *PostgresOperatorDatabase) GetConditions() []conditions.Condition {
+	return po.Status.Conditions
+}
+
+func (po *PostgresOperatorDatabase) SetConditions(conds []conditions.Condition) {
+	po.Status.Conditions = conds
+}
+
 func (pe *RabbitmqVhost) GetStatus() components.Status {
 	return pe.Status
 }
@@ -59,6 +76,14 @@ func (pe *RabbitmqVhost) SetErrorStatus(errorMsg string) {
 	pe.Status.Message = errorMsg
 }
 
+func (pe *RabbitmqVhost) GetConditions() []conditions.Condition {
+	return pe.Status.Conditions
+}
+
+func (pe *RabbitmqVhost) SetConditions(conds []conditions.Condition) {
+	pe.Status.Conditions = conds
+}
+
 func (pe *RabbitmqUser) GetStatus() components.Status {
 	return pe.Status
 }
@@ -72,6 +97,14 @@ func (pe *RabbitmqUser) SetErrorStatus(errorMsg string) {
 	pe.Status.Message = errorMsg
 }
 
+func (pe *RabbitmqUser) GetConditions() []conditions.Condition {
+	return pe.Status.Conditions
+}
+
+func (pe *RabbitmqUser) SetConditions(conds []conditions.Condition) {
+	pe.Status.Conditions = conds
+}
+
 func (rds *RDSInstance) GetStatus() components.Status {
 	return rds.Status
 }
@@ -85,6 +118,14 @@ func (rds *RDSInstance) SetErrorStatus(errorMsg string) {
 	rds.Status.Message = errorMsg
 }
 
+func (rds *RDSInstance) GetConditions() []conditions.Condition {
+	return rds.Status.Conditions
+}
+
+func (rds *RDSInstance) SetConditions(conds []conditions.Condition) {
+	rds.Status.Conditions = conds
+}
+
 func (snap *RDSSnapshot) GetStatus() components.Status {
 	return snap.Status
 }
@@ -98,6 +139,14 @@ func (snap *RDSSnapshot) SetErrorStatus(errorMsg string) {
 	snap.Status.Message = errorMsg
 }
 
+func (snap *RDSSnapshot) GetConditions() []conditions.Condition {
+	return snap.Status.Conditions
+}
+
+func (snap *RDSSnapshot) SetConditions(conds []conditions.Condition) {
+	snap.Status.Conditions = conds
+}
+
 func (pgu *PostgresUser) GetStatus() components.Status {
 	return pgu.Status
 }
@@ -111,6 +160,14 @@ func (pgu *PostgresUser) SetErrorStatus(errorMsg string) {
 	pgu.Status.Message = errorMsg
 }
 
+func (pgu *PostgresUser) GetConditions() []conditions.Condition {
+	return pgu.Status.Conditions
+}
+
+func (pgu *PostgresUser) SetConditions(conds []conditions.Condition) {
+	pgu.Status.Conditions = conds
+}
+
 func (pgu *PostgresDatabase) GetStatus() components.Status {
 	return pgu.Status
 }
@@ -124,6 +181,14 @@ func (pgu *PostgresDatabase) SetErrorStatus(errorMsg string) {
 	pgu.Status.Message = errorMsg
 }
 
+func (pgu *PostgresDatabase) GetConditions() []conditions.Condition {
+	return pgu.Status.Conditions
+}
+
+func (pgu *PostgresDatabase) SetConditions(conds []conditions.Condition) {
+	pgu.Status.Conditions = conds
+}
+
 func (pgu *DbConfig) GetStatus() components.Status {
 	return pgu.Status
 }
@@ -136,3 +201,11 @@ func (pgu *DbConfig) SetErrorStatus(errorMsg string) {
 	pgu.Status.Status = StatusError
 	pgu.Status.Message = errorMsg
 }
+
+func (pgu *DbConfig) GetConditions() []conditions.Condition {
+	return pgu.Status.Conditions
+}
+
+func (pgu *DbConfig) SetConditions(conds []conditions.Condition) {
+	pgu.Status.Conditions = conds
+}
@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // GCPProjectSpec defines the desired state of GCPProject
@@ -43,6 +45,9 @@ type GCPProjectStatus struct {
 	Message               string `json:"message"`
 	ProjectOperationName  string `json:"projectOperationName,omitempty"`
 	FirebaseOperationName string `json:"firebaseOperationName,omitempty"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // ServiceAccountSpec defines the desired state of ServiceAccount
@@ -32,6 +34,9 @@ type GCPServiceAccountStatus struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 	Email   string `json:"email"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 )
 
@@ -33,6 +34,14 @@ func (sa *GCPServiceAccount) SetErrorStatus(errorMsg string) {
 	sa.Status.Message = errorMsg
 }
 
+func (sa *GCPServiceAccount) GetConditions() []conditions.Condition {
+	return sa.Status.Conditions
+}
+
+func (sa *GCPServiceAccount) SetConditions(conds []conditions.Condition) {
+	sa.Status.Conditions = conds
+}
+
 func (gp *GCPProject) GetStatus() components.Status {
 	return gp.Status
 }
@@ -45,3 +54,11 @@ func (gp *GCPProject) SetErrorStatus(errorMsg string) {
 	gp.Status.Status = StatusError
 	gp.Status.Message = errorMsg
 }
+
+func (gp *GCPProject) GetConditions() []conditions.Condition {
+	return gp.Status.Conditions
+}
+
+func (gp *GCPProject) SetConditions(conds []conditions.Condition) {
+	gp.Status.Conditions = conds
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"time"
+)
+
+// ConditionStatus is the status of a condition, one of True, False or Unknown.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition is a single observation of one aspect of an object's state. This mirrors
+// the upstream Kubernetes condition convention so it works with `kubectl wait`.
+type Condition struct {
+	// Type of condition in CamelCase, e.g. DatabaseReady.
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	// +kubebuilder:validation:Enum=True,False,Unknown
+	Status ConditionStatus `json:"status"`
+	// The metadata.generation the condition was last set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Last time the condition changed status.
+	// Real type = time.Time
+	// workaround because metav1.Time is broked
+	// +optional
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+	// Machine-readable reason for the last transition, in CamelCase.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human-readable details about the last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Find returns the condition of the given type, or nil if it isn't set.
+func Find(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsTrue checks if the condition of the given type is set and True.
+func IsTrue(conditions []Condition, conditionType string) bool {
+	cond := Find(conditions, conditionType)
+	return cond != nil && cond.Status == ConditionTrue
+}
+
+// Set adds or updates a condition in the list and returns the new list. The LastTransitionTime
+// is only updated when the status changes, so this is safe to call on every reconcile.
+func Set(conditions []Condition, newCondition Condition) []Condition {
+	existing := Find(conditions, newCondition.Type)
+	if existing == nil {
+		if newCondition.LastTransitionTime == "" {
+			newCondition.LastTransitionTime = time.Now().UTC().Format(time.RFC3339)
+		}
+		return append(conditions, newCondition)
+	}
+
+	if existing.Status != newCondition.Status {
+		existing.Status = newCondition.Status
+		existing.LastTransitionTime = newCondition.LastTransitionTime
+		if existing.LastTransitionTime == "" {
+			existing.LastTransitionTime = time.Now().UTC().Format(time.RFC3339)
+		}
+	}
+	existing.ObservedGeneration = newCondition.ObservedGeneration
+	existing.Reason = newCondition.Reason
+	existing.Message = newCondition.Message
+	return conditions
+}
+
+// Remove deletes the condition of the given type from the list, if present, and returns the new list.
+func Remove(conditions []Condition, conditionType string) []Condition {
+	var out []Condition
+	for _, cond := range conditions {
+		if cond.Type != conditionType {
+			out = append(out, cond)
+		}
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+)
+
+var _ = Describe("Conditions", func() {
+	Describe("Set", func() {
+		It("adds a new condition with a transition time", func() {
+			conds := conditions.Set(nil, conditions.Condition{Type: "Ready", Status: conditions.ConditionTrue, Reason: "Done"})
+			Expect(conds).To(HaveLen(1))
+			Expect(conds[0].Type).To(Equal("Ready"))
+			Expect(conds[0].Status).To(Equal(conditions.ConditionTrue))
+			Expect(conds[0].Reason).To(Equal("Done"))
+			Expect(conds[0].LastTransitionTime).ToNot(BeEmpty())
+		})
+
+		It("keeps a given transition time", func() {
+			conds := conditions.Set(nil, conditions.Condition{Type: "Ready", Status: conditions.ConditionTrue, LastTransitionTime: "2020-01-01T00:00:00Z"})
+			Expect(conds[0].LastTransitionTime).To(Equal("2020-01-01T00:00:00Z"))
+		})
+
+		It("keeps the transition time when the status doesn't change", func() {
+			conds := []conditions.Condition{{Type: "Ready", Status: conditions.ConditionFalse, LastTransitionTime: "2020-01-01T00:00:00Z", Reason: "Waiting", Message: "old"}}
+			conds = conditions.Set(conds, conditions.Condition{Type: "Ready", Status: conditions.ConditionFalse, ObservedGeneration: 2, Reason: "StillWaiting", Message: "new"})
+			Expect(conds).To(HaveLen(1))
+			Expect(conds[0].LastTransitionTime).To(Equal("2020-01-01T00:00:00Z"))
+			Expect(conds[0].ObservedGeneration).To(Equal(int64(2)))
+			Expect(conds[0].Reason).To(Equal("StillWaiting"))
+			Expect(conds[0].Message).To(Equal("new"))
+		})
+
+		It("updates the transition time when the status changes", func() {
+			conds := []conditions.Condition{{Type: "Ready", Status: conditions.ConditionFalse, LastTransitionTime: "2020-01-01T00:00:00Z"}}
+			conds = conditions.Set(conds, conditions.Condition{Type: "Ready", Status: conditions.ConditionTrue, Reason: "Done"})
+			Expect(conds).To(HaveLen(1))
+			Expect(conds[0].Status).To(Equal(conditions.ConditionTrue))
+			Expect(conds[0].LastTransitionTime).ToNot(Equal("2020-01-01T00:00:00Z"))
+			Expect(conds[0].LastTransitionTime).ToNot(BeEmpty())
+			Expect(conds[0].Reason).To(Equal("Done"))
+		})
+
+		It("leaves other conditions alone", func() {
+			conds := []conditions.Condition{
+				{Type: "Ready", Status: conditions.ConditionFalse},
+				{Type: "Synced", Status: conditions.ConditionTrue, Reason: "Synced"},
+			}
+			conds = conditions.Set(conds, conditions.Condition{Type: "Ready", Status: conditions.ConditionTrue})
+			Expect(conds).To(HaveLen(2))
+			Expect(conds[1]).To(Equal(conditions.Condition{Type: "Synced", Status: conditions.ConditionTrue, Reason: "Synced"}))
+		})
+	})
+
+	Describe("Find and IsTrue", func() {
+		conds := []conditions.Condition{
+			{Type: "Ready", Status: conditions.ConditionTrue},
+			{Type: "Synced", Status: conditions.ConditionFalse},
+		}
+
+		It("finds a condition by type", func() {
+			Expect(conditions.Find(conds, "Synced")).To(Equal(&conds[1]))
+			Expect(conditions.Find(conds, "Other")).To(BeNil())
+		})
+
+		It("checks if a condition is true", func() {
+			Expect(conditions.IsTrue(conds, "Ready")).To(BeTrue())
+			Expect(conditions.IsTrue(conds, "Synced")).To(BeFalse())
+			Expect(conditions.IsTrue(conds, "Other")).To(BeFalse())
+		})
+	})
+
+	Describe("Remove", func() {
+		It("removes a condition by type", func() {
+			conds := []conditions.Condition{
+				{Type: "Ready", Status: conditions.ConditionTrue},
+				{Type: "Synced", Status: conditions.ConditionFalse},
+			}
+			Expect(conditions.Remove(conds, "Ready")).To(Equal([]conditions.Condition{{Type: "Synced", Status: conditions.ConditionFalse}}))
+			Expect(conditions.Remove(conds, "Other")).To(HaveLen(2))
+		})
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlays
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// PatchType is the format of a patch body.
+type PatchType string
+
+const (
+	// Kubernetes strategic merge patch, the same as `kubectl patch --type strategic`.
+	StrategicMergePatch PatchType = "strategic"
+	// RFC 7386 JSON merge patch.
+	MergePatch PatchType = "merge"
+	// RFC 6902 JSON patch, a list of operations.
+	JSONPatch PatchType = "json"
+)
+
+// Patch is a change applied to one rendered object before it is created or updated.
+type Patch struct {
+	// Kind of the object to patch, e.g. Deployment.
+	Kind string `json:"kind"`
+	// Name of the object to patch.
+	Name string `json:"name"`
+	// Format of the patch, defaults to strategic.
+	// +kubebuilder:validation:Enum=strategic,merge,json
+	// +optional
+	Type PatchType `json:"type,omitempty"`
+	// The patch body, in YAML or JSON.
+	Patch string `json:"patch"`
+}
+
+// PatchStatus is the outcome of the last attempt to apply a Patch.
+type PatchStatus struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// True if the patch was applied to a rendered object.
+	Applied bool `json:"applied"`
+	// Details if the patch was not applied.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Matches returns true if the patch targets an object with the given kind and name.
+func (p *Patch) Matches(kind, name string) bool {
+	return p.Kind == kind && p.Name == name
+}
+
+// Validate checks that a patch is well-formed without applying it.
+func (p *Patch) Validate() error {
+	if p.Kind == "" || p.Name == "" {
+		return errors.New("kind and name are required")
+	}
+	patchJSON, err := yaml.YAMLToJSON([]byte(p.Patch))
+	if err != nil {
+		return errors.Wrap(err, "unable to parse patch")
+	}
+	switch p.Type {
+	case "", StrategicMergePatch, MergePatch:
+		obj := map[string]interface{}{}
+		err = json.Unmarshal(patchJSON, &obj)
+		if err != nil {
+			return errors.Wrap(err, "patch must be an object")
+		}
+	case JSONPatch:
+		_, err = jsonpatch.DecodePatch(patchJSON)
+		if err != nil {
+			return errors.Wrap(err, "patch must be a list of operations")
+		}
+	default:
+		return errors.Errorf("unknown patch type %s", p.Type)
+	}
+	return nil
+}
+
+// Apply returns a patched copy of obj. obj itself is not modified.
+func (p *Patch) Apply(obj runtime.Object) (runtime.Object, error) {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to serialize object")
+	}
+	patchJSON, err := yaml.YAMLToJSON([]byte(p.Patch))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse patch")
+	}
+
+	var patched []byte
+	switch p.Type {
+	case "", StrategicMergePatch:
+		patched, err = strategicpatch.StrategicMergePatch(original, patchJSON, obj)
+	case MergePatch:
+		patched, err = jsonpatch.MergePatch(original, patchJSON)
+	case JSONPatch:
+		var decoded jsonpatch.Patch
+		decoded, err = jsonpatch.DecodePatch(patchJSON)
+		if err == nil {
+			patched, err = decoded.Apply(original)
+		}
+	default:
+		err = errors.Errorf("unknown patch type %s", p.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to apply patch")
+	}
+
+	out := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+	err = json.Unmarshal(patched, out)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to deserialize patched object")
+	}
+	return out, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlays_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+)
+
+var _ = Describe("Overlays", func() {
+	Describe("Apply to a ConfigMap", func() {
+		cases := []struct {
+			name     string
+			patch    overlays.Patch
+			expected map[string]string
+			err      string
+		}{
+			{
+				name:     "defaults to a strategic merge",
+				patch:    overlays.Patch{Patch: "data:\n  b: \"3\"\n  c: \"4\"\n"},
+				expected: map[string]string{"a": "1", "b": "3", "c": "4"},
+			},
+			{
+				name:     "removes keys set to null in a strategic merge",
+				patch:    overlays.Patch{Type: overlays.StrategicMergePatch, Patch: "data:\n  a: null\n"},
+				expected: map[string]string{"b": "2"},
+			},
+			{
+				name:     "applies a JSON merge patch",
+				patch:    overlays.Patch{Type: overlays.MergePatch, Patch: `{"data": {"b": "3"}}`},
+				expected: map[string]string{"a": "1", "b": "3"},
+			},
+			{
+				name:     "applies a JSON patch",
+				patch:    overlays.Patch{Type: overlays.JSONPatch, Patch: `[{"op": "replace", "path": "/data/a", "value": "5"}, {"op": "remove", "path": "/data/b"}]`},
+				expected: map[string]string{"a": "5"},
+			},
+			{
+				name:  "fails a JSON patch on a missing path",
+				patch: overlays.Patch{Type: overlays.JSONPatch, Patch: `[{"op": "remove", "path": "/data/missing"}]`},
+				err:   "unable to apply patch",
+			},
+			{
+				name:  "fails on bad YAML",
+				patch: overlays.Patch{Patch: "data: [\n"},
+				err:   "unable to parse patch",
+			},
+			{
+				name:  "fails on an unknown type",
+				patch: overlays.Patch{Type: "other", Patch: "data: {}\n"},
+				err:   "unknown patch type other",
+			},
+		}
+
+		for _, c := range cases {
+			c := c
+			It(c.name, func() {
+				obj := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+					Data:       map[string]string{"a": "1", "b": "2"},
+				}
+				patched, err := c.patch.Apply(obj)
+				if c.err != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(c.err))
+					return
+				}
+				Expect(err).ToNot(HaveOccurred())
+				Expect(patched.(*corev1.ConfigMap).Data).To(Equal(c.expected))
+				Expect(patched.(*corev1.ConfigMap).Name).To(Equal("foo"))
+				// The original is left alone.
+				Expect(obj.Data).To(Equal(map[string]string{"a": "1", "b": "2"}))
+			})
+		}
+	})
+
+	Describe("Apply to a Deployment's containers", func() {
+		cases := []struct {
+			name       string
+			patch      overlays.Patch
+			containers []corev1.Container
+		}{
+			{
+				name:  "merges containers by name in a strategic merge",
+				patch: overlays.Patch{Patch: "spec:\n  template:\n    spec:\n      containers:\n      - name: web\n        image: web:2\n"},
+				containers: []corev1.Container{
+					{Name: "web", Image: "web:2", Args: []string{"serve"}},
+					{Name: "sidecar", Image: "sidecar:1"},
+				},
+			},
+			{
+				name:  "replaces the whole list in a JSON merge patch",
+				patch: overlays.Patch{Type: overlays.MergePatch, Patch: "spec:\n  template:\n    spec:\n      containers:\n      - name: web\n        image: web:2\n"},
+				containers: []corev1.Container{
+					{Name: "web", Image: "web:2"},
+				},
+			},
+		}
+
+		for _, c := range cases {
+			c := c
+			It(c.name, func() {
+				obj := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "web", Image: "web:1", Args: []string{"serve"}},
+									{Name: "sidecar", Image: "sidecar:1"},
+								},
+							},
+						},
+					},
+				}
+				patched, err := c.patch.Apply(obj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(patched.(*appsv1.Deployment).Spec.Template.Spec.Containers).To(Equal(c.containers))
+			})
+		}
+	})
+
+	Describe("Validate", func() {
+		cases := []struct {
+			name  string
+			patch overlays.Patch
+			err   string
+		}{
+			{
+				name:  "accepts a strategic merge patch",
+				patch: overlays.Patch{Kind: "ConfigMap", Name: "foo", Patch: "data:\n  a: b\n"},
+			},
+			{
+				name:  "accepts a JSON patch",
+				patch: overlays.Patch{Kind: "ConfigMap", Name: "foo", Type: overlays.JSONPatch, Patch: `[{"op": "remove", "path": "/data/a"}]`},
+			},
+			{
+				name:  "requires a kind",
+				patch: overlays.Patch{Name: "foo", Patch: "data: {}\n"},
+				err:   "kind and name are required",
+			},
+			{
+				name:  "requires a name",
+				patch: overlays.Patch{Kind: "ConfigMap", Patch: "data: {}\n"},
+				err:   "kind and name are required",
+			},
+			{
+				name:  "requires an object for a merge patch",
+				patch: overlays.Patch{Kind: "ConfigMap", Name: "foo", Type: overlays.MergePatch, Patch: "- a\n"},
+				err:   "patch must be an object",
+			},
+			{
+				name:  "requires a list for a JSON patch",
+				patch: overlays.Patch{Kind: "ConfigMap", Name: "foo", Type: overlays.JSONPatch, Patch: "data: {}\n"},
+				err:   "patch must be a list of operations",
+			},
+			{
+				name:  "rejects an unknown type",
+				patch: overlays.Patch{Kind: "ConfigMap", Name: "foo", Type: "other", Patch: "data: {}\n"},
+				err:   "unknown patch type other",
+			},
+		}
+
+		for _, c := range cases {
+			c := c
+			It(c.name, func() {
+				err := c.patch.Validate()
+				if c.err == "" {
+					Expect(err).ToNot(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(c.err))
+				}
+			})
+		}
+	})
+
+	It("matches on kind and name", func() {
+		patch := overlays.Patch{Kind: "ConfigMap", Name: "foo"}
+		Expect(patch.Matches("ConfigMap", "foo")).To(BeTrue())
+		Expect(patch.Matches("ConfigMap", "bar")).To(BeFalse())
+		Expect(patch.Matches("Secret", "foo")).To(BeFalse())
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transitions
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// Number of transitions kept by Append, older ones are dropped.
+const MaxHistory = 20
+
+// Longest message kept on a transition in bytes, anything past this is cut off.
+const MaxMessageLength = 256
+
+// Transition is a single change of an object's overall status.
+type Transition struct {
+	// Status before the change. Empty for the first status an object gets.
+	// +optional
+	From string `json:"from,omitempty"`
+	// Status after the change.
+	To string `json:"to"`
+	// When the change was made.
+	// Real type = time.Time
+	// workaround because metav1.Time is broked
+	Time string `json:"time"`
+	// Version of the object's spec at the time, if it has one.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Component which set the new status.
+	// +optional
+	Component string `json:"component,omitempty"`
+	// Status message at the time, truncated to MaxMessageLength.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// New builds a transition stamped with the current time.
+func New(from, to, component, message string) Transition {
+	return Transition{
+		From:      from,
+		To:        to,
+		Time:      time.Now().UTC().Format(time.RFC3339),
+		Component: component,
+		Message:   message,
+	}
+}
+
+// Append adds a transition to the end of the history and returns the new history, keeping at
+// most MaxHistory entries.
+func Append(history []Transition, transition Transition) []Transition {
+	if len(transition.Message) > MaxMessageLength {
+		// Back off to the start of a rune so we never split a multi-byte character.
+		cut := MaxMessageLength - 3
+		for cut > 0 && !utf8.RuneStart(transition.Message[cut]) {
+			cut--
+		}
+		transition.Message = transition.Message[:cut] + "..."
+	}
+	history = append(history, transition)
+	if len(history) > MaxHistory {
+		history = append([]Transition{}, history[len(history)-MaxHistory:]...)
+	}
+	return history
+}
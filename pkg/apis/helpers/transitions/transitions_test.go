@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transitions_test
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/transitions"
+)
+
+var _ = Describe("Append", func() {
+	It("keeps short messages as they are", func() {
+		history := transitions.Append(nil, transitions.New("", "Ready", "deployment", "all good"))
+		Expect(history).To(HaveLen(1))
+		Expect(history[0].Message).To(Equal("all good"))
+	})
+
+	It("keeps a message of exactly the maximum length", func() {
+		message := strings.Repeat("a", transitions.MaxMessageLength)
+		history := transitions.Append(nil, transitions.New("", "Error", "deployment", message))
+		Expect(history[0].Message).To(Equal(message))
+	})
+
+	It("truncates long messages", func() {
+		message := strings.Repeat("a", transitions.MaxMessageLength+1)
+		history := transitions.Append(nil, transitions.New("", "Error", "deployment", message))
+		Expect(history[0].Message).To(HaveLen(transitions.MaxMessageLength))
+		Expect(history[0].Message).To(HaveSuffix("..."))
+	})
+
+	It("does not split a multi-byte character when truncating", func() {
+		// Three bytes per rune, so the byte cap lands in the middle of one.
+		message := strings.Repeat("€", transitions.MaxMessageLength)
+		history := transitions.Append(nil, transitions.New("", "Error", "deployment", message))
+		Expect(utf8.ValidString(history[0].Message)).To(BeTrue())
+		Expect(len(history[0].Message)).To(BeNumerically("<=", transitions.MaxMessageLength))
+		Expect(strings.TrimSuffix(history[0].Message, "...")).To(Equal(strings.Repeat("€", (transitions.MaxMessageLength-3)/3)))
+	})
+
+	It("drops the oldest transitions past the maximum history", func() {
+		var history []transitions.Transition
+		for i := 0; i < transitions.MaxHistory+5; i++ {
+			history = transitions.Append(history, transitions.New("", fmt.Sprintf("status-%d", i), "deployment", ""))
+		}
+		Expect(history).To(HaveLen(transitions.MaxHistory))
+		Expect(history[0].To).To(Equal("status-5"))
+		Expect(history[transitions.MaxHistory-1].To).To(Equal(fmt.Sprintf("status-%d", transitions.MaxHistory+4)))
+	})
+})
@@ -19,6 +19,8 @@ package v1beta1
 import (
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 const (
@@ -34,6 +36,9 @@ type RidecellIngressStatus struct {
 	Status        string                   `json:"status,omitempty"`
 	Message       string                   `json:"message,omitempty"`
 	IngressStatus extv1beta1.IngressStatus `json:"ingressstatus,omitempty"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 )
 
@@ -33,6 +34,14 @@ func (amc *AlertManagerConfig) SetErrorStatus(errorMsg string) {
 	amc.Status.Message = errorMsg
 }
 
+func (amc *AlertManagerConfig) GetConditions() []conditions.Condition {
+	return amc.Status.Conditions
+}
+
+func (amc *AlertManagerConfig) SetConditions(conds []conditions.Condition) {
+	amc.Status.Conditions = conds
+}
+
 func (mon *Monitor) GetStatus() components.Status {
 	return mon.Status
 }
@@ -45,3 +54,11 @@ func (mon *Monitor) SetErrorStatus(errorMsg string) {
 	mon.Status.Status = StatusError
 	mon.Status.Message = errorMsg
 }
+
+func (mon *Monitor) GetConditions() []conditions.Condition {
+	return mon.Status.Conditions
+}
+
+func (mon *Monitor) SetConditions(conds []conditions.Condition) {
+	mon.Status.Conditions = conds
+}
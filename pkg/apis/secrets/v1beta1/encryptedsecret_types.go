@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 // KMS doesn't allow encrypting an empty string so use a magic constant to represent it.
@@ -27,6 +29,9 @@ const EncryptedSecretEmptyKey = "___empty_string___"
 type EncryptedSecretStatus struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
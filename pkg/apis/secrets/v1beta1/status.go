@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 )
 
@@ -33,6 +34,14 @@ func (s *PullSecret) SetErrorStatus(errorMsg string) {
 	s.Status.Message = errorMsg
 }
 
+func (s *PullSecret) GetConditions() []conditions.Condition {
+	return s.Status.Conditions
+}
+
+func (s *PullSecret) SetConditions(conds []conditions.Condition) {
+	s.Status.Conditions = conds
+}
+
 func (es *EncryptedSecret) GetStatus() components.Status {
 	return es.Status
 }
@@ -45,3 +54,11 @@ func (es *EncryptedSecret) SetErrorStatus(errorMsg string) {
 	es.Status.Status = StatusError
 	es.Status.Message = errorMsg
 }
+
+func (es *EncryptedSecret) GetConditions() []conditions.Condition {
+	return es.Status.Conditions
+}
+
+func (es *EncryptedSecret) SetConditions(conds []conditions.Condition) {
+	es.Status.Conditions = conds
+}
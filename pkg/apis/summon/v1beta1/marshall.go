@@ -17,8 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"bytes"
 	"encoding/json"
-	"errors"
+	"fmt"
 )
 
 // Intercept JSON decoding and try to deal with "simple" values before giving
@@ -27,55 +28,219 @@ import (
 //    config:
 //      foo: bar
 //      baz: false
+//      workers: 4
+//      hosts: [a.example.com, b.example.com]
+//      cache:
+//        timeout: 60
 //
 // in a config section. This is all because the Kubernetes codegen machinery
 // can't cope with a map[string]interface{}, since it could be some composite
 // type, which would break all kinds of things.
+//
+// A JSON object with a single key of bool, int, float, string, list, or map is
+// the struct form (which is how these get written back out), anything else is
+// a nested map. If you really need a nested map with one of those as its only
+// key, wrap it in the struct form: {map: {string: foo}}.
 func (v *ConfigValue) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep numbers as text so integers don't get squashed into floats.
+	decoder.UseNumber()
 	var tmp interface{}
-	err := json.Unmarshal(data, &tmp)
+	err := decoder.Decode(&tmp)
 	if err != nil {
 		// Wat?
 		return err
 	}
-	boolVal, ok := tmp.(bool)
-	if ok {
-		v.Bool = &boolVal
-		return nil
+	mapVal, ok := tmp.(map[string]interface{})
+	if ok && len(mapVal) == 1 {
+		for key, val := range mapVal {
+			switch key {
+			case "bool", "int", "float", "string", "list", "map":
+				return v.fromStructForm(key, val)
+			}
+		}
 	}
-	floatVal, ok := tmp.(float64)
-	if ok {
-		v.Float = &floatVal
-		return nil
+	parsed, err := NewConfigValue(tmp)
+	if err != nil {
+		return err
 	}
-	stringVal, ok := tmp.(string)
-	if ok {
-		v.String = &stringVal
-		return nil
+	*v = parsed
+	return nil
+}
+
+// Always write the struct form with exactly one key, the same one
+// ToNilInterface would pick. Relying on omitempty here loses empty lists and
+// maps, an empty list would come back out as `{}` and then parse as a map.
+func (v ConfigValue) MarshalJSON() ([]byte, error) {
+	if v.Bool != nil {
+		return json.Marshal(map[string]interface{}{"bool": *v.Bool})
+	} else if v.Int != nil {
+		return json.Marshal(map[string]interface{}{"int": *v.Int})
+	} else if v.Float != nil {
+		return json.Marshal(map[string]interface{}{"float": *v.Float})
+	} else if v.String != nil {
+		return json.Marshal(map[string]interface{}{"string": *v.String})
+	} else if v.List != nil {
+		return json.Marshal(map[string]interface{}{"list": v.List})
+	} else if v.Map != nil {
+		return json.Marshal(map[string]interface{}{"map": v.Map})
 	}
-	// It was something else, hopefully a JSON object.
-	mapVal, ok := tmp.(map[string]interface{})
-	if ok {
-		val, ok := mapVal["bool"]
-		if ok {
-			boolVal = val.(bool)
-			v.Bool = &boolVal
-			return nil
-		}
-		val, ok = mapVal["float"]
-		if ok {
-			floatVal = val.(float64)
-			v.Float = &floatVal
-			return nil
-		}
-		val, ok = mapVal["string"]
-		if ok {
-			stringVal = val.(string)
-			v.String = &stringVal
-			return nil
+	return []byte("{}"), nil
+}
+
+// Decode the explicit {type: value} form of a ConfigValue.
+func (v *ConfigValue) fromStructForm(key string, val interface{}) error {
+	var err error
+	switch key {
+	case "bool":
+		boolVal, ok := val.(bool)
+		if !ok {
+			return fmt.Errorf("error decoding JSON: bool value %v is a %T", val, val)
+		}
+		*v = ConfigValue{Bool: &boolVal}
+	case "int":
+		numVal, ok := val.(json.Number)
+		if !ok {
+			return fmt.Errorf("error decoding JSON: int value %v is a %T", val, val)
+		}
+		intVal, err := numVal.Int64()
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
+		}
+		*v = ConfigValue{Int: &intVal}
+	case "float":
+		numVal, ok := val.(json.Number)
+		if !ok {
+			return fmt.Errorf("error decoding JSON: float value %v is a %T", val, val)
+		}
+		floatVal, err := numVal.Float64()
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
 		}
+		*v = ConfigValue{Float: &floatVal}
+	case "string":
+		stringVal, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("error decoding JSON: string value %v is a %T", val, val)
+		}
+		*v = ConfigValue{String: &stringVal}
+	case "list":
+		listVal, ok := val.([]interface{})
+		if !ok {
+			return fmt.Errorf("error decoding JSON: list value %v is a %T", val, val)
+		}
+		*v, err = structFormList(listVal)
+	case "map":
+		mapVal, ok := val.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("error decoding JSON: map value %v is a %T", val, val)
+		}
+		*v, err = structFormMap(mapVal)
 	}
-	return errors.New("error decoding JSON")
+	return err
+}
+
+// Items of struct form lists and maps are themselves ConfigValues in either
+// form, so round-trip them back through UnmarshalJSON.
+func structFormItem(item interface{}) (ConfigValue, error) {
+	itemVal := ConfigValue{}
+	b, err := json.Marshal(item)
+	if err != nil {
+		return itemVal, err
+	}
+	err = itemVal.UnmarshalJSON(b)
+	return itemVal, err
+}
+
+func structFormList(listVal []interface{}) (ConfigValue, error) {
+	list := make([]ConfigValue, len(listVal))
+	for i, item := range listVal {
+		itemVal, err := structFormItem(item)
+		if err != nil {
+			return ConfigValue{}, err
+		}
+		list[i] = itemVal
+	}
+	return ConfigValue{List: list}, nil
+}
+
+func structFormMap(mapVal map[string]interface{}) (ConfigValue, error) {
+	m := make(map[string]ConfigValue, len(mapVal))
+	for key, item := range mapVal {
+		itemVal, err := structFormItem(item)
+		if err != nil {
+			return ConfigValue{}, err
+		}
+		m[key] = itemVal
+	}
+	return ConfigValue{Map: m}, nil
+}
+
+// Build a ConfigValue from a plain Go value, as decoded from JSON or YAML.
+// Nested lists and maps are converted recursively, and keep their items in the
+// plain form.
+func NewConfigValue(value interface{}) (ConfigValue, error) {
+	switch val := value.(type) {
+	case bool:
+		return ConfigValue{Bool: &val}, nil
+	case int:
+		intVal := int64(val)
+		return ConfigValue{Int: &intVal}, nil
+	case int64:
+		return ConfigValue{Int: &val}, nil
+	case float64:
+		return ConfigValue{Float: &val}, nil
+	case json.Number:
+		intVal, err := val.Int64()
+		if err == nil {
+			return ConfigValue{Int: &intVal}, nil
+		}
+		floatVal, err := val.Float64()
+		if err != nil {
+			return ConfigValue{}, fmt.Errorf("error decoding number %s: %v", val, err)
+		}
+		return ConfigValue{Float: &floatVal}, nil
+	case string:
+		return ConfigValue{String: &val}, nil
+	case []string:
+		list := make([]ConfigValue, len(val))
+		for i := range val {
+			list[i] = ConfigValue{String: &val[i]}
+		}
+		return ConfigValue{List: list}, nil
+	case []interface{}:
+		list := make([]ConfigValue, len(val))
+		for i, item := range val {
+			itemVal, err := NewConfigValue(item)
+			if err != nil {
+				return ConfigValue{}, err
+			}
+			list[i] = itemVal
+		}
+		return ConfigValue{List: list}, nil
+	case map[string]interface{}:
+		m := make(map[string]ConfigValue, len(val))
+		for key, item := range val {
+			itemVal, err := NewConfigValue(item)
+			if err != nil {
+				return ConfigValue{}, err
+			}
+			m[key] = itemVal
+		}
+		return ConfigValue{Map: m}, nil
+	case map[interface{}]interface{}:
+		// What gopkg.in/yaml.v2 gives you for a nested mapping.
+		m := make(map[string]ConfigValue, len(val))
+		for key, item := range val {
+			itemVal, err := NewConfigValue(item)
+			if err != nil {
+				return ConfigValue{}, err
+			}
+			m[fmt.Sprintf("%v", key)] = itemVal
+		}
+		return ConfigValue{Map: m}, nil
+	}
+	return ConfigValue{}, fmt.Errorf("error decoding JSON: unsupported config value %v (%T)", value, value)
 }
 
 // Run the reverse, convert the union back into an interface{} for use in JSON
@@ -83,10 +248,24 @@ func (v *ConfigValue) UnmarshalJSON(data []byte) error {
 func (v *ConfigValue) ToNilInterface() interface{} {
 	if v.Bool != nil {
 		return *v.Bool
+	} else if v.Int != nil {
+		return *v.Int
 	} else if v.Float != nil {
 		return *v.Float
 	} else if v.String != nil {
 		return *v.String
+	} else if v.List != nil {
+		list := make([]interface{}, len(v.List))
+		for i := range v.List {
+			list[i] = v.List[i].ToNilInterface()
+		}
+		return list
+	} else if v.Map != nil {
+		m := make(map[string]interface{}, len(v.Map))
+		for key, item := range v.Map {
+			m[key] = item.ToNilInterface()
+		}
+		return m
 	} else {
 		panic("Unknown ConfigValue type")
 	}
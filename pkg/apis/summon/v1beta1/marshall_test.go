@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+)
+
+var _ = Describe("ConfigValue JSON", func() {
+	host := "a.example.com"
+	timeout := int64(60)
+	ratio := 0.5
+	enabled := true
+
+	cases := []struct {
+		name  string
+		value summonv1beta1.ConfigValue
+		json  string
+	}{
+		{"an empty list", summonv1beta1.ConfigValue{List: []summonv1beta1.ConfigValue{}}, `{"list":[]}`},
+		{"an empty map", summonv1beta1.ConfigValue{Map: map[string]summonv1beta1.ConfigValue{}}, `{"map":{}}`},
+		{"a bool", summonv1beta1.ConfigValue{Bool: &enabled}, `{"bool":true}`},
+		{"a float", summonv1beta1.ConfigValue{Float: &ratio}, `{"float":0.5}`},
+		{"nested values", summonv1beta1.ConfigValue{Map: map[string]summonv1beta1.ConfigValue{
+			"timeout": {Int: &timeout},
+			"hosts":   {List: []summonv1beta1.ConfigValue{{String: &host}, {List: []summonv1beta1.ConfigValue{}}}},
+			"empty":   {Map: map[string]summonv1beta1.ConfigValue{}},
+			"string":  {Map: map[string]summonv1beta1.ConfigValue{"string": {String: &host}}},
+		}}, `{"map":{"empty":{"map":{}},"hosts":{"list":[{"string":"a.example.com"},{"list":[]}]},"string":{"map":{"string":{"string":"a.example.com"}}},"timeout":{"int":60}}}`},
+	}
+
+	for _, c := range cases {
+		c := c
+		It("round-trips "+c.name, func() {
+			data, err := json.Marshal(c.value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(c.json))
+
+			parsed := summonv1beta1.ConfigValue{}
+			err = json.Unmarshal(data, &parsed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(c.value))
+		})
+	}
+})
@@ -19,6 +19,8 @@ package v1beta1
 import (
 	//corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 )
 
 type MockCarServerTenantSpec struct {
@@ -33,6 +35,9 @@ type MockCarServerTenantStatus struct {
 	Status        string `json:"status,omitempty"`
 	Message       string `json:"message,omitempty"`
 	KeysSecretRef string `json:"keyssecretref,omitempty"`
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/transitions"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 )
 
@@ -33,6 +36,39 @@ func (s *SummonPlatform) SetErrorStatus(errorMsg string) {
 	s.Status.Message = errorMsg
 }
 
+func (s *SummonPlatform) GetConditions() []conditions.Condition {
+	return s.Status.Conditions
+}
+
+func (s *SummonPlatform) SetConditions(conds []conditions.Condition) {
+	s.Status.Conditions = conds
+}
+
+func (s *SummonPlatform) GetOverlays() []overlays.Patch {
+	return s.Spec.Overlays
+}
+
+func (s *SummonPlatform) GetOverlayStatus() []overlays.PatchStatus {
+	return s.Status.Overlays
+}
+
+func (s *SummonPlatform) SetOverlayStatus(statuses []overlays.PatchStatus) {
+	s.Status.Overlays = statuses
+}
+
+func (s *SummonPlatform) SetSkippedComponents(names []string) {
+	s.Status.SkippedComponents = names
+}
+
+func (s *SummonPlatform) CurrentStatus() (string, string) {
+	return s.Status.Status, s.Status.Message
+}
+
+func (s *SummonPlatform) RecordTransition(transition transitions.Transition) {
+	transition.Version = s.Spec.Version
+	s.Status.History = transitions.Append(s.Status.History, transition)
+}
+
 func (s *DjangoUser) GetStatus() components.Status {
 	return s.Status
 }
@@ -46,6 +82,14 @@ func (s *DjangoUser) SetErrorStatus(errorMsg string) {
 	s.Status.Message = errorMsg
 }
 
+func (s *DjangoUser) GetConditions() []conditions.Condition {
+	return s.Status.Conditions
+}
+
+func (s *DjangoUser) SetConditions(conds []conditions.Condition) {
+	s.Status.Conditions = conds
+}
+
 func (s *MockCarServerTenant) GetStatus() components.Status {
 	return s.Status
 }
@@ -58,3 +102,11 @@ func (s *MockCarServerTenant) SetErrorStatus(errorMsg string) {
 	s.Status.Status = StatusError
 	s.Status.Message = errorMsg
 }
+
+func (s *MockCarServerTenant) GetConditions() []conditions.Condition {
+	return s.Status.Conditions
+}
+
+func (s *MockCarServerTenant) SetConditions(conds []conditions.Condition) {
+	s.Status.Conditions = conds
+}
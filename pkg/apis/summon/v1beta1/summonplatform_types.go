@@ -23,14 +23,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/transitions"
 )
 
 // Gross workaround for limitations the Kubernetes code generator and interface{}.
 // If you want to see the weird inner workings of the hack, look in marshall.go.
 type ConfigValue struct {
-	Bool   *bool    `json:"bool,omitempty"`
-	Float  *float64 `json:"float,omitempty"`
-	String *string  `json:"string,omitempty"`
+	Bool   *bool                  `json:"bool,omitempty"`
+	Int    *int64                 `json:"int,omitempty"`
+	Float  *float64               `json:"float,omitempty"`
+	String *string                `json:"string,omitempty"`
+	List   []ConfigValue          `json:"list,omitempty"`
+	Map    map[string]ConfigValue `json:"map,omitempty"`
 }
 
 // NotificationsSpec defines notificiations settings for this instance.
@@ -178,6 +184,98 @@ type CompHwAuxSpec struct {
 	Version string `json:"version"`
 }
 
+// CanarySpec defines the canary rollout settings for new versions.
+type CanarySpec struct {
+	// Roll a new Spec.Version out to a few web and daphne pods first, and only continue to the
+	// rest of the instance once they have baked without problems.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Number of canary pods to run, as a percentage of the web and daphne replicas. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight,omitempty"`
+	// How long the canary pods must stay healthy before the version is promoted. Defaults to 10 minutes.
+	// +optional
+	BakeTime metav1.Duration `json:"bakeTime,omitempty"`
+	// Highest error rate, from 0 to 1, the canary pods may have while baking. Defaults to 0.05.
+	// +optional
+	MaxErrorRate *float64 `json:"maxErrorRate,omitempty"`
+	// Prometheus query giving the error rate of the canary pods. Defaults to the share of 5xx
+	// responses from the web canary.
+	// +optional
+	ErrorRateQuery string `json:"errorRateQuery,omitempty"`
+}
+
+// RollbackSpec defines the automatic rollback settings for failed deploys.
+type RollbackSpec struct {
+	// Put the Deployments back on the last ready version when a new version's migrations fail or it
+	// doesn't become ready within DeployDeadline.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// How long a new version has to become ready. Defaults to 30 minutes.
+	// +optional
+	DeployDeadline metav1.Duration `json:"deployDeadline,omitempty"`
+	// A version whose migrations are safe to run older code against. Rollback is refused for any
+	// other version which has already applied its migrations.
+	// +optional
+	BackwardCompatibleMigrations string `json:"backwardCompatibleMigrations,omitempty"`
+}
+
+// MaintenanceSpec defines the maintenance mode settings.
+type MaintenanceSpec struct {
+	// Serve a maintenance page instead of the app and stop the background workers. Redis and the
+	// database are left running.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Text shown on the maintenance page.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// HibernationSpec defines when a dev or QA instance is scaled down to save money. Set either
+// AwakeWindows or WakeSchedule and SleepSchedule.
+type HibernationSpec struct {
+	// Windows when the instance is awake, like "Mon-Fri 08:00-20:00". It sleeps outside all of them.
+	// +optional
+	AwakeWindows []string `json:"awakeWindows,omitempty"`
+	// Cron expression for when to wake up, like "0 8 * * 1-5".
+	// +optional
+	WakeSchedule string `json:"wakeSchedule,omitempty"`
+	// Cron expression for when to go to sleep, like "0 20 * * 1-5".
+	// +optional
+	SleepSchedule string `json:"sleepSchedule,omitempty"`
+	// Time zone name for the schedule. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// DeployFreeze is a named period when no new versions are rolled out, like a holiday freeze.
+type DeployFreeze struct {
+	// Shown in the status while the freeze holds a deploy.
+	Name string `json:"name"`
+	// When the freeze starts.
+	// Real type = time.Time, see WaitStatus.
+	Start string `json:"start"`
+	// When the freeze ends.
+	// Real type = time.Time, see WaitStatus.
+	End string `json:"end"`
+}
+
+// DeployWindowSpec defines when new versions may start rolling out.
+type DeployWindowSpec struct {
+	// Windows when deploys may start, like "Mon-Thu 09:00-16:00", in the same format as
+	// HibernationSpec.AwakeWindows. Any time outside a freeze if empty.
+	// +optional
+	AllowedWindows []string `json:"allowedWindows,omitempty"`
+	// Time zone name for the windows. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Periods when no deploys may start, even inside a window.
+	// +optional
+	Freezes []DeployFreeze `json:"freezes,omitempty"`
+}
+
 // SummonPlatformSpec defines the desired state of SummonPlatform
 type SummonPlatformSpec struct {
 	// Important: Run "make" to regenerate code after modifying this file
@@ -280,6 +378,27 @@ type SummonPlatformSpec struct {
 	// To be removed when support for the 1540 fixup is removed in summon.
 	// +optional
 	NoCore1540Fixup bool `json:"noCore1540Fixup,omitempty"`
+	// Patches applied to rendered objects, for one-off changes that don't belong in the templates.
+	// +optional
+	Overlays []overlays.Patch `json:"overlays,omitempty"`
+	// Canary rollout settings.
+	// +optional
+	Canary CanarySpec `json:"canary,omitempty"`
+	// Automatic rollback settings.
+	// +optional
+	Rollback RollbackSpec `json:"rollback,omitempty"`
+	// Maintenance mode settings.
+	// +optional
+	Maintenance MaintenanceSpec `json:"maintenance,omitempty"`
+	// Sleep schedule for dev and QA instances. Overrides the namespace's
+	// summon.ridecell.io/hibernation annotation.
+	// +optional
+	Hibernation *HibernationSpec `json:"hibernation,omitempty"`
+	// When a new Spec.Version may start rolling out. Outside of it the instance stays on the
+	// current version with a DeployHeld status, unless the summon.ridecell.io/deploy-override
+	// annotation is set to the new version.
+	// +optional
+	DeployWindow *DeployWindowSpec `json:"deployWindow,omitempty"`
 }
 
 // NotificationStatus defines the observed state of Notifications
@@ -299,6 +418,17 @@ type NotificationStatus struct {
 	// The last version notification posted for HwAux deploy.
 	// +optional
 	HwAuxVersion string `json:"hwAuxVersion,omitempty"`
+	// The last canary version and phase a notification was posted for.
+	// +optional
+	CanaryVersion string `json:"canaryVersion,omitempty"`
+	// +optional
+	CanaryPhase string `json:"canaryPhase,omitempty"`
+	// The last failed version a rollback notification was posted for.
+	// +optional
+	RolledBackVersion string `json:"rolledBackVersion,omitempty"`
+	// The start time of the last maintenance window a notification was posted for.
+	// +optional
+	MaintenanceStarted string `json:"maintenanceStarted,omitempty"`
 }
 
 // MIVStatus is the output information for the Manual Identity Verification system.
@@ -316,6 +446,86 @@ type WaitStatus struct {
 	Until string `json:"until,omitempty"`
 }
 
+// CanaryStatus is the output information for canary rollouts.
+type CanaryStatus struct {
+	// Current phase of the canary, one of Progressing, Baking, Promoted or Aborted.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// The version running on the canary pods.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// The version the rest of the pods were running when the canary started.
+	// +optional
+	StableVersion string `json:"stableVersion,omitempty"`
+	// When the canary pods became ready and baking started.
+	// Real type = time.Time, see WaitStatus.
+	// +optional
+	BakeStarted string `json:"bakeStarted,omitempty"`
+	// The last error rate seen for the canary pods.
+	// +optional
+	ErrorRate string `json:"errorRate,omitempty"`
+	// Details of the current phase, such as why the canary was aborted.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RollbackStatus is the output information for automatic rollbacks.
+type RollbackStatus struct {
+	// The version being deployed.
+	// +optional
+	DeployVersion string `json:"deployVersion,omitempty"`
+	// When the Deployments started rolling out DeployVersion, after its migrations. The deploy
+	// deadline counts from here.
+	// Real type = time.Time, see WaitStatus.
+	// +optional
+	DeployStarted string `json:"deployStarted,omitempty"`
+	// The version which failed and was rolled back.
+	// +optional
+	FailedVersion string `json:"failedVersion,omitempty"`
+	// The version the Deployments were rolled back to.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Why the rollback happened.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// MaintenanceStatus is the output information for maintenance mode.
+type MaintenanceStatus struct {
+	// When maintenance mode was turned on, empty when it is off.
+	// Real type = time.Time, see WaitStatus.
+	// +optional
+	Started string `json:"started,omitempty"`
+	// Who turned maintenance mode on, from the summon.ridecell.io/maintenance-requested-by annotation.
+	// +optional
+	RequestedBy string `json:"requestedBy,omitempty"`
+}
+
+// HibernationStatus is the output information for scheduled hibernation.
+type HibernationStatus struct {
+	// Whether the instance is scaled down for the night or weekend.
+	// +optional
+	Asleep bool `json:"asleep,omitempty"`
+	// When a sleeping instance will next wake up.
+	// Real type = time.Time, see WaitStatus.
+	// +optional
+	NextWake string `json:"nextWake,omitempty"`
+}
+
+// DeployHoldStatus is the output information for deploys held outside the deploy window.
+type DeployHoldStatus struct {
+	// The new version waiting to roll out, empty when nothing is held.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Why the version is held, like a freeze name.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// When the version will start rolling out, empty if the windows never open.
+	// Real type = time.Time, see WaitStatus.
+	// +optional
+	Until string `json:"until,omitempty"`
+}
+
 // SummonPlatformStatus defines the observed state of SummonPlatform
 type SummonPlatformStatus struct {
 	// Overall object status
@@ -341,6 +551,9 @@ type SummonPlatformStatus struct {
 	// Previous version for which a backup was made.
 	// +optional
 	BackupVersion string `json:"backupVersion,omitempty"`
+	// Last version for which every Deployment became ready.
+	// +optional
+	LastReadyVersion string `json:"lastReadyVersion,omitempty"`
 	// Spec for Notification
 	// +optional
 	Notification NotificationStatus `json:"notification,omitempty"`
@@ -350,6 +563,34 @@ type SummonPlatformStatus struct {
 	// Status for deployment Waits
 	// +optional
 	Wait WaitStatus `json:"wait,omitempty"`
+	// Status of the canary rollout for the latest version.
+	// +optional
+	Canary CanaryStatus `json:"canary,omitempty"`
+	// Status of automatic rollbacks for the latest version.
+	// +optional
+	Rollback RollbackStatus `json:"rollback,omitempty"`
+	// Status of maintenance mode.
+	// +optional
+	Maintenance MaintenanceStatus `json:"maintenance,omitempty"`
+	// Status of scheduled hibernation.
+	// +optional
+	Hibernation HibernationStatus `json:"hibernation,omitempty"`
+	// Status of a version held outside the deploy window.
+	// +optional
+	DeployHold DeployHoldStatus `json:"deployHold,omitempty"`
+
+	// Detailed status conditions.
+	// +optional
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
+	// Outcome of each entry in Spec.Overlays, in the same order.
+	// +optional
+	Overlays []overlays.PatchStatus `json:"overlays,omitempty"`
+	// Components skipped by the ridecell.io/skip-components annotation on the last reconcile.
+	// +optional
+	SkippedComponents []string `json:"skippedComponents,omitempty"`
+	// Recent changes to Status, oldest first.
+	// +optional
+	History []transitions.Transition `json:"history,omitempty"`
 }
 
 // +genclient
@@ -120,7 +120,7 @@ var _ = Describe("SummonPlatform types", func() {
 			Expect(fetched.Spec.Config["foo"].String).To(PointTo(Equal("bar")))
 		})
 
-		It("can parse unstructured float data", func() {
+		It("can parse unstructured int data", func() {
 			c := helpers.Client
 			obj := &unstructured.Unstructured{
 				Object: map[string]interface{}{
@@ -148,7 +148,8 @@ var _ = Describe("SummonPlatform types", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fetched.Spec.Config).To(HaveKey("foo"))
 			Expect(fetched.Spec.Config["foo"].Bool).To(BeNil())
-			Expect(fetched.Spec.Config["foo"].Float).To(PointTo(BeEquivalentTo(1234)))
+			Expect(fetched.Spec.Config["foo"].Int).To(PointTo(BeEquivalentTo(1234)))
+			Expect(fetched.Spec.Config["foo"].Float).To(BeNil())
 			Expect(fetched.Spec.Config["foo"].String).To(BeNil())
 		})
 
@@ -231,10 +232,139 @@ var _ = Describe("SummonPlatform types", func() {
 			Expect(fetched.Spec.Config["GOOGLE_ANALYTICS_ID"].String).To(PointTo(Equal("UA-2345")))
 			Expect(fetched.Spec.Config).To(HaveKey("SESSION_COOKIE_AGE"))
 			Expect(fetched.Spec.Config["SESSION_COOKIE_AGE"].Bool).To(BeNil())
-			Expect(fetched.Spec.Config["SESSION_COOKIE_AGE"].Float).To(PointTo(BeEquivalentTo(1)))
+			Expect(fetched.Spec.Config["SESSION_COOKIE_AGE"].Int).To(PointTo(BeEquivalentTo(1)))
+			Expect(fetched.Spec.Config["SESSION_COOKIE_AGE"].Float).To(BeNil())
 			Expect(fetched.Spec.Config["SESSION_COOKIE_AGE"].String).To(BeNil())
 			Expect(fetched.Spec.Backup.TTL.Duration).To(Equal(time.Minute * 5))
 			Expect(*fetched.Spec.Backup.WaitUntilReady).To(BeTrue())
 		})
+		It("can parse unstructured float data", func() {
+			c := helpers.Client
+			obj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "summon.ridecell.io/v1beta1",
+					"kind":       "SummonPlatform",
+					"metadata": map[string]interface{}{
+						"name":      "foo",
+						"namespace": helpers.Namespace,
+					},
+					"spec": map[string]interface{}{
+						"version": "1",
+						"secrets": []string{"a"},
+						"config": map[string]interface{}{
+							"foo": 12.5,
+						},
+					},
+				},
+			}
+
+			err := c.Create(context.TODO(), obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			fetched := &summonv1beta1.SummonPlatform{}
+			err = c.Get(context.TODO(), types.NamespacedName{Name: "foo", Namespace: helpers.Namespace}, fetched)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fetched.Spec.Config).To(HaveKey("foo"))
+			Expect(fetched.Spec.Config["foo"].Int).To(BeNil())
+			Expect(fetched.Spec.Config["foo"].Float).To(PointTo(Equal(12.5)))
+		})
+
+		It("can parse unstructured list and map data", func() {
+			c := helpers.Client
+			obj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "summon.ridecell.io/v1beta1",
+					"kind":       "SummonPlatform",
+					"metadata": map[string]interface{}{
+						"name":      "foo",
+						"namespace": helpers.Namespace,
+					},
+					"spec": map[string]interface{}{
+						"version": "1",
+						"secrets": []string{"a"},
+						"config": map[string]interface{}{
+							"hosts": []interface{}{"a.example.com", "b.example.com"},
+							"cache": map[string]interface{}{
+								"timeout": 60,
+								"options": map[string]interface{}{"enabled": true},
+							},
+						},
+					},
+				},
+			}
+
+			err := c.Create(context.TODO(), obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			fetched := &summonv1beta1.SummonPlatform{}
+			err = c.Get(context.TODO(), types.NamespacedName{Name: "foo", Namespace: helpers.Namespace}, fetched)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fetched.Spec.Config).To(HaveKey("hosts"))
+			Expect(fetched.Spec.Config["hosts"].ToNilInterface()).To(Equal([]interface{}{"a.example.com", "b.example.com"}))
+			Expect(fetched.Spec.Config).To(HaveKey("cache"))
+			cache := fetched.Spec.Config["cache"]
+			Expect(cache.Map).To(HaveKey("timeout"))
+			Expect(cache.Map["timeout"].Int).To(PointTo(BeEquivalentTo(60)))
+			Expect(cache.Map["options"].Map["enabled"].Bool).To(PointTo(Equal(true)))
+		})
+
+		It("can parse the struct form written by older versions", func() {
+			c := helpers.Client
+			obj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "summon.ridecell.io/v1beta1",
+					"kind":       "SummonPlatform",
+					"metadata": map[string]interface{}{
+						"name":      "foo",
+						"namespace": helpers.Namespace,
+					},
+					"spec": map[string]interface{}{
+						"version": "1",
+						"secrets": []string{"a"},
+						"config": map[string]interface{}{
+							"foo": map[string]interface{}{"float": 60},
+							"bar": map[string]interface{}{"string": "baz"},
+						},
+					},
+				},
+			}
+
+			err := c.Create(context.TODO(), obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			fetched := &summonv1beta1.SummonPlatform{}
+			err = c.Get(context.TODO(), types.NamespacedName{Name: "foo", Namespace: helpers.Namespace}, fetched)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fetched.Spec.Config["foo"].Float).To(PointTo(BeEquivalentTo(60)))
+			Expect(fetched.Spec.Config["foo"].Int).To(BeNil())
+			Expect(fetched.Spec.Config["bar"].String).To(PointTo(Equal("baz")))
+			Expect(fetched.Spec.Config["bar"].Map).To(BeNil())
+		})
+
+		It("round-trips nested values through the struct form", func() {
+			c := helpers.Client
+			timeout := int64(60)
+			host := "a.example.com"
+			instance := &summonv1beta1.SummonPlatform{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: helpers.Namespace},
+				Spec: summonv1beta1.SummonPlatformSpec{
+					Version: "1",
+					Secrets: []string{"a"},
+					Config: map[string]summonv1beta1.ConfigValue{
+						"cache": {Map: map[string]summonv1beta1.ConfigValue{
+							"timeout": {Int: &timeout},
+							"hosts":   {List: []summonv1beta1.ConfigValue{{String: &host}}},
+						}},
+					},
+				},
+			}
+			err := c.Create(context.TODO(), instance)
+			Expect(err).NotTo(HaveOccurred())
+
+			fetched := &summonv1beta1.SummonPlatform{}
+			err = c.Get(context.TODO(), types.NamespacedName{Name: "foo", Namespace: helpers.Namespace}, fetched)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fetched.Spec.Config).To(Equal(instance.Spec.Config))
+		})
 	})
 })
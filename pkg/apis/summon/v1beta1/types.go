@@ -24,4 +24,31 @@ const (
 	StatusReady           = "Ready"
 	StatusError           = "Error"
 	StatusPostMigrateWait = "PostMigrateWait"
+	StatusCanary          = "Canary"
+	StatusCanaryAborted   = "CanaryAborted"
+	StatusRolledBack      = "RolledBack"
+	StatusDeployHeld      = "DeployHeld"
+)
+
+// Phases of a canary rollout, see CanaryStatus.
+const (
+	CanaryPhaseProgressing = "Progressing"
+	CanaryPhaseBaking      = "Baking"
+	CanaryPhasePromoted    = "Promoted"
+	CanaryPhaseAborted     = "Aborted"
+)
+
+// Condition types for SummonPlatform, each owned by a single component.
+const (
+	ConditionDatabaseReady        = "DatabaseReady"
+	ConditionBackupTaken          = "BackupTaken"
+	ConditionMigrationsComplete   = "MigrationsComplete"
+	ConditionDeploymentsAvailable = "DeploymentsAvailable"
+	ConditionSecretsValid         = "SecretsValid"
+)
+
+// Annotations on SummonPlatform objects which are read by more than just the controller.
+const (
+	// An RFC3339 time to keep an instance awake until, regardless of its hibernation schedule.
+	WakeUntilAnnotation = "summon.ridecell.io/wake-until"
 )
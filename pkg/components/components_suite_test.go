@@ -0,0 +1,90 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+)
+
+var instance *summonv1beta1.SummonPlatform
+
+func TestComponents(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	err := apis.AddToScheme(scheme.Scheme)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	ginkgo.RunSpecs(t, "Components Suite @unit")
+}
+
+var _ = ginkgo.BeforeEach(func() {
+	instance = &summonv1beta1.SummonPlatform{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec:       summonv1beta1.SummonPlatformSpec{Version: "1.2.3"},
+	}
+})
+
+// A component for exercising the reconciler. The template path is only there to give each one a
+// distinct name, like "test(a)".
+type testComponent struct {
+	templatePath string
+	notReady     bool
+	reconcile    func(*components.ComponentContext) (components.Result, error)
+	calls        int32
+}
+
+func newTestComponent(name string, reconcile func(*components.ComponentContext) (components.Result, error)) *testComponent {
+	return &testComponent{templatePath: name, reconcile: reconcile}
+}
+
+func (_ *testComponent) WatchTypes() []runtime.Object {
+	return []runtime.Object{}
+}
+
+func (comp *testComponent) IsReconcilable(_ *components.ComponentContext) bool {
+	return !comp.notReady
+}
+
+func (comp *testComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	atomic.AddInt32(&comp.calls, 1)
+	if comp.reconcile == nil {
+		return components.Result{}, nil
+	}
+	return comp.reconcile(ctx)
+}
+
+func (comp *testComponent) Calls() int {
+	return int(atomic.LoadInt32(&comp.calls))
+}
+
+// A StatusModifier which appends to the status message, to check the order modifiers ran in.
+func appendMessage(s string) components.StatusModifier {
+	return func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Message += s
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+)
+
+// Condition set by the reconciler itself after every reconcile.
+const ConditionReconciled = "Reconciled"
+
+const (
+	ReasonReconcileSuccess = "ReconcileSuccess"
+	ReasonReconcileError   = "ReconcileError"
+)
+
+// Set a single condition on a top object. The observed generation is filled in from the object
+// and the transition time is only updated when the condition's status changes.
+func SetCondition(obj runtime.Object, conditionType string, status conditions.ConditionStatus, reason, message string) {
+	statuser := obj.(Statuser)
+	statuser.SetConditions(conditions.Set(statuser.GetConditions(), conditions.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: obj.(metav1.Object).GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	}))
+}
+
+// Helper function for use as a StatusModifier which just sets a single condition.
+func ConditionModifier(conditionType string, status conditions.ConditionStatus, reason, message string) StatusModifier {
+	return func(obj runtime.Object) error {
+		SetCondition(obj, conditionType, status, reason, message)
+		return nil
+	}
+}
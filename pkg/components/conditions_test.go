@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+)
+
+var _ = Describe("Conditions", func() {
+	It("sets a condition with the observed generation", func() {
+		instance.Generation = 3
+		components.SetCondition(instance, "Ready", conditions.ConditionFalse, "Waiting", "waiting on things")
+		cond := conditions.Find(instance.Status.Conditions, "Ready")
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(conditions.ConditionFalse))
+		Expect(cond.ObservedGeneration).To(Equal(int64(3)))
+		Expect(cond.Reason).To(Equal("Waiting"))
+		Expect(cond.Message).To(Equal("waiting on things"))
+		Expect(cond.LastTransitionTime).ToNot(BeEmpty())
+	})
+
+	It("keeps the transition time while the status is unchanged", func() {
+		instance.Status.Conditions = []conditions.Condition{{Type: "Ready", Status: conditions.ConditionFalse, LastTransitionTime: "2020-01-01T00:00:00Z", Reason: "Waiting"}}
+		instance.Generation = 4
+		components.SetCondition(instance, "Ready", conditions.ConditionFalse, "StillWaiting", "still waiting")
+		Expect(instance.Status.Conditions).To(HaveLen(1))
+		cond := instance.Status.Conditions[0]
+		Expect(cond.LastTransitionTime).To(Equal("2020-01-01T00:00:00Z"))
+		Expect(cond.ObservedGeneration).To(Equal(int64(4)))
+		Expect(cond.Reason).To(Equal("StillWaiting"))
+		Expect(cond.Message).To(Equal("still waiting"))
+	})
+
+	It("updates an existing condition from a status modifier", func() {
+		instance.Status.Conditions = []conditions.Condition{{Type: "Ready", Status: conditions.ConditionFalse, LastTransitionTime: "2020-01-01T00:00:00Z", Reason: "Waiting"}}
+		modifier := components.ConditionModifier("Ready", conditions.ConditionTrue, "Done", "all done")
+		Expect(modifier(instance)).To(Succeed())
+		Expect(instance.Status.Conditions).To(HaveLen(1))
+		cond := instance.Status.Conditions[0]
+		Expect(cond.Status).To(Equal(conditions.ConditionTrue))
+		Expect(cond.LastTransitionTime).ToNot(Equal("2020-01-01T00:00:00Z"))
+		Expect(cond.Reason).To(Equal("Done"))
+		Expect(cond.Message).To(Equal("all done"))
+	})
+})
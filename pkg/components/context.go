@@ -17,21 +17,38 @@ limitations under the License.
 package components
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	// "github.com/golang/glog"
+	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	"github.com/Ridecell/ridecell-operator/pkg/logging"
 	"github.com/Ridecell/ridecell-operator/pkg/templates"
 )
 
+// Deadlines for reconciles. These are vars so the manager can override them from flags.
+var (
+	// A whole reconcile of one top object, including every component.
+	ReconcileTimeout = 10 * time.Minute
+	// A single component's Reconcile or ReconcileError, unless it implements Timeouter.
+	ComponentTimeout = 2 * time.Minute
+	// The status write at the end of a reconcile. Kept separate so a reconcile which ran out of
+	// time can still report why.
+	StatusTimeout = 30 * time.Second
+)
+
 func (ctx *ComponentContext) GetTemplate(path string, extraData map[string]interface{}) (runtime.Object, error) {
 	if ctx.templates == nil {
 		return nil, fmt.Errorf("no templates loaded for this reconciler")
@@ -43,7 +60,12 @@ func (ctx *ComponentContext) GetTemplate(path string, extraData map[string]inter
 }
 
 func (ctx *ComponentContext) CreateOrUpdate(path string, extraData map[string]interface{}, mutateFn func(runtime.Object, runtime.Object) error) (Result, controllerutil.OperationResult, error) {
-	target, err := ctx.GetTemplate(path, extraData)
+	target, err := ctx.renderTemplate(path, extraData)
+	if err == templates.ErrEmptyTemplate {
+		// Nothing to create, anything this template made before will be pruned.
+		ctx.recordRendered(path, nil)
+		return Result{}, controllerutil.OperationResultNone, nil
+	}
 	if err != nil {
 		return Result{}, controllerutil.OperationResultNone, err
 	}
@@ -62,16 +84,59 @@ func (ctx *ComponentContext) CreateOrUpdate(path string, extraData map[string]in
 		// Sync the metadata fields.
 		targetMeta := target.(metav1.ObjectMetaAccessor).GetObjectMeta().(*metav1.ObjectMeta)
 		existingMeta := existing.(metav1.ObjectMetaAccessor).GetObjectMeta().(*metav1.ObjectMeta)
-		return ReconcileMeta(targetMeta, existingMeta)
+		err = ReconcileMeta(targetMeta, existingMeta)
+		if err != nil {
+			return err
+		}
+		// Mark it for pruning later.
+		if ctx.inventory != nil {
+			setPruneMeta(ctx.Top.(metav1.Object), existingMeta, path)
+		}
+		return nil
 	})
 	if err != nil {
 		return Result{Requeue: true}, op, err
 	}
+	ctx.recordRendered(path, target)
 
 	return Result{}, op, nil
 }
 
-// Make a copy of a context with new templates. Used mostly for shared components.
+// Emit an Event on the top object. Does nothing if no recorder is configured.
+func (ctx *ComponentContext) Event(eventType, reason, message string) {
+	if ctx.Recorder == nil {
+		return
+	}
+	ctx.Recorder.Event(ctx.Top, eventType, reason, message)
+}
+
+// Emit a formatted Event on the top object. Does nothing if no recorder is configured.
+func (ctx *ComponentContext) Eventf(eventType, reason, messageFmt string, args ...interface{}) {
+	ctx.Event(eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// Get the logger for this reconcile. Inside a reconciler every message is tagged with the
+// reconcile ID, the top object and the running component. Contexts built by hand fall back to
+// the root logger.
+func (ctx *ComponentContext) Logger() logr.Logger {
+	if ctx.logger == nil {
+		return logging.Log
+	}
+	return ctx.logger
+}
+
+// Get the operator settings. Inside a reconciler these are fixed for the whole reconcile, even if
+// the OperatorConfig is reloaded part way through. Contexts built by hand use whatever is current.
+func (ctx *ComponentContext) Config() *config.Config {
+	if ctx == nil || ctx.config == nil {
+		return config.Current()
+	}
+	return ctx.config
+}
+
+// Make a copy of a context with new templates. Used mostly for shared components. Objects
+// created from other templates are not tracked for pruning, since their template paths can
+// collide with the reconciler's own.
 func (ctx *ComponentContext) WithTemplates(templates http.FileSystem) *ComponentContext {
 	return &ComponentContext{
 		Client:    ctx.Client,
@@ -79,6 +144,36 @@ func (ctx *ComponentContext) WithTemplates(templates http.FileSystem) *Component
 		Context:   ctx.Context,
 		Top:       ctx.Top,
 		Scheme:    ctx.Scheme,
+		Recorder:  ctx.Recorder,
+		logger:    ctx.logger,
+		config:    ctx.config,
+	}
+}
+
+// Make a copy of a context for running one component, with the logger tagged with the
+// component's name and a Context limited to the component's timeout. Everything else is shared
+// with the original context. The cancel func must be called when the component returns.
+func (ctx *ComponentContext) forComponent(comp Component) (*ComponentContext, context.CancelFunc) {
+	timeout := ComponentTimeout
+	timeouter, ok := comp.(Timeouter)
+	if ok {
+		timeout = timeouter.Timeout()
+	}
+	compCtx := *ctx
+	compCtx.logger = ctx.Logger().WithValues("component", componentName(comp))
+	var cancel context.CancelFunc
+	compCtx.Context, cancel = context.WithTimeout(ctx.Context, timeout)
+	return &compCtx, cancel
+}
+
+// Create a standalone context outside of a reconciler, used by tools like cmd/plan.
+func NewContext(top runtime.Object, c client.Client, scheme *runtime.Scheme, templates http.FileSystem) *ComponentContext {
+	return &ComponentContext{
+		Client:    c,
+		templates: templates,
+		Context:   context.Background(),
+		Top:       top,
+		Scheme:    scheme,
 	}
 }
 
@@ -87,8 +182,10 @@ func NewTestContext(top runtime.Object, templates http.FileSystem) *ComponentCon
 	// This method is ugly and I don't like it. I should rebuild this whole subsytem around interfaces and have an explicit fake for it.
 	return &ComponentContext{
 		Top:       top,
+		Context:   context.Background(),
 		Client:    fake.NewFakeClient(top),
 		Scheme:    scheme.Scheme,
+		Recorder:  record.NewFakeRecorder(100),
 		templates: templates,
 	}
 }
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// Error codes the AWS APIs use when they are throttling us.
+var awsThrottlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"RequestLimitExceeded":                   true,
+	"TooManyRequestsException":               true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"ProvisionedThroughputExceededException": true,
+}
+
+// Classify errors from external APIs which the component returned without a class. Throttling
+// from AWS is retried after a delay instead of backing off like other failures. Anything else
+// is returned unchanged.
+func classifyError(err error) error {
+	if err == nil || errors.ClassOf(err) != errors.ClassUnknown {
+		return err
+	}
+	aerr, ok := errors.Cause(err).(awserr.Error)
+	if ok && awsThrottlingCodes[aerr.Code()] {
+		return errors.RateLimited(err, 0)
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// Internals exposed to the components_test package.
+
+var BuildStages = buildStages
+var PlanDiff = planDiff
+var MaxConcurrentReconcilesFor = maxConcurrentReconciles
+
+// A manager which only knows how to inject the test client, enough for newContext.
+type testManager struct {
+	manager.Manager
+	client client.Client
+}
+
+func (m *testManager) SetFields(i interface{}) error {
+	c, ok := i.(inject.Client)
+	if ok {
+		err := c.InjectClient(m.client)
+		if err != nil {
+			return err
+		}
+	}
+	s, ok := i.(inject.Scheme)
+	if ok {
+		err := s.InjectScheme(scheme.Scheme)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Build a reconciler around a client without a manager or controller, so nothing is watched.
+func NewTestReconciler(name string, top runtime.Object, c client.Client, components []Component) (*componentReconciler, *record.FakeRecorder, error) {
+	components, stages, err := buildStages(components)
+	if err != nil {
+		return nil, nil, err
+	}
+	recorder := record.NewFakeRecorder(100)
+	return &componentReconciler{
+		name:       name,
+		top:        top,
+		components: components,
+		stages:     stages,
+		client:     c,
+		manager:    &testManager{client: c},
+		recorder:   recorder,
+	}, recorder, nil
+}
+
+// Track what gets rendered through a context for pruning, like a reconcile does.
+func (ctx *ComponentContext) TrackRendered() {
+	ctx.inventory = newInventory()
+}
+
+func (ctx *ComponentContext) Prune(pruneTypes []runtime.Object, templateExists func(string) bool) ([]runtime.Object, error) {
+	return ctx.prune(pruneTypes, templateExists)
+}
+
+// Run the components once against a context, without loading or saving the top object.
+func (cr *componentReconciler) ReconcileComponents(ctx *ComponentContext) (reconcile.Result, error) {
+	res, err := cr.reconcileComponents(ctx, &skipSet{skipped: map[int]bool{}})
+	return res.result, err
+}
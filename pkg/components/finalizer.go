@@ -0,0 +1,188 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// Annotation to let an object be deleted without cleaning up its external resources.
+const SkipFinalizerAnnotation = "ridecell.io/skip-finalizer"
+
+// Condition set on a top object while a Finalizer is cleaning up after it.
+const ConditionDeleting = "Deleting"
+
+const (
+	ReasonCleanupInProgress = "CleanupInProgress"
+	ReasonCleanupFailed     = "CleanupFailed"
+)
+
+// Default for Finalizer.PollInterval.
+var FinalizerPollInterval = 30 * time.Second
+
+// Deletes the external resources behind a top object. Return a Result with Requeue or
+// RequeueAfter set if the deletion has been started but isn't finished yet, and Cleanup will be
+// called again later.
+type CleanupFunc func(*ComponentContext) (Result, error)
+
+// Manages one finalizer for a component which owns resources outside of Kubernetes. The
+// finalizer is added to live objects, and on deletion Cleanup is run until it reports it is
+// done, then the finalizer is removed.
+//
+// Components with other work to do should call Handle at the top of their Reconcile. A
+// Finalizer is also a Component itself, for controllers where there is nothing else to do.
+type Finalizer struct {
+	// Name of the finalizer string on the object, like "s3bucket.finalizer".
+	Name string
+	// Deletes the external resources.
+	Cleanup CleanupFunc
+	// Deadline for a single call to Cleanup. Zero means whatever is left of the component's.
+	Timeout time.Duration
+	// How long to wait before calling Cleanup again when it asked for an immediate requeue.
+	PollInterval time.Duration
+}
+
+func NewFinalizer(name string, cleanup CleanupFunc) *Finalizer {
+	return &Finalizer{Name: name, Cleanup: cleanup, PollInterval: FinalizerPollInterval}
+}
+
+func (_ *Finalizer) WatchTypes() []runtime.Object {
+	return []runtime.Object{}
+}
+
+func (_ *Finalizer) IsReconcilable(_ *ComponentContext) bool {
+	return true
+}
+
+func (f *Finalizer) Reconcile(ctx *ComponentContext) (Result, error) {
+	res, _, err := f.Handle(ctx)
+	return res, err
+}
+
+// Add or process the finalizer. When deleting is true the top object is being deleted and the
+// calling component should return the result and error straight away.
+func (f *Finalizer) Handle(ctx *ComponentContext) (res Result, deleting bool, err error) {
+	instance := ctx.Top.(metav1.Object)
+
+	if instance.GetDeletionTimestamp().IsZero() {
+		if !helpers.ContainsFinalizer(f.Name, ctx.Top) {
+			err := f.updateFinalizers(ctx, helpers.AppendFinalizer)
+			if err != nil {
+				return Result{}, false, errors.Wrapf(err, "failed to add finalizer %s", f.Name)
+			}
+		}
+		return Result{}, false, nil
+	}
+
+	if !helpers.ContainsFinalizer(f.Name, ctx.Top) {
+		// Already cleaned up, or never got as far as adding it.
+		return Result{}, true, nil
+	}
+
+	if instance.GetAnnotations()[SkipFinalizerAnnotation] == "true" || !ctx.Config().Enabled(config.FeatureFinalizers) {
+		ctx.Logger().Info("Skipping finalizer cleanup", "finalizer", f.Name)
+	} else {
+		res, err = f.cleanup(ctx)
+		if err != nil {
+			return Result{StatusModifier: ConditionModifier(ConditionDeleting, conditions.ConditionTrue, ReasonCleanupFailed, err.Error())}, true, err
+		}
+		if res.Requeue || res.RequeueAfter > 0 {
+			if res.RequeueAfter == 0 {
+				res.RequeueAfter = f.PollInterval
+			}
+			res.Requeue = false
+			res.StatusModifier = chainModifiers(res.StatusModifier, ConditionModifier(ConditionDeleting, conditions.ConditionTrue, ReasonCleanupInProgress, fmt.Sprintf("waiting for %s cleanup", f.Name)))
+			return res, true, nil
+		}
+	}
+
+	// All operations complete, remove finalizer.
+	err = f.updateFinalizers(ctx, helpers.RemoveFinalizer)
+	if err != nil {
+		return Result{}, true, errors.Wrapf(err, "failed to remove finalizer %s", f.Name)
+	}
+	return Result{}, true, nil
+}
+
+// Run one cleanup attempt with its own deadline. Timeouts are retried with backoff like any
+// other transient failure.
+func (f *Finalizer) cleanup(ctx *ComponentContext) (Result, error) {
+	cleanupCtx := *ctx
+	if f.Timeout > 0 {
+		var cancel context.CancelFunc
+		cleanupCtx.Context, cancel = context.WithTimeout(ctx.Context, f.Timeout)
+		defer cancel()
+	}
+	res, err := f.Cleanup(&cleanupCtx)
+	if err != nil && cleanupCtx.Context.Err() == context.DeadlineExceeded {
+		return res, errors.Transient(errors.Wrapf(err, "%s cleanup timed out", f.Name))
+	}
+	return res, err
+}
+
+// Add or remove our finalizer on a fresh copy of the top object. Several components in one
+// controller can each have a finalizer on the same object, so this can't just save ctx.Top.
+func (f *Finalizer) updateFinalizers(ctx *ComponentContext, change func(string, runtime.Object) []string) error {
+	instance := ctx.Top.(metav1.Object)
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		fresh := ctx.Top.DeepCopyObject()
+		err := ctx.Get(ctx.Context, types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}, fresh)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				// Already gone, nothing left to hold up.
+				return nil
+			}
+			return err
+		}
+		freshMeta := fresh.(metav1.Object)
+		freshMeta.SetFinalizers(change(f.Name, fresh))
+		err = ctx.Update(ctx.Context, fresh)
+		if err != nil {
+			return err
+		}
+		instance.SetFinalizers(freshMeta.GetFinalizers())
+		instance.SetResourceVersion(freshMeta.GetResourceVersion())
+		return nil
+	})
+}
+
+// Combine two status modifiers, either of which may be nil.
+func chainModifiers(first, second StatusModifier) StatusModifier {
+	if first == nil {
+		return second
+	}
+	return func(obj runtime.Object) error {
+		err := first(obj)
+		if err != nil {
+			return err
+		}
+		return second(obj)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// A client whose first few updates fail with a conflict, like when something else saved the object first.
+type conflictClient struct {
+	client.Client
+	conflicts int
+	updates   int
+}
+
+func (c *conflictClient) Update(ctx context.Context, obj runtime.Object) error {
+	c.updates++
+	if c.conflicts > 0 {
+		c.conflicts--
+		return kerrors.NewConflict(schema.GroupResource{Group: "summon.ridecell.io", Resource: "summonplatforms"}, "foo", errors.New("changed"))
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+var _ = Describe("Finalizer", func() {
+	var finalizer *components.Finalizer
+	var ctx *components.ComponentContext
+	var cleanups int
+	var cleanup func(*components.ComponentContext) (components.Result, error)
+
+	BeforeEach(func() {
+		cleanups = 0
+		cleanup = func(_ *components.ComponentContext) (components.Result, error) {
+			return components.Result{}, nil
+		}
+		finalizer = components.NewFinalizer("test.finalizer", func(ctx *components.ComponentContext) (components.Result, error) {
+			cleanups++
+			return cleanup(ctx)
+		})
+		config.SetCurrent(config.New(nil, nil, map[string]bool{config.FeatureFinalizers: true}))
+	})
+
+	AfterEach(func() {
+		config.SetCurrent(nil)
+	})
+
+	// Point the context at a client holding the current instance.
+	setup := func() {
+		ctx = components.NewTestContext(instance, nil)
+	}
+
+	deleteInstance := func() {
+		now := metav1.Now()
+		instance.DeletionTimestamp = &now
+		instance.Finalizers = []string{"test.finalizer"}
+		setup()
+	}
+
+	storedFinalizers := func() []string {
+		fetched := &summonv1beta1.SummonPlatform{}
+		err := ctx.Get(context.Background(), types.NamespacedName{Name: "foo", Namespace: "default"}, fetched)
+		Expect(err).ToNot(HaveOccurred())
+		return fetched.Finalizers
+	}
+
+	It("adds the finalizer to a live object", func() {
+		setup()
+		res, deleting, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeFalse())
+		Expect(res).To(Equal(components.Result{}))
+		Expect(instance.Finalizers).To(ConsistOf("test.finalizer"))
+		Expect(storedFinalizers()).To(ConsistOf("test.finalizer"))
+		Expect(cleanups).To(Equal(0))
+	})
+
+	It("retries adding the finalizer on a conflict", func() {
+		setup()
+		c := &conflictClient{Client: ctx.Client, conflicts: 1}
+		ctx.Client = c
+		_, _, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.updates).To(Equal(2))
+		Expect(storedFinalizers()).To(ConsistOf("test.finalizer"))
+	})
+
+	It("keeps other finalizers", func() {
+		instance.Finalizers = []string{"other.finalizer"}
+		setup()
+		_, _, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(storedFinalizers()).To(ConsistOf("other.finalizer", "test.finalizer"))
+	})
+
+	It("cleans up and removes the finalizer on deletion", func() {
+		deleteInstance()
+		res, deleting, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(res).To(Equal(components.Result{}))
+		Expect(cleanups).To(Equal(1))
+		Expect(instance.Finalizers).To(BeEmpty())
+		Expect(storedFinalizers()).To(BeEmpty())
+	})
+
+	It("does nothing once the finalizer is gone", func() {
+		deleteInstance()
+		instance.Finalizers = []string{"other.finalizer"}
+		_, deleting, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(cleanups).To(Equal(0))
+	})
+
+	It("polls while cleanup is still running", func() {
+		finalizer.PollInterval = 5 * time.Second
+		cleanup = func(_ *components.ComponentContext) (components.Result, error) {
+			return components.Result{Requeue: true}, nil
+		}
+		deleteInstance()
+		res, deleting, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(res.Requeue).To(BeFalse())
+		Expect(res.RequeueAfter).To(Equal(5 * time.Second))
+		Expect(storedFinalizers()).To(ConsistOf("test.finalizer"))
+
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		condition := conditions.Find(instance.Status.Conditions, components.ConditionDeleting)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(components.ReasonCleanupInProgress))
+	})
+
+	It("keeps the requeue delay cleanup asked for", func() {
+		cleanup = func(_ *components.ComponentContext) (components.Result, error) {
+			return components.Result{RequeueAfter: time.Minute}, nil
+		}
+		deleteInstance()
+		res, _, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(time.Minute))
+		Expect(storedFinalizers()).To(ConsistOf("test.finalizer"))
+	})
+
+	It("keeps the finalizer when cleanup fails", func() {
+		cleanup = func(_ *components.ComponentContext) (components.Result, error) {
+			return components.Result{}, errors.New("oops")
+		}
+		deleteInstance()
+		res, deleting, err := finalizer.Handle(ctx)
+		Expect(err).To(MatchError("oops"))
+		Expect(deleting).To(BeTrue())
+		Expect(storedFinalizers()).To(ConsistOf("test.finalizer"))
+
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		condition := conditions.Find(instance.Status.Conditions, components.ConditionDeleting)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(components.ReasonCleanupFailed))
+	})
+
+	It("retries a cleanup which timed out as a transient error", func() {
+		finalizer.Timeout = 10 * time.Millisecond
+		cleanup = func(ctx *components.ComponentContext) (components.Result, error) {
+			<-ctx.Context.Done()
+			return components.Result{}, ctx.Context.Err()
+		}
+		deleteInstance()
+		_, _, err := finalizer.Handle(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("test.finalizer cleanup timed out"))
+		Expect(errors.ClassOf(err)).To(Equal(errors.ClassTransient))
+		Expect(storedFinalizers()).To(ConsistOf("test.finalizer"))
+	})
+
+	It("skips cleanup with the skip annotation", func() {
+		instance.Annotations = map[string]string{components.SkipFinalizerAnnotation: "true"}
+		deleteInstance()
+		_, deleting, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(cleanups).To(Equal(0))
+		Expect(storedFinalizers()).To(BeEmpty())
+	})
+
+	It("skips cleanup with the feature gate off", func() {
+		config.SetCurrent(config.New(nil, nil, map[string]bool{config.FeatureFinalizers: false}))
+		deleteInstance()
+		_, deleting, err := finalizer.Handle(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(cleanups).To(Equal(0))
+		Expect(storedFinalizers()).To(BeEmpty())
+	})
+})
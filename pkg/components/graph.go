@@ -0,0 +1,139 @@
+/*
+Copyright 2018-2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/pkg/errors"
+)
+
+// A wrapper for a component with explicit dependencies. Only used while building the reconciler,
+// the wrapper is stripped off in NewReconciler so optional interfaces keep working.
+type dependentComponent struct {
+	Component
+	dependencies []Component
+}
+
+// Declare that a component must run only after all of the given components have finished
+// reconciling. Components passed to NewReconciler without this wrapper keep the old behavior
+// of waiting for every component listed before them. Components which run in the same stage
+// are executed concurrently, so they must not modify ctx.Top directly, only via StatusModifiers.
+//
+//	defaults := NewDefaults()
+//	components.DependsOn(NewIAMUser("aws/iamuser.yml.tpl"), defaults)
+func DependsOn(comp Component, deps ...Component) Component {
+	return &dependentComponent{Component: comp, dependencies: deps}
+}
+
+// Strip the dependency wrapper off a component, if present.
+func unwrapComponent(comp Component) Component {
+	dep, ok := comp.(*dependentComponent)
+	if ok {
+		return dep.Component
+	}
+	return comp
+}
+
+// Build the execution stages for a list of components. Each stage is a list of indexes into the
+// unwrapped component slice, in the original list order, and every component in a stage only
+// depends on components from earlier stages.
+func buildStages(comps []Component) ([]Component, [][]int, error) {
+	unwrapped := make([]Component, len(comps))
+	for i, comp := range comps {
+		unwrapped[i] = unwrapComponent(comp)
+	}
+
+	indexOf := func(comp Component) int {
+		comp = unwrapComponent(comp)
+		for i, other := range unwrapped {
+			if other == comp {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// Resolve the dependency indexes for each component.
+	dependencies := make([][]int, len(comps))
+	for i, comp := range comps {
+		dep, ok := comp.(*dependentComponent)
+		if !ok {
+			// No explicit dependencies, wait for everything listed before this.
+			for j := 0; j < i; j++ {
+				dependencies[i] = append(dependencies[i], j)
+			}
+			continue
+		}
+		for _, depComp := range dep.dependencies {
+			j := indexOf(depComp)
+			if j == -1 {
+				return nil, nil, errors.Errorf("dependency %#v of %#v is not in the component list", depComp, dep.Component)
+			}
+			if j == i {
+				return nil, nil, errors.Errorf("component %#v depends on itself", dep.Component)
+			}
+			dependencies[i] = append(dependencies[i], j)
+		}
+	}
+
+	// Assign each component to the stage after its latest dependency.
+	stageOf := make([]int, len(comps))
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(comps))
+	var visit func(int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("dependency cycle detected at %#v", unwrapped[i])
+		}
+		state[i] = visiting
+		stage := 0
+		for _, j := range dependencies[i] {
+			err := visit(j)
+			if err != nil {
+				return err
+			}
+			if stageOf[j]+1 > stage {
+				stage = stageOf[j] + 1
+			}
+		}
+		stageOf[i] = stage
+		state[i] = visited
+		return nil
+	}
+	stageCount := 0
+	for i := range comps {
+		err := visit(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if stageOf[i]+1 > stageCount {
+			stageCount = stageOf[i] + 1
+		}
+	}
+
+	stages := make([][]int, stageCount)
+	for i := range comps {
+		stages[stageOf[i]] = append(stages[stageOf[i]], i)
+	}
+	return unwrapped, stages, nil
+}
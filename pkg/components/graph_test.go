@@ -0,0 +1,190 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+var _ = Describe("Component graph", func() {
+	var a, b, c, d *testComponent
+
+	BeforeEach(func() {
+		a = newTestComponent("a", nil)
+		b = newTestComponent("b", nil)
+		c = newTestComponent("c", nil)
+		d = newTestComponent("d", nil)
+	})
+
+	Describe("BuildStages", func() {
+		It("waits for everything listed before a component without dependencies", func() {
+			comps, stages, err := components.BuildStages([]components.Component{a, b, c})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(comps).To(Equal([]components.Component{a, b, c}))
+			Expect(stages).To(Equal([][]int{{0}, {1}, {2}}))
+		})
+
+		It("runs components with the same dependencies in one stage", func() {
+			comps, stages, err := components.BuildStages([]components.Component{
+				a,
+				components.DependsOn(b, a),
+				components.DependsOn(c, a),
+				d,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			// The DependsOn wrappers are stripped.
+			Expect(comps).To(Equal([]components.Component{a, b, c, d}))
+			Expect(stages).To(Equal([][]int{{0}, {1, 2}, {3}}))
+		})
+
+		It("places a component after its latest dependency", func() {
+			_, stages, err := components.BuildStages([]components.Component{
+				a,
+				b,
+				components.DependsOn(c, a),
+				components.DependsOn(d, a, b),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stages).To(Equal([][]int{{0}, {1, 2}, {3}}))
+		})
+
+		It("allows depending on a later component", func() {
+			_, stages, err := components.BuildStages([]components.Component{
+				components.DependsOn(a, b),
+				components.DependsOn(b),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stages).To(Equal([][]int{{1}, {0}}))
+		})
+
+		It("rejects an unknown dependency", func() {
+			_, _, err := components.BuildStages([]components.Component{a, components.DependsOn(b, c)})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not in the component list"))
+		})
+
+		It("rejects a component depending on itself", func() {
+			_, _, err := components.BuildStages([]components.Component{components.DependsOn(a, a)})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("depends on itself"))
+		})
+
+		It("rejects a cycle", func() {
+			_, _, err := components.BuildStages([]components.Component{
+				components.DependsOn(a, c),
+				components.DependsOn(b, a),
+				components.DependsOn(c, b),
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dependency cycle detected"))
+		})
+	})
+
+	Describe("reconcileComponents", func() {
+		var ctx *components.ComponentContext
+
+		reconcileComponents := func(comps ...components.Component) (time.Duration, error) {
+			cr, _, err := components.NewTestReconciler("test", &summonv1beta1.SummonPlatform{}, fake.NewFakeClient(instance), comps)
+			Expect(err).ToNot(HaveOccurred())
+			result, err := cr.ReconcileComponents(ctx)
+			return result.RequeueAfter, err
+		}
+
+		BeforeEach(func() {
+			ctx = components.NewTestContext(instance, nil)
+		})
+
+		It("runs a stage concurrently", func() {
+			// Each one waits for the other to start, so they would time out if run one at a time.
+			aStarted := make(chan bool)
+			bStarted := make(chan bool)
+			wait := func(started, other chan bool) func(*components.ComponentContext) (components.Result, error) {
+				return func(_ *components.ComponentContext) (components.Result, error) {
+					close(started)
+					select {
+					case <-other:
+						return components.Result{}, nil
+					case <-time.After(5 * time.Second):
+						return components.Result{}, errors.New("timed out waiting for the other component")
+					}
+				}
+			}
+			a.reconcile = wait(aStarted, bStarted)
+			b.reconcile = wait(bStarted, aStarted)
+			_, err := reconcileComponents(c, components.DependsOn(a, c), components.DependsOn(b, c))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("applies status modifiers in list order, not completion order", func() {
+			a.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+				time.Sleep(100 * time.Millisecond)
+				return components.Result{StatusModifier: appendMessage("a")}, nil
+			}
+			b.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{StatusModifier: appendMessage("b")}, nil
+			}
+			// The next stage sees the changes from the one before.
+			var seen string
+			c.reconcile = func(ctx *components.ComponentContext) (components.Result, error) {
+				seen = ctx.Top.(*summonv1beta1.SummonPlatform).Status.Message
+				return components.Result{StatusModifier: appendMessage("c")}, nil
+			}
+			_, err := reconcileComponents(a, components.DependsOn(b), c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(seen).To(Equal("ab"))
+			Expect(instance.Status.Message).To(Equal("abc"))
+		})
+
+		It("keeps the shortest requeue", func() {
+			a.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{RequeueAfter: time.Hour}, nil
+			}
+			b.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{RequeueAfter: time.Minute}, nil
+			}
+			requeueAfter, _ := reconcileComponents(a, components.DependsOn(b), c)
+			Expect(requeueAfter).To(Equal(time.Minute))
+		})
+
+		It("skips components which aren't reconcilable", func() {
+			b.notReady = true
+			_, err := reconcileComponents(a, b, c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(a.Calls()).To(Equal(1))
+			Expect(b.Calls()).To(Equal(0))
+			Expect(c.Calls()).To(Equal(1))
+		})
+
+		It("finishes the stage but stops before the next one on an error", func() {
+			a.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{}, errors.New("a failed")
+			}
+			_, err := reconcileComponents(a, components.DependsOn(b), c)
+			Expect(err).To(MatchError("a failed"))
+			Expect(b.Calls()).To(Equal(1))
+			Expect(c.Calls()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// Total time for a single Reconcile call, including the status update.
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ridecell_operator_reconcile_duration_seconds",
+		Help:    "Time taken by each reconcile of a top object.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	}, []string{"controller"})
+
+	// Time spent in each component's Reconcile method.
+	componentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ridecell_operator_component_duration_seconds",
+		Help:    "Time taken by a single component's Reconcile.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"controller", "component"})
+
+	componentErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ridecell_operator_component_errors_total",
+		Help: "Number of errors returned by each component.",
+	}, []string{"controller", "component"})
+
+	errorHandlerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ridecell_operator_error_handler_errors_total",
+		Help: "Number of errors returned by component error handlers.",
+	}, []string{"controller", "component"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ridecell_operator_reconcile_errors_total",
+		Help: "Number of reconciles which ended in an error, by error class.",
+	}, []string{"controller", "class"})
+
+	watchMapErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ridecell_operator_watch_map_errors_total",
+		Help: "Number of watch events dropped because a component's WatchMap failed.",
+	}, []string{"controller", "component"})
+
+	reconcileRequeues = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ridecell_operator_reconcile_requeues_total",
+		Help: "Number of reconciles which asked to be requeued, by type (requeue or requeue_after).",
+	}, []string{"controller", "type"})
+
+	statusUpdateRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ridecell_operator_status_update_retries_total",
+		Help: "Number of times a status update had to be retried after a conflict.",
+	}, []string{"controller"})
+
+	erroredObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ridecell_operator_errored_objects",
+		Help: "Number of top objects whose last reconcile ended in an error.",
+	}, []string{"controller"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileDuration,
+		componentDuration,
+		componentErrors,
+		errorHandlerErrors,
+		reconcileErrors,
+		watchMapErrors,
+		reconcileRequeues,
+		statusUpdateRetries,
+		erroredObjects,
+	)
+}
+
+// Tracks which top objects are currently in an error state for the erroredObjects gauge.
+type erroredObjectTracker struct {
+	lock    sync.Mutex
+	objects map[types.NamespacedName]bool
+}
+
+func (t *erroredObjectTracker) set(controller string, name types.NamespacedName, errored bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.objects == nil {
+		t.objects = map[types.NamespacedName]bool{}
+	}
+	if errored {
+		t.objects[name] = true
+	} else {
+		delete(t.objects, name)
+	}
+	erroredObjects.WithLabelValues(controller).Set(float64(len(t.objects)))
+}
+
+// Work out a human-friendly name for a component to use in metric labels and logs. This is
+// the type name without the "Component" suffix, plus the template path if the component has one.
+func componentName(comp Component) string {
+	val := reflect.ValueOf(comp)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	name := strings.TrimSuffix(val.Type().Name(), "Component")
+	if val.Kind() == reflect.Struct {
+		templatePath := val.FieldByName("templatePath")
+		if templatePath.IsValid() && templatePath.Kind() == reflect.String && templatePath.String() != "" {
+			name = fmt.Sprintf("%s(%s)", name, templatePath.String())
+		}
+	}
+	return name
+}
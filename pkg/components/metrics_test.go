@@ -0,0 +1,146 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// Read a metric from the controller-runtime registry. Counters and gauges give their value,
+// histograms their sample count, and anything missing gives 0.
+func metricValue(name string, labels map[string]string) float64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.Metric {
+			if !labelsMatch(metric.Label, labels) {
+				continue
+			}
+			switch {
+			case metric.Counter != nil:
+				return metric.Counter.GetValue()
+			case metric.Gauge != nil:
+				return metric.Gauge.GetValue()
+			case metric.Histogram != nil:
+				return float64(metric.Histogram.GetSampleCount())
+			}
+		}
+	}
+	return 0
+}
+
+func labelsMatch(pairs []*dto.LabelPair, labels map[string]string) bool {
+	if len(pairs) != len(labels) {
+		return false
+	}
+	for _, pair := range pairs {
+		if labels[pair.GetName()] != pair.GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+var _ = Describe("Reconciler metrics", func() {
+	// Each test uses its own controller name so the counters start from zero.
+	var name string
+	var comp *testComponent
+	testCount := 0
+
+	reconcileOnce := func() {
+		cr, _, err := components.NewTestReconciler(name, &summonv1beta1.SummonPlatform{}, fake.NewFakeClient(instance), []components.Component{comp})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = cr.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		testCount++
+		name = fmt.Sprintf("metrics-test-%d", testCount)
+		comp = newTestComponent("a", nil)
+	})
+
+	It("times reconciles and components", func() {
+		reconcileOnce()
+		Expect(metricValue("ridecell_operator_reconcile_duration_seconds", map[string]string{"controller": name})).To(Equal(1.0))
+		Expect(metricValue("ridecell_operator_component_duration_seconds", map[string]string{"controller": name, "component": "test(a)"})).To(Equal(1.0))
+		Expect(metricValue("ridecell_operator_component_errors_total", map[string]string{"controller": name, "component": "test(a)"})).To(Equal(0.0))
+		Expect(metricValue("ridecell_operator_errored_objects", map[string]string{"controller": name})).To(Equal(0.0))
+	})
+
+	It("counts errors by component and class", func() {
+		comp.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+			return components.Result{}, errors.Permanent(errors.New("bad spec"))
+		}
+		reconcileOnce()
+		Expect(metricValue("ridecell_operator_component_errors_total", map[string]string{"controller": name, "component": "test(a)"})).To(Equal(1.0))
+		Expect(metricValue("ridecell_operator_reconcile_errors_total", map[string]string{"controller": name, "class": "permanent"})).To(Equal(1.0))
+	})
+
+	It("tracks errored objects until they reconcile cleanly", func() {
+		failing := true
+		comp.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+			if failing {
+				return components.Result{}, errors.New("oops")
+			}
+			return components.Result{}, nil
+		}
+		cr, _, err := components.NewTestReconciler(name, &summonv1beta1.SummonPlatform{}, fake.NewFakeClient(instance), []components.Component{comp})
+		Expect(err).ToNot(HaveOccurred())
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+
+		_, err = cr.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metricValue("ridecell_operator_errored_objects", map[string]string{"controller": name})).To(Equal(1.0))
+
+		failing = false
+		_, err = cr.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metricValue("ridecell_operator_errored_objects", map[string]string{"controller": name})).To(Equal(0.0))
+	})
+
+	It("counts requeues by type", func() {
+		comp.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+			return components.Result{RequeueAfter: time.Minute}, nil
+		}
+		reconcileOnce()
+		Expect(metricValue("ridecell_operator_reconcile_requeues_total", map[string]string{"controller": name, "type": "requeue_after"})).To(Equal(1.0))
+		Expect(metricValue("ridecell_operator_reconcile_requeues_total", map[string]string{"controller": name, "type": "requeue"})).To(Equal(0.0))
+
+		comp.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+			return components.Result{}, errors.Transient(errors.New("blip"))
+		}
+		reconcileOnce()
+		Expect(metricValue("ridecell_operator_reconcile_requeues_total", map[string]string{"controller": name, "type": "requeue"})).To(Equal(1.0))
+	})
+})
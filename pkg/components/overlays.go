@@ -0,0 +1,106 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+)
+
+// Render a template and apply any overlay patches from the top object to the result.
+func (ctx *ComponentContext) renderTemplate(path string, extraData map[string]interface{}) (runtime.Object, error) {
+	obj, err := ctx.GetTemplate(path, extraData)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.applyOverlays(obj)
+}
+
+// Apply the top object's overlay patches which target a rendered object, in order.
+func (ctx *ComponentContext) applyOverlays(obj runtime.Object) (runtime.Object, error) {
+	overlayer, ok := ctx.Top.(Overlayer)
+	if !ok {
+		return obj, nil
+	}
+	patches := overlayer.GetOverlays()
+	if len(patches) == 0 {
+		return obj, nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, ctx.Scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get GVK for overlay")
+	}
+	name := obj.(metav1.Object).GetName()
+	for i, patch := range patches {
+		if !patch.Matches(gvk.Kind, name) {
+			continue
+		}
+		patched, err := patch.Apply(obj)
+		if err != nil {
+			ctx.recordOverlay(i, overlays.PatchStatus{Kind: patch.Kind, Name: patch.Name, Message: err.Error()})
+			return nil, errors.Wrapf(err, "error applying overlay %d to %s %s", i, gvk.Kind, name)
+		}
+		ctx.recordOverlay(i, overlays.PatchStatus{Kind: patch.Kind, Name: patch.Name, Applied: true})
+		obj = patched
+	}
+	return obj, nil
+}
+
+func (ctx *ComponentContext) recordOverlay(i int, status overlays.PatchStatus) {
+	if ctx.inventory == nil {
+		return
+	}
+	ctx.inventory.mutex.Lock()
+	defer ctx.inventory.mutex.Unlock()
+	ctx.inventory.overlays[i] = status
+}
+
+// Build a StatusModifier reporting the outcome of every overlay patch. Patches which didn't
+// match anything rendered this reconcile keep their last status, since their component may
+// just be waiting on something. Returns nil if the top object doesn't support overlays.
+func (ctx *ComponentContext) overlayStatusModifier() StatusModifier {
+	overlayer, ok := ctx.Top.(Overlayer)
+	if !ok || ctx.inventory == nil {
+		return nil
+	}
+	ctx.inventory.mutex.Lock()
+	defer ctx.inventory.mutex.Unlock()
+
+	patches := overlayer.GetOverlays()
+	previous := overlayer.GetOverlayStatus()
+	var statuses []overlays.PatchStatus
+	for i, patch := range patches {
+		status, ok := ctx.inventory.overlays[i]
+		if !ok {
+			status = overlays.PatchStatus{Kind: patch.Kind, Name: patch.Name, Message: "no matching object rendered yet"}
+			if i < len(previous) && previous[i].Kind == patch.Kind && previous[i].Name == patch.Name {
+				status = previous[i]
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return func(obj runtime.Object) error {
+		obj.(Overlayer).SetOverlayStatus(statuses)
+		return nil
+	}
+}
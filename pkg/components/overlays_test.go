@@ -0,0 +1,104 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+)
+
+var _ = Describe("Overlays", func() {
+	var testTemplates http.FileSystem = http.Dir("test_templates")
+	noop := func(_, _ runtime.Object) error { return nil }
+
+	render := func() (*corev1.ConfigMap, error) {
+		ctx := components.NewContext(instance, fake.NewFakeClient(instance), scheme.Scheme, testTemplates)
+		_, _, err := ctx.CreateOrUpdate("configmap.yml.tpl", map[string]interface{}{"value": "one"}, noop)
+		if err != nil {
+			return nil, err
+		}
+		target := &corev1.ConfigMap{}
+		err = ctx.Get(ctx.Context, types.NamespacedName{Name: "foo-config", Namespace: "default"}, target)
+		Expect(err).ToNot(HaveOccurred())
+		return target, nil
+	}
+
+	cases := []struct {
+		name     string
+		patches  []overlays.Patch
+		expected map[string]string
+	}{
+		{
+			name:     "renders unchanged without overlays",
+			expected: map[string]string{"version": "1.2.3", "extra": "one"},
+		},
+		{
+			name: "skips patches for other objects",
+			patches: []overlays.Patch{
+				{Kind: "ConfigMap", Name: "other", Patch: "data: {extra: other}"},
+				{Kind: "Secret", Name: "foo-config", Patch: "data: {extra: secret}"},
+			},
+			expected: map[string]string{"version": "1.2.3", "extra": "one"},
+		},
+		{
+			name: "applies later patches over earlier ones",
+			patches: []overlays.Patch{
+				{Kind: "ConfigMap", Name: "foo-config", Patch: "data: {extra: first, added: first}"},
+				{Kind: "ConfigMap", Name: "foo-config", Type: overlays.MergePatch, Patch: "data: {extra: second}"},
+			},
+			expected: map[string]string{"version": "1.2.3", "extra": "second", "added": "first"},
+		},
+		{
+			name: "applies each patch to the output of the one before",
+			patches: []overlays.Patch{
+				{Kind: "ConfigMap", Name: "foo-config", Patch: "data: {added: first}"},
+				{Kind: "ConfigMap", Name: "foo-config", Type: overlays.JSONPatch, Patch: `[{"op": "remove", "path": "/data/added"}]`},
+			},
+			expected: map[string]string{"version": "1.2.3", "extra": "one"},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		It(c.name, func() {
+			instance.Spec.Overlays = c.patches
+			target, err := render()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Data).To(Equal(c.expected))
+		})
+	}
+
+	It("stops at the first patch which fails", func() {
+		instance.Spec.Overlays = []overlays.Patch{
+			{Kind: "ConfigMap", Name: "foo-config", Type: overlays.JSONPatch, Patch: `[{"op": "remove", "path": "/data/missing"}]`},
+			{Kind: "ConfigMap", Name: "foo-config", Patch: "data: {extra: second}"},
+		}
+		_, err := render()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("error applying overlay 0 to ConfigMap foo-config"))
+	})
+})
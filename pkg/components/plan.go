@@ -0,0 +1,208 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Ridecell/ridecell-operator/pkg/templates"
+)
+
+// What applying a template would do to the cluster.
+type PlanAction string
+
+const (
+	PlanCreate    PlanAction = "create"
+	PlanUpdate    PlanAction = "update"
+	PlanUnchanged PlanAction = "unchanged"
+	PlanError     PlanAction = "error"
+	// The template rendered nothing, anything it made before would be pruned.
+	PlanEmpty PlanAction = "empty"
+	// The template needs data only a real reconcile has, so it wasn't rendered.
+	PlanSkipped PlanAction = "skipped"
+)
+
+// Wrapped with the reason by template data functions for templates that can't be planned.
+var ErrPlanSkipped = errors.New("template can't be planned")
+
+// A PlannedChange is the result of rendering one template and comparing it to the live object.
+type PlannedChange struct {
+	Template  string
+	Kind      string
+	Namespace string
+	Name      string
+	Action    PlanAction
+	// Line diff of the existing object against the rendered one, lines prefixed with "+ ", "- " or "  ".
+	Diff string
+	// Why rendering failed, or for PlanSkipped, why it wasn't attempted.
+	Err error
+}
+
+// Render a template and compare it against the current object in the cluster without changing
+// anything. Only fields present in the rendered object are compared, since everything else is
+// either server-managed or left alone by CreateOrUpdate.
+func (ctx *ComponentContext) Plan(path string, extraData map[string]interface{}) PlannedChange {
+	change := PlannedChange{Template: path, Action: PlanError}
+	target, err := ctx.renderTemplate(path, extraData)
+	if err == templates.ErrEmptyTemplate {
+		change.Action = PlanEmpty
+		return change
+	}
+	if err != nil {
+		change.Err = errors.Wrapf(err, "error rendering template %s", path)
+		return change
+	}
+	targetMeta := target.(metav1.Object)
+	change.Namespace = targetMeta.GetNamespace()
+	change.Name = targetMeta.GetName()
+	gvk, err := apiutil.GVKForObject(target, ctx.Scheme)
+	if err != nil {
+		change.Err = errors.Wrapf(err, "error finding kind for template %s", path)
+		return change
+	}
+	change.Kind = gvk.Kind
+
+	targetData, err := planData(target)
+	if err != nil {
+		change.Err = err
+		return change
+	}
+
+	existing := target.DeepCopyObject()
+	err = ctx.Get(ctx.Context, types.NamespacedName{Name: change.Name, Namespace: change.Namespace}, existing)
+	if err != nil && kerrors.IsNotFound(err) {
+		change.Diff, change.Err = planDiff(map[string]interface{}{}, targetData)
+		if change.Err == nil {
+			change.Action = PlanCreate
+		}
+		return change
+	} else if err != nil {
+		change.Err = errors.Wrapf(err, "error getting %s %s/%s", change.Kind, change.Namespace, change.Name)
+		return change
+	}
+
+	existingData, err := planData(existing)
+	if err != nil {
+		change.Err = err
+		return change
+	}
+	// Typed Gets don't always fill in the kind, it's the same object either way.
+	existingData["apiVersion"] = targetData["apiVersion"]
+	existingData["kind"] = targetData["kind"]
+	existingData = pruneToKeys(existingData, targetData).(map[string]interface{})
+	change.Diff, change.Err = planDiff(existingData, targetData)
+	if change.Err != nil {
+		change.Action = PlanError
+	} else if change.Diff == "" {
+		change.Action = PlanUnchanged
+	} else {
+		change.Action = PlanUpdate
+	}
+	return change
+}
+
+// Convert an object to generic data for diffing, dropping fields that only the server sets.
+func planData(obj runtime.Object) (map[string]interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "error serializing object")
+	}
+	data := map[string]interface{}{}
+	err = json.Unmarshal(raw, &data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error deserializing object")
+	}
+	delete(data, "status")
+	metadata, ok := data["metadata"].(map[string]interface{})
+	if ok {
+		for _, key := range []string{"creationTimestamp", "resourceVersion", "uid", "selfLink", "generation", "ownerReferences", "managedFields"} {
+			delete(metadata, key)
+		}
+	}
+	return data, nil
+}
+
+// Recursively drop any map keys from existing which aren't set in target.
+func pruneToKeys(existing, target interface{}) interface{} {
+	existingMap, ok := existing.(map[string]interface{})
+	if !ok {
+		return existing
+	}
+	targetMap, ok := target.(map[string]interface{})
+	if !ok {
+		return existing
+	}
+	pruned := map[string]interface{}{}
+	for key, targetVal := range targetMap {
+		existingVal, ok := existingMap[key]
+		if ok {
+			pruned[key] = pruneToKeys(existingVal, targetVal)
+		}
+	}
+	return pruned
+}
+
+// Build a line diff of two objects in YAML form. Returns an empty string if they are the same.
+func planDiff(existing, target map[string]interface{}) (string, error) {
+	existingYaml := []byte{}
+	if len(existing) != 0 {
+		var err error
+		existingYaml, err = yaml.Marshal(existing)
+		if err != nil {
+			return "", errors.Wrap(err, "error serializing existing object")
+		}
+	}
+	targetYaml, err := yaml.Marshal(target)
+	if err != nil {
+		return "", errors.Wrap(err, "error serializing rendered object")
+	}
+	if string(existingYaml) == string(targetYaml) {
+		return "", nil
+	}
+
+	dmp := diffmatchpatch.New()
+	existingChars, targetChars, lines := dmp.DiffLinesToChars(string(existingYaml), string(targetYaml))
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(existingChars, targetChars, false), lines)
+	var out strings.Builder
+	for _, diff := range diffs {
+		prefix := "  "
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+ "
+		case diffmatchpatch.DiffDelete:
+			prefix = "- "
+		}
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			fmt.Fprintf(&out, "%s%s", prefix, line)
+		}
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+)
+
+var _ = Describe("Plan", func() {
+	var testTemplates http.FileSystem = http.Dir("test_templates")
+	var extra map[string]interface{}
+
+	BeforeEach(func() {
+		extra = map[string]interface{}{"value": "one"}
+	})
+
+	plan := func(path string, objs ...runtime.Object) components.PlannedChange {
+		c := fake.NewFakeClient(append(objs, instance)...)
+		ctx := components.NewContext(instance, c, scheme.Scheme, testTemplates)
+		return ctx.Plan(path, extra)
+	}
+
+	It("plans a create for a missing object", func() {
+		change := plan("configmap.yml.tpl")
+		Expect(change.Err).ToNot(HaveOccurred())
+		Expect(change.Action).To(Equal(components.PlanCreate))
+		Expect(change.Kind).To(Equal("ConfigMap"))
+		Expect(change.Namespace).To(Equal("default"))
+		Expect(change.Name).To(Equal("foo-config"))
+		Expect(change.Diff).To(ContainSubstring("+   extra: one\n"))
+		Expect(change.Diff).To(ContainSubstring("+   version: 1.2.3\n"))
+	})
+
+	It("passes the extra data to the template", func() {
+		extra["value"] = "two"
+		change := plan("configmap.yml.tpl")
+		Expect(change.Diff).To(ContainSubstring("+   extra: two\n"))
+	})
+
+	It("plans nothing for an object which already matches", func() {
+		existing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-config", Namespace: "default", ResourceVersion: "12"},
+			Data:       map[string]string{"version": "1.2.3", "extra": "one", "other": "ignored"},
+		}
+		change := plan("configmap.yml.tpl", existing)
+		Expect(change.Err).ToNot(HaveOccurred())
+		Expect(change.Action).To(Equal(components.PlanUnchanged))
+		Expect(change.Diff).To(BeEmpty())
+	})
+
+	It("plans an update for a changed field", func() {
+		existing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-config", Namespace: "default"},
+			Data:       map[string]string{"version": "1.2.2", "extra": "one"},
+		}
+		change := plan("configmap.yml.tpl", existing)
+		Expect(change.Err).ToNot(HaveOccurred())
+		Expect(change.Action).To(Equal(components.PlanUpdate))
+		Expect(change.Diff).To(ContainSubstring("-   version: 1.2.2\n"))
+		Expect(change.Diff).To(ContainSubstring("+   version: 1.2.3\n"))
+		Expect(change.Diff).To(ContainSubstring("    extra: one\n"))
+	})
+
+	It("reports a template which renders nothing", func() {
+		change := plan("empty.yml.tpl")
+		Expect(change.Err).ToNot(HaveOccurred())
+		Expect(change.Action).To(Equal(components.PlanEmpty))
+	})
+
+	It("reports a template which fails to render", func() {
+		change := plan("missing.yml.tpl")
+		Expect(change.Err).To(HaveOccurred())
+		Expect(change.Action).To(Equal(components.PlanError))
+	})
+
+	Describe("planDiff", func() {
+		It("returns nothing for equal objects", func() {
+			data := map[string]interface{}{"a": "b"}
+			diff, err := components.PlanDiff(data, data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff).To(BeEmpty())
+		})
+
+		It("marks every line as added for a new object", func() {
+			diff, err := components.PlanDiff(map[string]interface{}{}, map[string]interface{}{"a": "b", "c": "d"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff).To(Equal("+ a: b\n+ c: d\n"))
+		})
+
+		It("prefixes changed and unchanged lines", func() {
+			diff, err := components.PlanDiff(map[string]interface{}{"a": "b", "c": "d"}, map[string]interface{}{"a": "b", "c": "e"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff).To(Equal("  a: b\n- c: d\n+ c: e\n"))
+		})
+	})
+})
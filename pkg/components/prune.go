@@ -0,0 +1,211 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+)
+
+// Label with the UID of the top object, set on everything created through CreateOrUpdate.
+const PruneOwnerLabel = "ridecell.io/prune-owner"
+
+// Annotation with the template path an object was created from.
+const PruneTemplateAnnotation = "ridecell.io/template"
+
+// Annotation on a top object to turn off pruning for it.
+const SkipPruneAnnotation = "ridecell.io/skip-prune"
+
+// An object produced by a template during a reconcile.
+type renderedObject struct {
+	gvk schema.GroupVersionKind
+	key types.NamespacedName
+}
+
+// The objects produced by each template path during a single reconcile, and the outcome of
+// any overlay patches applied to them. Components in the same stage share a context, so this
+// is locked.
+type inventory struct {
+	mutex    sync.Mutex
+	rendered map[string][]renderedObject
+	overlays map[int]overlays.PatchStatus
+}
+
+func newInventory() *inventory {
+	return &inventory{rendered: map[string][]renderedObject{}, overlays: map[int]overlays.PatchStatus{}}
+}
+
+// Record that a template was rendered. A nil obj means it rendered empty.
+func (ctx *ComponentContext) recordRendered(path string, obj runtime.Object) {
+	if ctx.inventory == nil {
+		return
+	}
+	ctx.inventory.mutex.Lock()
+	defer ctx.inventory.mutex.Unlock()
+
+	objects, ok := ctx.inventory.rendered[path]
+	if !ok {
+		objects = []renderedObject{}
+	}
+	if obj != nil {
+		gvk, err := apiutil.GVKForObject(obj, ctx.Scheme)
+		if err != nil {
+			// Can't identify it, so never prune anything from this template.
+			ctx.Logger().Error(err, "Unable to get GVK for object from template", "template", path)
+			ctx.inventory.rendered[path] = append(objects, renderedObject{})
+			return
+		}
+		objMeta := obj.(metav1.Object)
+		namespace := objMeta.GetNamespace()
+		if namespace == "" {
+			namespace = ctx.Top.(metav1.Object).GetNamespace()
+		}
+		objects = append(objects, renderedObject{gvk: gvk, key: types.NamespacedName{Namespace: namespace, Name: objMeta.GetName()}})
+	}
+	ctx.inventory.rendered[path] = objects
+}
+
+// Set the labels and annotations used to find objects to prune.
+func setPruneMeta(top metav1.Object, objMeta *metav1.ObjectMeta, path string) {
+	if objMeta.Labels == nil {
+		objMeta.Labels = map[string]string{}
+	}
+	objMeta.Labels[PruneOwnerLabel] = string(top.GetUID())
+	if objMeta.Annotations == nil {
+		objMeta.Annotations = map[string]string{}
+	}
+	objMeta.Annotations[PruneTemplateAnnotation] = path
+}
+
+// Delete objects owned by the top object which are no longer produced. An object is no longer
+// produced if its template rendered this reconcile but didn't include it (it rendered empty or
+// under a different name), or if its template doesn't exist anymore. Templates which weren't
+// rendered this reconcile are otherwise left alone, since plenty of components skip rendering
+// while waiting for something. pruneTypes is every owned type the reconciler watches.
+func (ctx *ComponentContext) prune(pruneTypes []runtime.Object, templateExists func(string) bool) ([]runtime.Object, error) {
+	top := ctx.Top.(metav1.Object)
+	if top.GetAnnotations()[SkipPruneAnnotation] == "true" || top.GetUID() == "" || top.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	ctx.inventory.mutex.Lock()
+	defer ctx.inventory.mutex.Unlock()
+
+	// Work out which types and namespaces to look in.
+	gvks := map[schema.GroupVersionKind]bool{}
+	for _, obj := range pruneTypes {
+		gvk, err := apiutil.GVKForObject(obj, ctx.Scheme)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get GVK for prune type")
+		}
+		gvks[gvk] = true
+	}
+	namespaces := map[string]bool{top.GetNamespace(): true}
+	for _, objects := range ctx.inventory.rendered {
+		for _, rendered := range objects {
+			if rendered.gvk.Kind != "" {
+				gvks[rendered.gvk] = true
+				namespaces[rendered.key.Namespace] = true
+			}
+		}
+	}
+
+	pruned := []runtime.Object{}
+	for gvk := range gvks {
+		listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+		list, err := ctx.Scheme.New(listGVK)
+		if err != nil {
+			// No list type registered, nothing we can do.
+			continue
+		}
+		for namespace := range namespaces {
+			listOptions := (&client.ListOptions{}).InNamespace(namespace).MatchingLabels(map[string]string{PruneOwnerLabel: string(top.GetUID())})
+			err = ctx.List(ctx.Context, listOptions, list)
+			if err != nil {
+				return pruned, errors.Wrapf(err, "error listing %s for pruning", listGVK.Kind)
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return pruned, errors.Wrapf(err, "error extracting %s for pruning", listGVK.Kind)
+			}
+			for _, item := range items {
+				if !ctx.shouldPrune(item, gvk, templateExists) {
+					continue
+				}
+				itemMeta := item.(metav1.Object)
+				ctx.Logger().Info("Pruning object", "objectKind", gvk.Kind, "objectNamespace", itemMeta.GetNamespace(), "objectName", itemMeta.GetName())
+				err = ctx.Delete(ctx.Context, item, client.PropagationPolicy(metav1.DeletePropagationBackground))
+				if err != nil && !kerrors.IsNotFound(err) {
+					return pruned, errors.Wrapf(err, "error pruning %s %s/%s", gvk.Kind, itemMeta.GetNamespace(), itemMeta.GetName())
+				}
+				ctx.Eventf(corev1.EventTypeNormal, "Pruned", "Deleted %s %s/%s, no longer produced by %s", gvk.Kind, itemMeta.GetNamespace(), itemMeta.GetName(), itemMeta.GetAnnotations()[PruneTemplateAnnotation])
+				pruned = append(pruned, item)
+			}
+		}
+	}
+	return pruned, nil
+}
+
+func (ctx *ComponentContext) shouldPrune(obj runtime.Object, gvk schema.GroupVersionKind, templateExists func(string) bool) bool {
+	top := ctx.Top.(metav1.Object)
+	objMeta := obj.(metav1.Object)
+
+	// Double check ownership, in case the label was copied somewhere else.
+	if objMeta.GetLabels()[PruneOwnerLabel] != string(top.GetUID()) {
+		return false
+	}
+	owner := metav1.GetControllerOf(objMeta)
+	if owner == nil || owner.UID != top.GetUID() {
+		return false
+	}
+	if objMeta.GetDeletionTimestamp() != nil {
+		return false
+	}
+
+	path := objMeta.GetAnnotations()[PruneTemplateAnnotation]
+	if path == "" {
+		return false
+	}
+	rendered, ok := ctx.inventory.rendered[path]
+	if !ok {
+		// Not rendered this time, only prune it if the template is gone entirely.
+		return !templateExists(strings.TrimPrefix(path, "/"))
+	}
+	for _, r := range rendered {
+		if r.gvk.Kind == "" {
+			// Unidentifiable object from this template, be safe.
+			return false
+		}
+		if r.gvk == gvk && r.key.Namespace == objMeta.GetNamespace() && r.key.Name == objMeta.GetName() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+)
+
+var _ = Describe("Prune", func() {
+	var testTemplates http.FileSystem = http.Dir("test_templates")
+	var ctx *components.ComponentContext
+	pruneTypes := []runtime.Object{&corev1.ConfigMap{}}
+	noop := func(_, _ runtime.Object) error { return nil }
+	allExist := func(_ string) bool { return true }
+
+	BeforeEach(func() {
+		instance.UID = types.UID("top-uid")
+	})
+
+	// A ConfigMap as CreateOrUpdate would have left it.
+	owned := func(name, path string) *corev1.ConfigMap {
+		isController := true
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Labels:      map[string]string{components.PruneOwnerLabel: "top-uid"},
+				Annotations: map[string]string{components.PruneTemplateAnnotation: path},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "summon.ridecell.io/v1beta1",
+					Kind:       "SummonPlatform",
+					Name:       instance.Name,
+					UID:        instance.UID,
+					Controller: &isController,
+				}},
+			},
+		}
+	}
+
+	setup := func(objs ...runtime.Object) {
+		ctx = components.NewContext(instance, fake.NewFakeClient(append(objs, instance)...), scheme.Scheme, testTemplates)
+		ctx.TrackRendered()
+	}
+
+	exists := func(name string) bool {
+		err := ctx.Get(ctx.Context, types.NamespacedName{Name: name, Namespace: "default"}, &corev1.ConfigMap{})
+		if err != nil {
+			Expect(err.Error()).To(ContainSubstring("not found"))
+			return false
+		}
+		return true
+	}
+
+	It("keeps objects which are still rendered", func() {
+		setup(owned("foo-config", "configmap.yml.tpl"))
+		_, _, err := ctx.CreateOrUpdate("configmap.yml.tpl", map[string]interface{}{"value": "one"}, noop)
+		Expect(err).ToNot(HaveOccurred())
+
+		pruned, err := ctx.Prune(pruneTypes, allExist)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(exists("foo-config")).To(BeTrue())
+	})
+
+	It("prunes an object its template no longer renders", func() {
+		setup(owned("foo-old", "configmap.yml.tpl"))
+		_, _, err := ctx.CreateOrUpdate("configmap.yml.tpl", map[string]interface{}{"value": "one"}, noop)
+		Expect(err).ToNot(HaveOccurred())
+
+		pruned, err := ctx.Prune(pruneTypes, allExist)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(HaveLen(1))
+		Expect(exists("foo-old")).To(BeFalse())
+		Expect(exists("foo-config")).To(BeTrue())
+	})
+
+	It("prunes an object when its template renders empty", func() {
+		setup(owned("foo-optional", "empty.yml.tpl"))
+		_, _, err := ctx.CreateOrUpdate("empty.yml.tpl", map[string]interface{}{"enabled": false}, noop)
+		Expect(err).ToNot(HaveOccurred())
+
+		pruned, err := ctx.Prune(pruneTypes, allExist)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(HaveLen(1))
+		Expect(exists("foo-optional")).To(BeFalse())
+	})
+
+	It("never prunes objects without the owner label", func() {
+		unlabeled := owned("foo-old", "configmap.yml.tpl")
+		unlabeled.Labels = nil
+		setup(unlabeled)
+		_, _, err := ctx.CreateOrUpdate("configmap.yml.tpl", map[string]interface{}{"value": "one"}, noop)
+		Expect(err).ToNot(HaveOccurred())
+
+		pruned, err := ctx.Prune(pruneTypes, allExist)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(exists("foo-old")).To(BeTrue())
+	})
+
+	It("never prunes objects labeled for a different owner", func() {
+		other := owned("foo-old", "configmap.yml.tpl")
+		other.Labels[components.PruneOwnerLabel] = "other-uid"
+		copied := owned("foo-copied", "configmap.yml.tpl")
+		copied.OwnerReferences[0].UID = "other-uid"
+		setup(other, copied)
+		_, _, err := ctx.CreateOrUpdate("configmap.yml.tpl", map[string]interface{}{"value": "one"}, noop)
+		Expect(err).ToNot(HaveOccurred())
+
+		pruned, err := ctx.Prune(pruneTypes, allExist)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(exists("foo-old")).To(BeTrue())
+		Expect(exists("foo-copied")).To(BeTrue())
+	})
+
+	It("never prunes objects from a template which wasn't rendered", func() {
+		setup(owned("foo-other", "other.yml.tpl"))
+		_, _, err := ctx.CreateOrUpdate("configmap.yml.tpl", map[string]interface{}{"value": "one"}, noop)
+		Expect(err).ToNot(HaveOccurred())
+
+		pruned, err := ctx.Prune(pruneTypes, allExist)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(exists("foo-other")).To(BeTrue())
+	})
+
+	It("prunes objects from a template which no longer exists", func() {
+		setup(owned("foo-other", "other.yml.tpl"))
+		pruned, err := ctx.Prune(pruneTypes, func(path string) bool { return path != "other.yml.tpl" })
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(HaveLen(1))
+		Expect(exists("foo-other")).To(BeFalse())
+	})
+
+	It("never prunes objects without a template annotation", func() {
+		setup(owned("foo-old", ""))
+		pruned, err := ctx.Prune(pruneTypes, func(_ string) bool { return false })
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(exists("foo-old")).To(BeTrue())
+	})
+
+	It("does nothing with the skip annotation", func() {
+		instance.Annotations = map[string]string{components.SkipPruneAnnotation: "true"}
+		setup(owned("foo-old", "configmap.yml.tpl"))
+		_, _, err := ctx.CreateOrUpdate("configmap.yml.tpl", map[string]interface{}{"value": "one"}, noop)
+		Expect(err).ToNot(HaveOccurred())
+
+		pruned, err := ctx.Prune(pruneTypes, allExist)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(exists("foo-old")).To(BeTrue())
+	})
+})
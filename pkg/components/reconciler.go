@@ -21,13 +21,16 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sync"
+	"time"
 
-	"github.com/golang/glog"
-	"github.com/pkg/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -35,19 +38,33 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	"github.com/Ridecell/ridecell-operator/pkg/logging"
+	"github.com/Ridecell/ridecell-operator/pkg/templates"
 )
 
 func NewReconciler(name string, mgr manager.Manager, top runtime.Object, templates http.FileSystem, components []Component) (*componentReconciler, error) {
+	// Work out the execution order from the declared dependencies.
+	components, stages, err := buildStages(components)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to build component graph for %s", name)
+	}
+
 	cr := &componentReconciler{
 		name:       name,
 		top:        top,
 		templates:  templates,
 		components: components,
+		stages:     stages,
 		manager:    mgr,
+		recorder:   mgr.GetRecorder(name),
 	}
 
 	// Create the controller.
-	c, err := controller.New(name, mgr, controller.Options{Reconciler: cr})
+	c, err := controller.New(name, mgr, controller.Options{Reconciler: cr, MaxConcurrentReconciles: maxConcurrentReconciles(name)})
 	if err != nil {
 		return nil, fmt.Errorf("unable to create controller: %v", err)
 	}
@@ -71,9 +88,11 @@ func NewReconciler(name string, mgr manager.Manager, top runtime.Object, templat
 					ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
 						requests, err := mfComp.WatchMap(obj, cr.client)
 						if err != nil {
-							// For lack anything better to do for now ...
-							fmt.Printf("ERROR FROM MAP FUNC: %s", err)
-							panic(err)
+							// Nothing to requeue from here, so log it and drop this event. The
+							// next change or resync will try the mapping again.
+							watchMapErrors.WithLabelValues(cr.name, componentName(mfComp)).Inc()
+							logging.Log.WithName(cr.name).Error(err, "Error mapping watch event", "component", componentName(mfComp), "namespace", obj.Meta.GetNamespace(), "name", obj.Meta.GetName())
+							return nil
 						}
 						return requests
 					}),
@@ -87,6 +106,7 @@ func NewReconciler(name string, mgr manager.Manager, top runtime.Object, templat
 					continue
 				}
 				watchedTypes[watchType] = true
+				cr.pruneTypes = append(cr.pruneTypes, watchObj)
 				watchHandler = &handler.EnqueueRequestForOwner{
 					IsController: true,
 					OwnerType:    cr.top,
@@ -103,8 +123,8 @@ func NewReconciler(name string, mgr manager.Manager, top runtime.Object, templat
 	return cr, nil
 }
 
-func (cr *componentReconciler) newContext(request reconcile.Request) (*ComponentContext, error) {
-	reqCtx := context.TODO()
+func (cr *componentReconciler) newContext(reqCtx context.Context, request reconcile.Request) (*ComponentContext, error) {
+	logger := cr.requestLogger(request)
 
 	// Fetch the current value of the top object for this reconcile.
 	top := cr.top.DeepCopyObject()
@@ -117,6 +137,10 @@ func (cr *componentReconciler) newContext(request reconcile.Request) (*Component
 		templates: cr.templates,
 		Context:   reqCtx,
 		Top:       top,
+		Recorder:  cr.recorder,
+		inventory: newInventory(),
+		logger:    logger,
+		config:    config.Current(),
 	}
 	err = cr.manager.SetFields(ctx)
 	if err != nil {
@@ -125,19 +149,44 @@ func (cr *componentReconciler) newContext(request reconcile.Request) (*Component
 	return ctx, nil
 }
 
-func (cr *componentReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	glog.Infof("[%s] %s: Reconciling!", request.NamespacedName, cr.name)
+// Build a logger for one reconcile. The ID is unique per reconcile so every line from a single
+// pass can be found together in the log search system.
+func (cr *componentReconciler) requestLogger(request reconcile.Request) logr.Logger {
+	kind := reflect.TypeOf(cr.top).Elem().Name()
+	return logging.Log.WithName(cr.name).WithValues("reconcileID", string(uuid.NewUUID()), "kind", kind, "namespace", request.Namespace, "name", request.Name)
+}
 
-	// Build a reconciler context to pass around.
-	ctx, err := cr.newContext(request)
+func (cr *componentReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	defer func() {
+		reconcileDuration.WithLabelValues(cr.name).Observe(time.Since(start).Seconds())
+	}()
+
+	// Build a reconciler context to pass around, bounded so one hung API can't stall this
+	// worker forever.
+	reqCtx, cancel := context.WithTimeout(context.Background(), ReconcileTimeout)
+	defer cancel()
+
+	// Leave objects outside our namespaces or shard to whichever operator owns them.
+	inScope, err := OperatorScope.Contains(reqCtx, cr.client, request.Namespace)
+	if err != nil {
+		return reconcile.Result{Requeue: true}, err
+	}
+	if !inScope {
+		return reconcile.Result{}, nil
+	}
+	ctx, err := cr.newContext(reqCtx, request)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			// Top object not found, likely already deleted.
+			cr.errored.set(cr.name, request.NamespacedName, false)
 			return reconcile.Result{}, nil
 		}
 		// Some other fetch error, try again on the next tick.
 		return reconcile.Result{Requeue: true}, err
 	}
+	logger := ctx.Logger()
+	logger.Info("Reconciling")
 
 	// Make a clean copy of the top object to diff against later. This is used for
 	// diffing because the status subresource might not always be available.
@@ -148,33 +197,125 @@ func (cr *componentReconciler) Reconcile(request reconcile.Request) (reconcile.R
 	annotations := instance.GetAnnotations()
 	reconcileBlocked, ok := annotations["ridecell.io/skip-reconcile"]
 	if ok && reconcileBlocked == "true" {
-		glog.Infof("[%s] %s: Skipping Reconcile\n", request.NamespacedName, cr.name)
+		logger.Info("Skipping reconcile")
 		return reconcile.Result{}, nil
 	}
 
-	// Reconcile all the components.
-	// start := time.Now()
-	result, err := cr.reconcileComponents(ctx)
-	// fmt.Printf("$$$ Reconcile took %s\n", time.Since(start))
+	// Reconcile all the components, minus any paused by annotation.
+	skip := cr.skippedComponents(ctx)
+	result, err := cr.reconcileComponents(ctx, skip)
+
+	// Clean up anything the components no longer produce.
+	_, pruneErr := ctx.prune(cr.pruneTypes, cr.templateExists)
+	if pruneErr != nil {
+		logger.Error(pruneErr, "Error pruning")
+		if err == nil {
+			err = pruneErr
+			result.result.Requeue = true
+		}
+	}
+	cr.errored.set(cr.name, request.NamespacedName, err != nil)
+	reconciledModifier := ConditionModifier(ConditionReconciled, conditions.ConditionTrue, ReasonReconcileSuccess, "")
 	if err != nil {
-		// fmt.Printf("@@@@ Reconcile error %v\n", err)
+		logger.Error(err, "Reconcile error", "class", errors.ClassOf(err))
 		ctx.Top.(Statuser).SetErrorStatus(err.Error())
+		reconciledModifier = ConditionModifier(ConditionReconciled, conditions.ConditionFalse, ReasonReconcileError, err.Error())
+		applyErrorPolicy(&result.result, err)
+		reconcileErrors.WithLabelValues(cr.name, string(errors.ClassOf(err))).Inc()
+	}
+	// Report how any overlay patches went.
+	overlayModifier := ctx.overlayStatusModifier()
+	if overlayModifier != nil {
+		result.statusModifiers = append(result.statusModifiers, overlayModifier)
+		overlayModifier(ctx.Top) //nolint
 	}
+	// Report which components were paused.
+	skipModifier := skip.statusModifier(ctx.Top)
+	if skipModifier != nil {
+		result.statusModifiers = append(result.statusModifiers, skipModifier)
+		skipModifier(ctx.Top) //nolint
+	}
+	// Add any change to the overall status to the history.
+	transitionModifier := result.transitionModifier(cleanTop, err)
+	if transitionModifier != nil {
+		result.statusModifiers = append(result.statusModifiers, transitionModifier)
+		transitionModifier(ctx.Top) //nolint
+	}
+	// Record the overall outcome as a condition, and keep it in the modifier list so it survives a status write conflict.
+	result.statusModifiers = append(result.statusModifiers, reconciledModifier)
+	reconciledModifier(ctx.Top) //nolint
+
+	// The components may have used up the reconcile deadline, give the status write its own.
+	statusCtx, statusCancel := context.WithTimeout(context.Background(), StatusTimeout)
+	defer statusCancel()
+	ctx.Context = statusCtx
 
 	// Check if an update to the status subresource is required.
+	var statusErr error
 	if !reflect.DeepEqual(ctx.Top.(Statuser).GetStatus(), cleanTop.(Statuser).GetStatus()) {
 		// Update the top object status.
-		glog.V(2).Infof("[%s] Reconcile: Updating Status\n", request.NamespacedName)
-		err = cr.modifyStatus(ctx, result.statusModifiers)
+		logger.V(2).Info("Updating status")
+		statusErr = cr.modifyStatus(ctx, result.statusModifiers)
+	}
+
+	// The step annotation is one-shot, so remove it now the component has had its run. If it
+	// wasn't reconcilable yet or an earlier stage failed, leave it for the next reconcile.
+	if skip.step != "" && skip.stepRan {
+		err = cr.clearStep(ctx, skip.step)
 		if err != nil {
+			logger.Error(err, "Error clearing step annotation")
 			result.result.Requeue = true
-			return result.result, err
+		} else {
+			// Setting the same step again should be reported again.
+			cr.steps.seen(request.NamespacedName, "")
 		}
 	}
 
+	if statusErr != nil {
+		result.result.Requeue = true
+		cr.countRequeue(result.result)
+		return result.result, statusErr
+	}
+
+	cr.countRequeue(result.result)
 	return result.result, nil
 }
 
+// Combine the retry the components asked for with the policy for the error's class, so user
+// errors don't get retried in a tight loop and transient ones always get retried. Timers from
+// the components, like the hibernation or rollback deadlines, are kept unless the policy asks
+// for an earlier retry. Backoff drops them since the workqueue only backs off without a
+// RequeueAfter, and the retry works them out again anyway.
+func applyErrorPolicy(result *reconcile.Result, err error) {
+	policy := errors.PolicyFor(err)
+	if policy.Backoff {
+		result.Requeue = true
+		result.RequeueAfter = 0
+		return
+	}
+	if policy.RequeueAfter != 0 && (result.RequeueAfter == 0 || result.RequeueAfter > policy.RequeueAfter) {
+		result.RequeueAfter = policy.RequeueAfter
+	}
+}
+
+// Check if a template path exists in this reconciler's templates.
+func (cr *componentReconciler) templateExists(path string) bool {
+	if cr.templates == nil {
+		return true
+	}
+	return templates.Exists(cr.templates, path)
+}
+
+// Update the requeue metrics for the final result of a reconcile.
+func (cr *componentReconciler) countRequeue(result reconcile.Result) {
+	if result.Requeue {
+		reconcileRequeues.WithLabelValues(cr.name, "requeue").Inc()
+	}
+	if result.RequeueAfter > 0 {
+		reconcileRequeues.WithLabelValues(cr.name, "requeue_after").Inc()
+	}
+}
+
 // A holding struct for the overall result of a reconcileComponents call.
 type reconcilerResults struct {
 	// The current context.
@@ -185,11 +326,16 @@ type reconcilerResults struct {
 	statusModifiers []StatusModifier
 	// The most recent error.
 	err error
+	// The component that returned err, and the last one to change the overall status. Used to
+	// attribute status transitions, see transitions.go.
+	errComponent    string
+	statusComponent string
 }
 
 func (r *reconcilerResults) mergeResult(componentResult Result, component Component, err error) error {
 	if err != nil {
 		r.err = err
+		r.errComponent = componentName(component)
 	}
 	if componentResult.Requeue {
 		r.result.Requeue = true
@@ -199,10 +345,13 @@ func (r *reconcilerResults) mergeResult(componentResult Result, component Compon
 	}
 	if componentResult.StatusModifier != nil {
 		r.statusModifiers = append(r.statusModifiers, componentResult.StatusModifier)
+		before, _ := currentStatus(r.ctx.Top)
 		statusErr := componentResult.StatusModifier(r.ctx.Top)
+		if after, _ := currentStatus(r.ctx.Top); after != before {
+			r.statusComponent = componentName(component)
+		}
 		if statusErr != nil {
-			instance := r.ctx.Top.(metav1.Object)
-			glog.Errorf("[%s/%s] Error running status modifier from %#v: %s\n", instance.GetNamespace(), instance.GetName(), component, statusErr)
+			r.ctx.Logger().Error(statusErr, "Error running status modifier", "component", componentName(component))
 			if r.err == nil {
 				// If we already had a real error, don't mask it, otherwise propagate this error.
 				err = errors.Wrap(statusErr, "Error running initial status modifier")
@@ -213,25 +362,69 @@ func (r *reconcilerResults) mergeResult(componentResult Result, component Compon
 	return err
 }
 
-func (cr *componentReconciler) reconcileComponents(ctx *ComponentContext) (*reconcilerResults, error) {
-	instance := ctx.Top.(metav1.Object)
+func (cr *componentReconciler) reconcileComponents(ctx *ComponentContext, skip *skipSet) (*reconcilerResults, error) {
+	isReady := make([]bool, len(cr.components))
 	ready := []Component{}
-	for _, component := range cr.components {
-		glog.V(10).Infof("[%s/%s] reconcileComponents: Checking if %#v is available to reconcile", instance.GetNamespace(), instance.GetName(), component)
+	for i, component := range cr.components {
+		logger := ctx.Logger().WithValues("component", componentName(component))
+		if skip.skipped[i] {
+			logger.V(2).Info("Skipping paused component")
+			continue
+		}
+		logger.V(10).Info("Checking if component is available to reconcile")
 		if component.IsReconcilable(ctx) {
-			glog.V(9).Infof("[%s/%s] reconcileComponents: %#v is available to reconcile", instance.GetNamespace(), instance.GetName(), component)
+			logger.V(9).Info("Component is available to reconcile")
+			isReady[i] = true
 			ready = append(ready, component)
 		}
 	}
 	res := &reconcilerResults{ctx: ctx}
-	for _, component := range ready {
-		// fmt.Printf("### Reconciling %#v\n", component)
-		// start := time.Now()
-		innerRes, err := component.Reconcile(ctx)
-		// fmt.Printf("### Done reconciling %#v, took %s\n", component, time.Since(start))
-		// Update result. This should be checked before the err!=nil because sometimes
-		// we want to requeue immediately on error.
-		err = res.mergeResult(innerRes, component, err)
+	for _, stage := range cr.stages {
+		// Run all the ready components in this stage concurrently.
+		stageResults := make([]Result, len(stage))
+		stageErrs := make([]error, len(stage))
+		var wg sync.WaitGroup
+		for n, i := range stage {
+			if !isReady[i] {
+				continue
+			}
+			if skip.step != "" && skip.stepped == i {
+				skip.stepRan = true
+			}
+			wg.Add(1)
+			go func(n int, component Component) {
+				defer wg.Done()
+				componentStart := time.Now()
+				compCtx, cancel := ctx.forComponent(component)
+				defer cancel()
+				stageResults[n], stageErrs[n] = component.Reconcile(compCtx)
+				if stageErrs[n] != nil && compCtx.Context.Err() == context.DeadlineExceeded {
+					stageErrs[n] = errors.Transient(errors.Wrapf(stageErrs[n], "%s timed out", componentName(component)))
+				}
+				stageErrs[n] = classifyError(stageErrs[n])
+				componentDuration.WithLabelValues(cr.name, componentName(component)).Observe(time.Since(componentStart).Seconds())
+				if stageErrs[n] != nil {
+					componentErrors.WithLabelValues(cr.name, componentName(component)).Inc()
+				}
+			}(n, cr.components[i])
+		}
+		wg.Wait()
+
+		// Merge results in list order so status modifiers are applied deterministically. This
+		// should be checked before the err!=nil because sometimes we want to requeue immediately on error.
+		var err error
+		for n, i := range stage {
+			if !isReady[i] {
+				continue
+			}
+			if stageErrs[n] != nil {
+				ctx.Eventf(corev1.EventTypeWarning, "ComponentError", "%s: %s", componentName(cr.components[i]), stageErrs[n])
+			}
+			mergeErr := res.mergeResult(stageResults[n], cr.components[i], stageErrs[n])
+			if mergeErr != nil && err == nil {
+				err = mergeErr
+			}
+		}
 		if err != nil {
 			for _, errComponent := range ready {
 				errReconciler, ok := errComponent.(ErrorHandler)
@@ -239,12 +432,15 @@ func (cr *componentReconciler) reconcileComponents(ctx *ComponentContext) (*reco
 					// Not an error handler, push on.
 					continue
 				}
-				innerRes, errorErr := errReconciler.ReconcileError(ctx, err)
+				errCtx, cancel := ctx.forComponent(errComponent)
+				innerRes, errorErr := errReconciler.ReconcileError(errCtx, err)
+				cancel()
 				// Linting ignored "Error not handled", not an error that needs to be handled.
 				res.mergeResult(innerRes, errComponent, nil) //nolint
 				if errorErr != nil {
-					// Can't really do much more than log it, sigh. Some day this should set a prometheus metric.
-					glog.Errorf("[%s/%s] Error running error handler %#v: %s", instance.GetNamespace(), instance.GetName(), errComponent, errorErr)
+					// Can't really do much more than log it and count it, sigh.
+					errorHandlerErrors.WithLabelValues(cr.name, componentName(errComponent)).Inc()
+					ctx.Logger().Error(errorErr, "Error running error handler", "component", componentName(errComponent))
 				}
 			}
 			return res, err
@@ -263,6 +459,7 @@ func (cr *componentReconciler) modifyStatus(ctx *ComponentContext, statusModifie
 
 	// Something went wrong so we have to do a re-get an apply of the modifiers.
 	for tries := 0; tries < 5; tries++ {
+		statusUpdateRetries.WithLabelValues(cr.name).Inc()
 		err = cr.updateStatus(ctx, ctx.Top, func(instance runtime.Object) error {
 			for _, mod := range statusModifiers {
 				err := mod(instance)
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+var _ = Describe("Reconciler", func() {
+	var comp *testComponent
+	var c client.Client
+	var recorder *record.FakeRecorder
+
+	reconcileOnce := func(comps ...components.Component) reconcile.Result {
+		cr, rec, err := components.NewTestReconciler("test", &summonv1beta1.SummonPlatform{}, c, comps)
+		Expect(err).ToNot(HaveOccurred())
+		recorder = rec
+		result, err := cr.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		comp = newTestComponent("a", nil)
+		c = fake.NewFakeClient(instance)
+	})
+
+	Describe("events", func() {
+		It("emits events from components on the top object", func() {
+			comp.reconcile = func(ctx *components.ComponentContext) (components.Result, error) {
+				ctx.Eventf("Normal", "Hello", "from %s", "a")
+				return components.Result{}, nil
+			}
+			reconcileOnce(comp)
+			Expect(recorder.Events).To(Receive(Equal("Normal Hello from a")))
+			Expect(recorder.Events).ToNot(Receive())
+		})
+
+		It("emits an event for a component error", func() {
+			comp.reconcile = func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{}, errors.New("oops")
+			}
+			reconcileOnce(comp)
+			Expect(recorder.Events).To(Receive(Equal("Warning ComponentError test(a): oops")))
+		})
+	})
+
+	Describe("error policy", func() {
+		var timer *testComponent
+		var failing *testComponent
+		var failWith error
+
+		BeforeEach(func() {
+			timer = newTestComponent("timer", func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{RequeueAfter: 5 * time.Minute}, nil
+			})
+			failing = newTestComponent("failing", func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{}, failWith
+			})
+		})
+
+		It("keeps a component's timer on a permanent error", func() {
+			failWith = errors.Permanent(errors.New("bad spec"))
+			result := reconcileOnce(timer, failing)
+			Expect(result.Requeue).To(BeFalse())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+		})
+
+		It("uses the policy delay when it is shorter than the timer", func() {
+			failWith = errors.Waiting(errors.New("not yet"))
+			result := reconcileOnce(timer, failing)
+			Expect(result.RequeueAfter).To(Equal(errors.Policies[errors.ClassWaiting].RequeueAfter))
+		})
+
+		It("keeps the timer when it is shorter than the policy delay", func() {
+			failWith = errors.RateLimited(errors.New("slow down"), 10*time.Minute)
+			result := reconcileOnce(timer, failing)
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+		})
+
+		It("uses the retry delay from a rate limited error", func() {
+			failWith = errors.RateLimited(errors.New("slow down"), 2*time.Minute)
+			result := reconcileOnce(timer, failing)
+			Expect(result.RequeueAfter).To(Equal(2 * time.Minute))
+		})
+
+		It("retries AWS throttling after a delay", func() {
+			failWith = errors.Wrap(awserr.New("Throttling", "Rate exceeded", nil), "error describing instance")
+			result := reconcileOnce(failing)
+			Expect(result.Requeue).To(BeFalse())
+			Expect(result.RequeueAfter).To(Equal(errors.Policies[errors.ClassRateLimited].RequeueAfter))
+		})
+
+		It("leaves other AWS errors and classified throttling alone", func() {
+			failWith = awserr.New("InvalidParameterValue", "bad value", nil)
+			result := reconcileOnce(failing)
+			Expect(result.Requeue).To(BeTrue())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			failWith = errors.Permanent(awserr.New("Throttling", "Rate exceeded", nil))
+			result = reconcileOnce(failing)
+			Expect(result.Requeue).To(BeFalse())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+
+		It("backs off on a transient error", func() {
+			failWith = errors.Transient(errors.New("blip"))
+			result := reconcileOnce(timer, failing)
+			Expect(result.Requeue).To(BeTrue())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+	})
+
+	Describe("stepping a paused component", func() {
+		BeforeEach(func() {
+			instance.Annotations = map[string]string{
+				components.SkipComponentsAnnotation: "test(a)",
+				components.StepComponentAnnotation:  "test(a)",
+			}
+			c = fake.NewFakeClient(instance)
+		})
+
+		stepAnnotation := func() string {
+			fetched := &summonv1beta1.SummonPlatform{}
+			err := c.Get(context.Background(), types.NamespacedName{Name: "foo", Namespace: "default"}, fetched)
+			Expect(err).ToNot(HaveOccurred())
+			return fetched.Annotations[components.StepComponentAnnotation]
+		}
+
+		It("runs the component once and clears the annotation", func() {
+			reconcileOnce(comp)
+			Expect(comp.Calls()).To(Equal(1))
+			Expect(stepAnnotation()).To(BeEmpty())
+
+			reconcileOnce(comp)
+			Expect(comp.Calls()).To(Equal(1))
+		})
+
+		It("keeps the annotation while the component isn't reconcilable", func() {
+			comp.notReady = true
+			reconcileOnce(comp)
+			Expect(comp.Calls()).To(Equal(0))
+			Expect(stepAnnotation()).To(Equal("test(a)"))
+		})
+
+		It("keeps the annotation when an earlier stage fails", func() {
+			failing := newTestComponent("b", func(_ *components.ComponentContext) (components.Result, error) {
+				return components.Result{}, errors.New("oops")
+			})
+			reconcileOnce(failing, comp)
+			Expect(comp.Calls()).To(Equal(0))
+			Expect(stepAnnotation()).To(Equal("test(a)"))
+		})
+
+		It("only reports an unusable step annotation once", func() {
+			instance.Annotations[components.StepComponentAnnotation] = "test(other)"
+			c = fake.NewFakeClient(instance)
+			cr, rec, err := components.NewTestReconciler("test", &summonv1beta1.SummonPlatform{}, c, []components.Component{comp})
+			Expect(err).ToNot(HaveOccurred())
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+
+			_, err = cr.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Events).To(Receive(Equal(`Warning StepIgnored ridecell.io/step-component "test(other)" matches 0 paused components, expected exactly one`)))
+
+			_, err = cr.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Events).ToNot(Receive())
+			Expect(comp.Calls()).To(Equal(0))
+			Expect(stepAnnotation()).To(Equal("test(other)"))
+		})
+	})
+})
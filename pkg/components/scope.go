@@ -0,0 +1,177 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// Which top objects this copy of the operator is responsible for. The zero value covers
+// everything, which is the normal single-replica deployment.
+type Scope struct {
+	// Only reconcile objects in these namespaces. Empty means all namespaces.
+	Namespaces []string
+	// Only reconcile objects in namespaces whose labels match. Nil means all namespaces.
+	NamespaceSelector labels.Selector
+	// Split namespaces between ShardCount replicas by hash, this replica takes ShardIndex. A
+	// ShardCount of 0 or 1 means no sharding.
+	ShardIndex int
+	ShardCount int
+}
+
+// The scope for every reconciler in this process, set from flags in cmd/manager.
+var OperatorScope Scope
+
+// Workers per controller, keyed by controller name with or without the "-controller" suffix.
+// Controllers not listed get DefaultMaxConcurrentReconciles.
+var MaxConcurrentReconciles = map[string]int{}
+
+var DefaultMaxConcurrentReconciles = 1
+
+func (s Scope) Validate() error {
+	if s.ShardCount < 0 || s.ShardIndex < 0 {
+		return errors.Errorf("shard index and count must not be negative")
+	}
+	if s.ShardCount > 1 && s.ShardIndex >= s.ShardCount {
+		return errors.Errorf("shard index %d is out of range for %d shards", s.ShardIndex, s.ShardCount)
+	}
+	return nil
+}
+
+// Name of the leader election lock for this scope. Copies of the operator with different
+// scopes run side by side and each needs its own lock, while replicas of one copy share it.
+// The zero value returns "" to keep controller-runtime's default lock.
+func (s Scope) LeaderElectionID() string {
+	id := "ridecell-operator"
+	selector := ""
+	if s.NamespaceSelector != nil {
+		selector = s.NamespaceSelector.String()
+	}
+	if len(s.Namespaces) != 0 || selector != "" {
+		// Hash rather than list the namespaces, the ID has to stay a valid object name.
+		namespaces := append([]string{}, s.Namespaces...)
+		sort.Strings(namespaces)
+		h := fnv.New32a()
+		h.Write([]byte(strings.Join(namespaces, ",") + ";" + selector)) //nolint
+		id += fmt.Sprintf("-scope-%08x", h.Sum32())
+	}
+	if s.ShardCount > 1 {
+		id += fmt.Sprintf("-shard-%d", s.ShardIndex)
+	}
+	if id == "ridecell-operator" {
+		return ""
+	}
+	return id
+}
+
+// Check if objects in a namespace belong to this copy of the operator. Cluster-scoped objects
+// have an empty namespace and are shared by every copy, like the OperatorConfig.
+func (s Scope) Contains(ctx context.Context, c client.Client, namespace string) (bool, error) {
+	if namespace == "" {
+		return true, nil
+	}
+	if !s.inShard(namespace) {
+		return false, nil
+	}
+	if len(s.Namespaces) != 0 && !containsString(s.Namespaces, namespace) {
+		return false, nil
+	}
+	if s.NamespaceSelector != nil && !s.NamespaceSelector.Empty() {
+		ns := &corev1.Namespace{}
+		err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+		if err != nil {
+			return false, errors.Wrapf(err, "error getting namespace %s to check its labels", namespace)
+		}
+		if !s.NamespaceSelector.Matches(labels.Set(ns.Labels)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Check if a namespace hashes to this shard.
+func (s Scope) inShard(namespace string) bool {
+	if s.ShardCount <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(namespace)) //nolint
+	return int(h.Sum32()%uint32(s.ShardCount)) == s.ShardIndex
+}
+
+// Split a comma-separated namespace list, dropping blanks.
+func ParseNamespaces(value string) []string {
+	namespaces := []string{}
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// Parse a list of per-controller worker counts like "summon-platform=4,rds=2".
+func ParseMaxConcurrentReconciles(value string) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid entry %q, expected controller=count", entry)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || count < 1 {
+			return nil, errors.Errorf("invalid count in %q, expected a positive number", entry)
+		}
+		counts[strings.TrimSuffix(strings.TrimSpace(parts[0]), "-controller")] = count
+	}
+	return counts, nil
+}
+
+// Look up the number of workers for a controller.
+func maxConcurrentReconciles(name string) int {
+	count, ok := MaxConcurrentReconciles[strings.TrimSuffix(name, "-controller")]
+	if ok {
+		return count
+	}
+	return DefaultMaxConcurrentReconciles
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+)
+
+var _ = Describe("Scope", func() {
+	var c client.Client
+
+	BeforeEach(func() {
+		c = fake.NewFakeClient(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "summon-dev", Labels: map[string]string{"team": "summon"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"team": "other"}}},
+		)
+	})
+
+	contains := func(scope components.Scope, namespace string) bool {
+		ok, err := scope.Contains(context.Background(), c, namespace)
+		Expect(err).ToNot(HaveOccurred())
+		return ok
+	}
+
+	Describe("Contains", func() {
+		It("covers everything with the zero value", func() {
+			Expect(contains(components.Scope{}, "summon-dev")).To(BeTrue())
+			Expect(contains(components.Scope{}, "missing")).To(BeTrue())
+		})
+
+		It("always covers cluster-scoped objects", func() {
+			scope := components.Scope{Namespaces: []string{"other"}, ShardIndex: 1, ShardCount: 2}
+			Expect(contains(scope, "")).To(BeTrue())
+		})
+
+		It("limits to the listed namespaces", func() {
+			scope := components.Scope{Namespaces: []string{"summon-dev"}}
+			Expect(contains(scope, "summon-dev")).To(BeTrue())
+			Expect(contains(scope, "other")).To(BeFalse())
+		})
+
+		It("limits to namespaces matching the selector", func() {
+			scope := components.Scope{NamespaceSelector: labels.SelectorFromSet(labels.Set{"team": "summon"})}
+			Expect(contains(scope, "summon-dev")).To(BeTrue())
+			Expect(contains(scope, "other")).To(BeFalse())
+		})
+
+		It("ignores an empty selector", func() {
+			scope := components.Scope{NamespaceSelector: labels.Everything()}
+			Expect(contains(scope, "missing")).To(BeTrue())
+		})
+
+		It("returns an error for a missing namespace with a selector", func() {
+			scope := components.Scope{NamespaceSelector: labels.SelectorFromSet(labels.Set{"team": "summon"})}
+			_, err := scope.Contains(context.Background(), c, "missing")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("puts each namespace in exactly one shard", func() {
+			counts := make([]int, 3)
+			for i := 0; i < 300; i++ {
+				namespace := fmt.Sprintf("summon-%d", i)
+				owners := 0
+				for shard := range counts {
+					if contains(components.Scope{ShardIndex: shard, ShardCount: 3}, namespace) {
+						owners++
+						counts[shard]++
+					}
+				}
+				Expect(owners).To(Equal(1), namespace)
+			}
+			for _, count := range counts {
+				Expect(count).To(BeNumerically(">", 50))
+			}
+		})
+
+		It("treats a shard count of one as no sharding", func() {
+			Expect(contains(components.Scope{ShardCount: 1}, "summon-dev")).To(BeTrue())
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts the zero value and in range shards", func() {
+			Expect(components.Scope{}.Validate()).To(Succeed())
+			Expect(components.Scope{ShardIndex: 2, ShardCount: 3}.Validate()).To(Succeed())
+		})
+
+		It("rejects negative values", func() {
+			Expect(components.Scope{ShardCount: -1}.Validate()).ToNot(Succeed())
+			Expect(components.Scope{ShardIndex: -1}.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects an index past the shard count", func() {
+			Expect(components.Scope{ShardIndex: 3, ShardCount: 3}.Validate()).To(MatchError("shard index 3 is out of range for 3 shards"))
+		})
+	})
+
+	Describe("LeaderElectionID", func() {
+		It("keeps the default lock for the zero value", func() {
+			Expect(components.Scope{}.LeaderElectionID()).To(Equal(""))
+			Expect(components.Scope{ShardCount: 1}.LeaderElectionID()).To(Equal(""))
+		})
+
+		It("uses one lock per shard", func() {
+			Expect(components.Scope{ShardIndex: 1, ShardCount: 3}.LeaderElectionID()).To(Equal("ridecell-operator-shard-1"))
+		})
+
+		It("uses different locks for different namespaces", func() {
+			dev := components.Scope{Namespaces: []string{"summon-dev"}}.LeaderElectionID()
+			qa := components.Scope{Namespaces: []string{"summon-qa"}}.LeaderElectionID()
+			Expect(dev).To(HavePrefix("ridecell-operator-scope-"))
+			Expect(qa).To(HavePrefix("ridecell-operator-scope-"))
+			Expect(dev).ToNot(Equal(qa))
+		})
+
+		It("ignores the order of the namespaces", func() {
+			a := components.Scope{Namespaces: []string{"summon-dev", "summon-qa"}}.LeaderElectionID()
+			b := components.Scope{Namespaces: []string{"summon-qa", "summon-dev"}}.LeaderElectionID()
+			Expect(a).To(Equal(b))
+		})
+
+		It("uses different locks for different selectors", func() {
+			summon := components.Scope{NamespaceSelector: labels.SelectorFromSet(labels.Set{"team": "summon"})}.LeaderElectionID()
+			other := components.Scope{NamespaceSelector: labels.SelectorFromSet(labels.Set{"team": "other"})}.LeaderElectionID()
+			Expect(summon).To(HavePrefix("ridecell-operator-scope-"))
+			Expect(summon).ToNot(Equal(other))
+			Expect(components.Scope{NamespaceSelector: labels.Everything()}.LeaderElectionID()).To(Equal(""))
+		})
+
+		It("combines the namespace scope and the shard", func() {
+			id := components.Scope{Namespaces: []string{"summon-dev"}, ShardIndex: 2, ShardCount: 3}.LeaderElectionID()
+			Expect(id).To(MatchRegexp(`^ridecell-operator-scope-[0-9a-f]{8}-shard-2$`))
+		})
+	})
+
+	Describe("ParseNamespaces", func() {
+		It("splits and trims the list", func() {
+			Expect(components.ParseNamespaces(" summon-dev, ,other,")).To(Equal([]string{"summon-dev", "other"}))
+		})
+
+		It("returns an empty list for an empty value", func() {
+			Expect(components.ParseNamespaces("")).To(BeEmpty())
+		})
+	})
+
+	Describe("ParseMaxConcurrentReconciles", func() {
+		AfterEach(func() {
+			components.MaxConcurrentReconciles = map[string]int{}
+		})
+
+		It("parses the counts and drops the controller suffix", func() {
+			counts, err := components.ParseMaxConcurrentReconciles("summon-platform-controller=4, rds=2,")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int{"summon-platform": 4, "rds": 2}))
+
+			components.MaxConcurrentReconciles = counts
+			Expect(components.MaxConcurrentReconcilesFor("summon-platform-controller")).To(Equal(4))
+			Expect(components.MaxConcurrentReconcilesFor("rds")).To(Equal(2))
+			Expect(components.MaxConcurrentReconcilesFor("s3bucket-controller")).To(Equal(components.DefaultMaxConcurrentReconciles))
+		})
+
+		It("rejects an entry without a count", func() {
+			_, err := components.ParseMaxConcurrentReconciles("rds")
+			Expect(err).To(MatchError(`invalid entry "rds", expected controller=count`))
+		})
+
+		It("rejects a count below one", func() {
+			_, err := components.ParseMaxConcurrentReconciles("rds=0")
+			Expect(err).To(MatchError(`invalid count in "rds=0", expected a positive number`))
+			_, err = components.ParseMaxConcurrentReconciles("rds=many")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
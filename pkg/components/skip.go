@@ -0,0 +1,173 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+const (
+	// Comma-separated component names to pause on one object while everything else keeps
+	// converging. An entry is either a full component name like "deployment(web/deployment.yml.tpl)"
+	// or just the part before the template path, like "migration", to match every instance.
+	SkipComponentsAnnotation = "ridecell.io/skip-components"
+	// Name of one skipped component to run on the next reconcile only. The annotation is removed
+	// again once that reconcile finishes.
+	StepComponentAnnotation = "ridecell.io/step-component"
+)
+
+// Which components to leave out of a reconcile.
+type skipSet struct {
+	// Indexes into the reconciler's components.
+	skipped map[int]bool
+	// Sorted names of the skipped components, for status.
+	names []string
+	// The step annotation value if it let a component run, so it can be cleared afterwards.
+	step string
+	// Index of the component let through by the step annotation, only set along with step.
+	stepped int
+	// If the stepped component's Reconcile was called. The annotation stays until it has.
+	stepRan bool
+}
+
+// The step annotation last seen on each top object, so the events about it are only emitted
+// once rather than on every reconcile until it's used or removed.
+type stepTracker struct {
+	lock  sync.Mutex
+	steps map[types.NamespacedName]string
+}
+
+// Record the step annotation on an object, returning true if it changed since last time.
+func (t *stepTracker) seen(name types.NamespacedName, step string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.steps == nil {
+		t.steps = map[types.NamespacedName]string{}
+	}
+	changed := t.steps[name] != step
+	if step == "" {
+		delete(t.steps, name)
+	} else {
+		t.steps[name] = step
+	}
+	return changed
+}
+
+// Check if a component name matches an entry from the skip or step annotations.
+func componentMatches(name, entry string) bool {
+	if name == entry {
+		return true
+	}
+	paren := strings.Index(name, "(")
+	return paren != -1 && name[:paren] == entry
+}
+
+// Work out which components the annotations on the top object pause for this reconcile.
+func (cr *componentReconciler) skippedComponents(ctx *ComponentContext) *skipSet {
+	skip := &skipSet{skipped: map[int]bool{}}
+	top := ctx.Top.(metav1.Object)
+	annotations := top.GetAnnotations()
+	for _, entry := range strings.Split(annotations[SkipComponentsAnnotation], ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		for i, comp := range cr.components {
+			if componentMatches(componentName(comp), entry) {
+				skip.skipped[i] = true
+			}
+		}
+	}
+
+	// Let a single paused component through, if requested.
+	step := strings.TrimSpace(annotations[StepComponentAnnotation])
+	changed := cr.steps.seen(types.NamespacedName{Name: top.GetName(), Namespace: top.GetNamespace()}, step)
+	if step != "" {
+		matched := []int{}
+		for i := range skip.skipped {
+			if componentMatches(componentName(cr.components[i]), step) {
+				matched = append(matched, i)
+			}
+		}
+		if len(matched) == 1 {
+			delete(skip.skipped, matched[0])
+			skip.step = annotations[StepComponentAnnotation]
+			skip.stepped = matched[0]
+			if changed {
+				ctx.Eventf(corev1.EventTypeNormal, "ComponentStepped", "running paused component %s once", componentName(cr.components[matched[0]]))
+			}
+		} else if changed {
+			ctx.Eventf(corev1.EventTypeWarning, "StepIgnored", "%s %q matches %d paused components, expected exactly one", StepComponentAnnotation, step, len(matched))
+		} else {
+			ctx.Logger().V(1).Info("Ignoring step annotation", "step", step, "matches", len(matched))
+		}
+	}
+
+	for i := range skip.skipped {
+		skip.names = append(skip.names, componentName(cr.components[i]))
+	}
+	sort.Strings(skip.names)
+	return skip
+}
+
+// A StatusModifier recording the skipped components, or nil if the top object doesn't report them.
+func (skip *skipSet) statusModifier(top runtime.Object) StatusModifier {
+	_, ok := top.(SkipStatuser)
+	if !ok {
+		return nil
+	}
+	names := skip.names
+	return func(obj runtime.Object) error {
+		obj.(SkipStatuser).SetSkippedComponents(names)
+		return nil
+	}
+}
+
+// Remove the step annotation after it has been used, unless someone changed it in the meantime.
+func (cr *componentReconciler) clearStep(ctx *ComponentContext, step string) error {
+	top := ctx.Top.(metav1.Object)
+	fresh := cr.top.DeepCopyObject()
+	err := ctx.Get(ctx.Context, types.NamespacedName{Name: top.GetName(), Namespace: top.GetNamespace()}, fresh)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "error getting top object to clear step annotation")
+	}
+	freshMeta := fresh.(metav1.Object)
+	annotations := freshMeta.GetAnnotations()
+	if annotations[StepComponentAnnotation] != step {
+		return nil
+	}
+	delete(annotations, StepComponentAnnotation)
+	freshMeta.SetAnnotations(annotations)
+	err = ctx.Update(ctx.Context, fresh)
+	if err != nil {
+		return errors.Wrap(err, "error clearing step annotation")
+	}
+	return nil
+}
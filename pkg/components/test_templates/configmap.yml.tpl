@@ -0,0 +1,8 @@
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Instance.Name }}-config
+  namespace: {{ .Instance.Namespace }}
+data:
+  version: {{ .Instance.Spec.Version | quote }}
+  extra: {{ .Extra.value | quote }}
@@ -0,0 +1,7 @@
+{{ if .Extra.enabled }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Instance.Name }}-optional
+  namespace: {{ .Instance.Namespace }}
+{{ end }}
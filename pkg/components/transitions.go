@@ -0,0 +1,59 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/transitions"
+)
+
+// Get the overall status of a top object, or empty strings if it doesn't keep a history.
+func currentStatus(obj runtime.Object) (string, string) {
+	recorder, ok := obj.(TransitionRecorder)
+	if !ok {
+		return "", ""
+	}
+	return recorder.CurrentStatus()
+}
+
+// Build a status modifier recording the change in overall status since the start of the
+// reconcile, or nil if it didn't change. The transition is built once up front so replaying the
+// modifiers after a write conflict records the same entry rather than a new one.
+//
+// Only the start and end of each reconcile are compared, so components flipping the status back
+// and forth within one pass don't flood the history.
+func (r *reconcilerResults) transitionModifier(cleanTop runtime.Object, err error) StatusModifier {
+	_, ok := r.ctx.Top.(TransitionRecorder)
+	if !ok {
+		return nil
+	}
+	from, _ := currentStatus(cleanTop)
+	to, message := currentStatus(r.ctx.Top)
+	if from == to {
+		return nil
+	}
+	component := r.statusComponent
+	if err != nil {
+		component = r.errComponent
+	}
+	transition := transitions.New(from, to, component, message)
+	return func(obj runtime.Object) error {
+		obj.(TransitionRecorder).RecordTransition(transition)
+		return nil
+	}
+}
@@ -21,12 +21,19 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/transitions"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 )
 
 // // A componentReconciler is the data for a single reconciler. These are our
@@ -36,18 +43,37 @@ type componentReconciler struct {
 	top        runtime.Object
 	templates  http.FileSystem
 	components []Component
+	// Execution stages, as indexes into components. See buildStages.
+	stages     [][]int
 	client     client.Client
 	manager    manager.Manager
+	recorder   record.EventRecorder
 	Controller controller.Controller
+	// Top objects whose last reconcile failed, for metrics.
+	errored erroredObjectTracker
+	// Step annotations already reported on, see skip.go.
+	steps stepTracker
+	// Owned object types to look through when pruning.
+	pruneTypes []runtime.Object
 }
 
 // A ComponentContext is the state for a single reconcile request to the controller.
 type ComponentContext struct {
 	client.Client
 	templates http.FileSystem
-	Context   context.Context // This should probably go away
-	Top       runtime.Object
-	Scheme    *runtime.Scheme
+	// Cancelled when the reconcile is abandoned or the running component hits its timeout. Pass
+	// this to every external API call.
+	Context context.Context
+	Top     runtime.Object
+	Scheme  *runtime.Scheme
+	// Recorder for Events on the top object, see Event and Eventf.
+	Recorder record.EventRecorder
+	// Objects rendered by CreateOrUpdate during this reconcile, see prune.go. Nil when not pruning.
+	inventory *inventory
+	// Structured logger tagged with this reconcile, see Logger.
+	logger logr.Logger
+	// Operator settings as of the start of this reconcile, see Config.
+	config *config.Config
 }
 
 // A function which modifies component status.
@@ -88,4 +114,35 @@ type Statuser interface {
 	GetStatus() Status
 	SetStatus(Status)
 	SetErrorStatus(string)
+	// Typed conditions stored alongside the status, see SetCondition.
+	GetConditions() []conditions.Condition
+	SetConditions([]conditions.Condition)
+}
+
+// An optional interface for components which need a different deadline than ComponentTimeout,
+// like ones waiting on slow external APIs.
+type Timeouter interface {
+	Timeout() time.Duration
+}
+
+// An optional interface for top-level objects which accept overlay patches for their rendered
+// objects. Patches are applied in CreateOrUpdate and the outcomes are reported back in status.
+type Overlayer interface {
+	GetOverlays() []overlays.Patch
+	GetOverlayStatus() []overlays.PatchStatus
+	SetOverlayStatus([]overlays.PatchStatus)
+}
+
+// An optional interface for top-level objects which report the components skipped by the
+// SkipComponentsAnnotation. See skip.go.
+type SkipStatuser interface {
+	SetSkippedComponents([]string)
+}
+
+// An optional interface for top-level objects which keep a history of changes to their overall
+// status. See transitions.go.
+type TransitionRecorder interface {
+	// The overall status and its message, like "Migrating" and "Running migrations".
+	CurrentStatus() (string, string)
+	RecordTransition(transitions.Transition)
 }
@@ -0,0 +1,211 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// A setting components can read through Config.Get. The name is the environment variable it
+// used to come from, which is still used as a fallback.
+type Setting struct {
+	Name string
+	// Credentials have to come from a secret in the OperatorConfig, never a plain value.
+	Secret bool
+}
+
+// Every setting an OperatorConfig may set.
+var Settings = []Setting{
+	{Name: "ALERTMANAGER_AUTH", Secret: true},
+	{Name: "ALERTMANAGER_NAME"},
+	{Name: "AWS_REGION"},
+	{Name: "AWS_SUBNET_GROUP_NAME"},
+	{Name: "DEFAULT_PERMISSIONS_BOUNDARY_ARN"},
+	{Name: "DEPLOY_STAT_URL"},
+	{Name: "FIREBASE_DATABASE_DEFAULT_RULES"},
+	{Name: "GOOGLE_BILLING_ACCOUNT_NAME"},
+	{Name: "GOOGLE_SERVICE_ACCOUNT_KEY", Secret: true},
+	{Name: "LOCAL_REGISTRY_URL"},
+	{Name: "MOCKCARSERVER_AUTH", Secret: true},
+	{Name: "MOCKCARSERVER_URI"},
+	{Name: "NAMESPACE"},
+	{Name: "PERMISSIONS_BOUNDARY_ARN"},
+	{Name: "PG_API_KEY", Secret: true},
+	{Name: "PG_MOCK_URL"},
+	{Name: "PG_ROUTING_KEY", Secret: true},
+	{Name: "PROMETHEUS_URL"},
+	{Name: "RABBITMQ_INSECURE"},
+	{Name: "RABBITMQ_URI", Secret: true},
+	{Name: "SLACK_API_KEY", Secret: true},
+	{Name: "SUMO_ACCESS_ID", Secret: true},
+	{Name: "SUMO_ACCESS_KEY", Secret: true},
+	{Name: "SUMO_MOCK_URL"},
+	{Name: "TRUSTED_ROLE_ARNS"},
+}
+
+// Named feature gates.
+const (
+	// Clean up external resources when their object is deleted, see components.Finalizer.
+	FeatureFinalizers = "Finalizers"
+	// Use the deployment-based SummonPlatform status checks.
+	FeatureNewStatusCheck = "NewStatusCheck"
+)
+
+// A feature gate, off unless turned on by the OperatorConfig or its old environment variable.
+type Gate struct {
+	Name   string
+	EnvVar string
+}
+
+var Gates = []Gate{
+	{Name: FeatureFinalizers, EnvVar: "ENABLE_FINALIZERS"},
+	{Name: FeatureNewStatusCheck, EnvVar: "ENABLE_NEW_STATUS_CHECK"},
+}
+
+// Where a setting's value came from.
+const (
+	SourceOperatorConfig = "OperatorConfig"
+	SourceSecret         = "Secret"
+	SourceEnvironment    = "Environment"
+	SourceUnset          = "Unset"
+)
+
+// A resolved operator configuration. Anything not set here falls back to the environment, so a
+// nil Config is just the old env-only behavior.
+type Config struct {
+	values  map[string]string
+	sources map[string]string
+	gates   map[string]bool
+}
+
+// Build a Config from resolved values, with the source of each value keyed by setting name.
+func New(values map[string]string, sources map[string]string, gates map[string]bool) *Config {
+	return &Config{values: values, sources: sources, gates: gates}
+}
+
+func (c *Config) Get(name string) string {
+	if c != nil {
+		value, ok := c.values[name]
+		if ok {
+			return value
+		}
+	}
+	return os.Getenv(name)
+}
+
+// Describe where a setting's value comes from, without revealing it.
+func (c *Config) Source(name string) string {
+	if c != nil {
+		_, ok := c.values[name]
+		if ok {
+			return c.sources[name]
+		}
+	}
+	if os.Getenv(name) != "" {
+		return SourceEnvironment
+	}
+	return SourceUnset
+}
+
+func (c *Config) Enabled(gate string) bool {
+	if c != nil {
+		enabled, ok := c.gates[gate]
+		if ok {
+			return enabled
+		}
+	}
+	for _, g := range Gates {
+		if g.Name == gate {
+			return os.Getenv(g.EnvVar) == "true"
+		}
+	}
+	return false
+}
+
+// The state of every known feature gate.
+func (c *Config) EnabledGates() map[string]bool {
+	gates := map[string]bool{}
+	for _, g := range Gates {
+		gates[g.Name] = c.Enabled(g.Name)
+	}
+	return gates
+}
+
+// Check the names used in an OperatorConfig. Secret settings can't be given as plain values.
+func Validate(plain []string, secret []string, gates []string) error {
+	known := map[string]Setting{}
+	for _, setting := range Settings {
+		known[setting.Name] = setting
+	}
+	problems := []string{}
+	for _, name := range plain {
+		setting, ok := known[name]
+		if !ok {
+			problems = append(problems, "unknown setting "+name)
+		} else if setting.Secret {
+			problems = append(problems, name+" is a credential and must come from a secret")
+		}
+	}
+	for _, name := range secret {
+		_, ok := known[name]
+		if !ok {
+			problems = append(problems, "unknown setting "+name)
+		}
+	}
+	for _, name := range gates {
+		found := false
+		for _, g := range Gates {
+			if g.Name == name {
+				found = true
+			}
+		}
+		if !found {
+			problems = append(problems, "unknown feature gate "+name)
+		}
+	}
+	if len(problems) != 0 {
+		sort.Strings(problems)
+		return errors.Errorf("invalid operator config: %s", strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+var current atomic.Value
+
+// The configuration in effect, nil until an OperatorConfig has been loaded.
+func Current() *Config {
+	c, _ := current.Load().(*Config)
+	return c
+}
+
+func SetCurrent(c *Config) {
+	current.Store(c)
+}
+
+// Shortcuts for code without a ComponentContext.
+func Get(name string) string {
+	return Current().Get(name)
+}
+
+func Enabled(gate string) bool {
+	return Current().Enabled(gate)
+}
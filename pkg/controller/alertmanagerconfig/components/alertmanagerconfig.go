@@ -18,11 +18,9 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
-	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -83,7 +81,7 @@ func (comp *alertManageConfigComponent) Reconcile(ctx *components.ComponentConte
 			routetype := &alertconfig.Route{}
 			errRo := yaml.Unmarshal([]byte(config.Spec.Route), routetype)
 			if errRo != nil {
-				glog.Errorf("failed to load Routes form yaml for %s in %s", config.Name, config.Namespace)
+				ctx.Logger().Error(errRo, "Failed to load Routes from yaml", "configNamespace", config.Namespace, "configName", config.Name)
 			}
 			defaultConfig.Route.Routes = append(defaultConfig.Route.Routes, routetype)
 			// Receivers
@@ -91,7 +89,7 @@ func (comp *alertManageConfigComponent) Reconcile(ctx *components.ComponentConte
 				receivertype := &alertconfig.Receiver{}
 				errRe := yaml.Unmarshal([]byte(receiver), receivertype)
 				if errRe != nil {
-					glog.Errorf("failed to load Receivers form yaml for %s in %s", config.Name, config.Namespace)
+					ctx.Logger().Error(errRe, "Failed to load Receivers from yaml", "configNamespace", config.Namespace, "configName", config.Name)
 				}
 				defaultConfig.Receivers = append(defaultConfig.Receivers, receivertype)
 			}
@@ -109,7 +107,7 @@ func (comp *alertManageConfigComponent) Reconcile(ctx *components.ComponentConte
 	// on Marshal config relplace SecretURL with <secret> string.
 	finalConfigStr := strings.Replace(string(finalConfig), "slack_api_url: <secret>", fmt.Sprintf("slack_api_url: %s", defaultConfig.Global.SlackAPIURL.String()), 1)
 	finalConfigStr = strings.Replace(string(finalConfigStr), "api_url: <secret>", fmt.Sprintf("api_url: %s", defaultConfig.Global.SlackAPIURL.String()), -1)
-	finalConfigStr = strings.Replace(string(finalConfigStr), "routing_key: <secret>", fmt.Sprintf("routing_key: %s", os.Getenv("PG_ROUTING_KEY")), -1)
+	finalConfigStr = strings.Replace(string(finalConfigStr), "routing_key: <secret>", fmt.Sprintf("routing_key: %s", ctx.Config().Get("PG_ROUTING_KEY")), -1)
 	finalConfig = []byte(finalConfigStr)
 	// Create/Update secret with finalConfig which prometheus-operator can attach to alertmanager
 	// https://github.com/coreos/prometheus-operator/blob/master/Documentation/user-guides/alerting.md
@@ -126,7 +124,7 @@ func (comp *alertManageConfigComponent) Reconcile(ctx *components.ComponentConte
 		Name:      fmt.Sprintf("alertmanager-%s", instance.Spec.AlertManagerName),
 	}, alertConfigFinal)
 	if err != nil {
-		glog.Infof("creating config as secret for %s", instance.Spec.AlertManagerName)
+		ctx.Logger().Info("Creating config as secret", "alertManagerName", instance.Spec.AlertManagerName)
 		alertConfigFinal.Data = map[string][]byte{"alertmanager.yaml": []byte(finalConfig)}
 		// Remove "alertmanager.yaml" key from DefaultData  adding rest DefaultData. So we can keep rest of the templates.
 		delete(defaultConfigSecret.Data, "alertmanager.yaml")
@@ -17,11 +17,11 @@ limitations under the License.
 package components
 
 import (
-	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
 )
 
 type defaultsComponent struct{}
@@ -42,10 +42,9 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	instance := ctx.Top.(*dbv1beta1.DbConfig)
 
 	// Check for an invalid configuration.
-	if instance.Spec.Postgres.RDS == nil && instance.Spec.Postgres.Local == nil {
-		return components.Result{}, errors.New("Must specify RDS or Local postgres configuration")
-	} else if instance.Spec.Postgres.RDS != nil && instance.Spec.Postgres.Local != nil {
-		return components.Result{}, errors.New("Cannot specify both RDS and Local postgres configuration")
+	err := Validate(instance)
+	if err != nil {
+		return components.Result{}, errors.InvalidSpec(err)
 	}
 
 	return components.Result{}, nil
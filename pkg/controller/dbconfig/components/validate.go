@@ -0,0 +1,33 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/pkg/errors"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+)
+
+// Check a DbConfig spec for an invalid configuration. Shared by the defaults component and the admission webhook.
+func Validate(instance *dbv1beta1.DbConfig) error {
+	if instance.Spec.Postgres.RDS == nil && instance.Spec.Postgres.Local == nil {
+		return errors.New("Must specify RDS or Local postgres configuration")
+	} else if instance.Spec.Postgres.RDS != nil && instance.Spec.Postgres.Local != nil {
+		return errors.New("Cannot specify both RDS and Local postgres configuration")
+	}
+	return nil
+}
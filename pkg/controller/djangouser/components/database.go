@@ -87,7 +87,7 @@ INSERT INTO auth_user (username, password, first_name, last_name, email, is_acti
   RETURNING id;`
 
 	// Create the auth_user.
-	row := db.QueryRow(query, instance.Spec.Email, hashedPassword, instance.Spec.FirstName, instance.Spec.LastName, instance.Spec.Active, instance.Spec.Staff, instance.Spec.Superuser)
+	row := db.QueryRowContext(ctx.Context, query, instance.Spec.Email, hashedPassword, instance.Spec.FirstName, instance.Spec.LastName, instance.Spec.Active, instance.Spec.Staff, instance.Spec.Superuser)
 	var id int
 	err = row.Scan(&id)
 	if err != nil {
@@ -103,7 +103,7 @@ INSERT INTO common_userprofile (user_id, is_jumio_verified, created_at, updated_
   RETURNING id;`
 
 	// Create the common_userprofile.
-	row = db.QueryRow(query, id)
+	row = db.QueryRowContext(ctx.Context, query, id)
 	var profileId int
 	err = row.Scan(&profileId)
 	if err != nil {
@@ -122,7 +122,7 @@ INSERT INTO common_staff (user_profile_id, is_active, manager, dispatcher, busin
 `
 
 	// Create the common_staff.
-	_, err = db.Exec(query, profileId, instance.Spec.Active, instance.Spec.Manager, instance.Spec.Dispatcher, instance.Spec.BusinessAdmin)
+	_, err = db.ExecContext(ctx.Context, query, profileId, instance.Spec.Active, instance.Spec.Manager, instance.Spec.Dispatcher, instance.Spec.BusinessAdmin)
 	if err != nil {
 		return components.Result{}, errors.Wrap(err, "database: Error running common_staff query")
 	}
@@ -17,8 +17,6 @@ limitations under the License.
 package components
 
 import (
-	"os"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/rds"
@@ -56,7 +54,7 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	instance := ctx.Top.(*awsv1beta1.ElasticSearch)
 
 	// Populate the VPC, Subnet and Sucurity group
-	describeDBSubnetGroupOutput, err := comp.rdsAPI.DescribeDBSubnetGroups(&rds.DescribeDBSubnetGroupsInput{DBSubnetGroupName: aws.String(os.Getenv("AWS_SUBNET_GROUP_NAME"))})
+	describeDBSubnetGroupOutput, err := comp.rdsAPI.DescribeDBSubnetGroupsWithContext(ctx.Context, &rds.DescribeDBSubnetGroupsInput{DBSubnetGroupName: aws.String(ctx.Config().Get("AWS_SUBNET_GROUP_NAME"))})
 	if err != nil {
 		return components.Result{}, errors.Wrapf(err, "elasticsearch: unable to describe subnet group")
 	}
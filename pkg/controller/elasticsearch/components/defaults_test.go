@@ -22,6 +22,7 @@ import (
 	"os"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/pkg/errors"
@@ -76,7 +77,7 @@ var _ = Describe("ElasticSearch Defaults Component", func() {
 
 })
 
-func (m *mockRDSClient) DescribeDBSubnetGroups(input *rds.DescribeDBSubnetGroupsInput) (*rds.DescribeDBSubnetGroupsOutput, error) {
+func (m *mockRDSClient) DescribeDBSubnetGroupsWithContext(ctx aws.Context, input *rds.DescribeDBSubnetGroupsInput, opts ...request.Option) (*rds.DescribeDBSubnetGroupsOutput, error) {
 	if aws.StringValue(input.DBSubnetGroupName) != "test-subnet" {
 		return nil, errors.New("awsmock_describedbsubnetgroup: db subnet group does not match spec")
 	}
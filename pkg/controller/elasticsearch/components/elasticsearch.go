@@ -18,7 +18,6 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -34,22 +33,24 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	awsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/aws/v1beta1"
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	corev1 "k8s.io/api/core/v1"
 )
 
 const elasticsearchFinalizer = "elasticsearch.finalizer"
 
 type elasticSearchComponent struct {
-	esAPI  esiface.ElasticsearchServiceAPI
-	iamAPI iamiface.IAMAPI
+	esAPI     esiface.ElasticsearchServiceAPI
+	iamAPI    iamiface.IAMAPI
+	finalizer *components.Finalizer
 }
 
 func NewElasticSearch() *elasticSearchComponent {
 	sess := session.Must(session.NewSession())
 	esService := es.New(sess)
 	iamService := iam.New(sess)
-	return &elasticSearchComponent{esAPI: esService, iamAPI: iamService}
+	comp := &elasticSearchComponent{esAPI: esService, iamAPI: iamService}
+	comp.finalizer = components.NewFinalizer(elasticsearchFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *elasticSearchComponent) InjectESAPI(esapi esiface.ElasticsearchServiceAPI, iamapi iamiface.IAMAPI) {
@@ -70,34 +71,9 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 	var esDomainInstance *es.ElasticsearchDomainStatus
 	esDomainName := strings.ToLower(instance.Name)
 
-	// if object is not being deleted
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		// Is our finalizer attached to the object?
-		if !helpers.ContainsFinalizer(elasticsearchFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(elasticsearchFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "elasticsearch: failed to update instance while adding finalizer")
-			}
-			return components.Result{Requeue: true}, nil
-		}
-	} else {
-		if helpers.ContainsFinalizer(elasticsearchFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				result, err := comp.deleteDependencies(ctx)
-				if err != nil {
-					return result, err
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(elasticsearchFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "elasticsearch: failed to update instance while removing finalizer")
-			}
-		}
-		// If object is being deleted and has no finalizer just exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
 	// Wait for security group component to complete
@@ -105,7 +81,7 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 		return components.Result{Requeue: true}, nil
 	}
 	//Create Service Role for ElasticSearch
-	_, err := comp.iamAPI.CreateServiceLinkedRole(&iam.CreateServiceLinkedRoleInput{
+	_, err = comp.iamAPI.CreateServiceLinkedRoleWithContext(ctx.Context, &iam.CreateServiceLinkedRoleInput{
 		AWSServiceName: aws.String("es.amazonaws.com"),
 		Description:    aws.String("created through ridecell-operator"),
 	})
@@ -117,7 +93,7 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 
 	var elasticsearchNotExist bool
 	// try to get ES instance
-	describeElasticsearchDomainOutput, err := comp.esAPI.DescribeElasticsearchDomain(&es.DescribeElasticsearchDomainInput{DomainName: aws.String(esDomainName)})
+	describeElasticsearchDomainOutput, err := comp.esAPI.DescribeElasticsearchDomainWithContext(ctx.Context, &es.DescribeElasticsearchDomainInput{DomainName: aws.String(esDomainName)})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok && aerr.Code() != es.ErrCodeResourceNotFoundException {
 			return components.Result{}, errors.Wrapf(err, "elasticsearch: unable to describe elasticsearch instance")
@@ -153,7 +129,7 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 		}
 
 		// Create ES domain with given configs
-		createElasticsearchDomainOutput, err := comp.esAPI.CreateElasticsearchDomain(&es.CreateElasticsearchDomainInput{
+		createElasticsearchDomainOutput, err := comp.esAPI.CreateElasticsearchDomainWithContext(ctx.Context, &es.CreateElasticsearchDomainInput{
 			DomainName: aws.String(esDomainName),
 			DomainEndpointOptions: &es.DomainEndpointOptions{
 				EnforceHTTPS:      aws.Bool(true),
@@ -194,7 +170,7 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 	}
 
 	// Set Ridecell-Operator tag if not present
-	listTagsOuput, err := comp.esAPI.ListTags(&es.ListTagsInput{
+	listTagsOuput, err := comp.esAPI.ListTagsWithContext(ctx.Context, &es.ListTagsInput{
 		ARN: esDomainInstance.ARN,
 	})
 	if err != nil {
@@ -208,7 +184,7 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 		}
 	}
 	if !tagFound {
-		_, err := comp.esAPI.AddTags(&es.AddTagsInput{
+		_, err := comp.esAPI.AddTagsWithContext(ctx.Context, &es.AddTagsInput{
 			ARN: esDomainInstance.ARN,
 			TagList: []*es.Tag{
 				&es.Tag{Key: aws.String("Ridecell-Operator"), Value: aws.String("true")},
@@ -271,7 +247,7 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 
 	if needsUpdate {
 		// update ES Domain
-		_, err := comp.esAPI.UpdateElasticsearchDomainConfig(updateElasticsearchDomainConfigInput)
+		_, err := comp.esAPI.UpdateElasticsearchDomainConfigWithContext(ctx.Context, updateElasticsearchDomainConfigInput)
 		if err != nil {
 			return components.Result{}, errors.Wrapf(err, "elasticsearch: unable to update elasticsearch instance")
 		}
@@ -296,7 +272,7 @@ func (comp *elasticSearchComponent) Reconcile(ctx *components.ComponentContext)
 func (comp *elasticSearchComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*awsv1beta1.ElasticSearch)
 
-	_, err := comp.esAPI.DeleteElasticsearchDomain(&es.DeleteElasticsearchDomainInput{
+	_, err := comp.esAPI.DeleteElasticsearchDomainWithContext(ctx.Context, &es.DeleteElasticsearchDomainInput{
 		DomainName: aws.String(strings.ToLower(instance.Name)),
 	})
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	es "github.com/aws/aws-sdk-go/service/elasticsearchservice"
 	esiface "github.com/aws/aws-sdk-go/service/elasticsearchservice/elasticsearchserviceiface"
 	"github.com/aws/aws-sdk-go/service/iam"
@@ -129,11 +130,11 @@ var _ = Describe("elasticsearch aws Component", func() {
 })
 
 // Mock aws functions below
-func (m *mockIAMClient) CreateServiceLinkedRole(input *iam.CreateServiceLinkedRoleInput) (*iam.CreateServiceLinkedRoleOutput, error) {
+func (m *mockIAMClient) CreateServiceLinkedRoleWithContext(ctx aws.Context, input *iam.CreateServiceLinkedRoleInput, opts ...request.Option) (*iam.CreateServiceLinkedRoleOutput, error) {
 	return &iam.CreateServiceLinkedRoleOutput{}, nil
 }
 
-func (m *mockESClient) DescribeElasticsearchDomain(input *es.DescribeElasticsearchDomainInput) (*es.DescribeElasticsearchDomainOutput, error) {
+func (m *mockESClient) DescribeElasticsearchDomainWithContext(ctx aws.Context, input *es.DescribeElasticsearchDomainInput, opts ...request.Option) (*es.DescribeElasticsearchDomainOutput, error) {
 	if aws.StringValue(input.DomainName) != strings.ToLower(instance.Name) {
 		return nil, errors.New("awsmock_describeESdomain: given domain name does not match spec")
 	}
@@ -160,7 +161,7 @@ func (m *mockESClient) DescribeElasticsearchDomain(input *es.DescribeElasticsear
 	}, nil
 }
 
-func (m *mockESClient) CreateElasticsearchDomain(input *es.CreateElasticsearchDomainInput) (*es.CreateElasticsearchDomainOutput, error) {
+func (m *mockESClient) CreateElasticsearchDomainWithContext(ctx aws.Context, input *es.CreateElasticsearchDomainInput, opts ...request.Option) (*es.CreateElasticsearchDomainOutput, error) {
 	m.mockDomainExists = true
 	m.mockDomainHasTags = false
 	return &es.CreateElasticsearchDomainOutput{
@@ -183,17 +184,17 @@ func (m *mockESClient) CreateElasticsearchDomain(input *es.CreateElasticsearchDo
 	}, nil
 }
 
-func (m *mockESClient) UpdateElasticsearchDomainConfig(input *es.UpdateElasticsearchDomainConfigInput) (*es.UpdateElasticsearchDomainConfigOutput, error) {
+func (m *mockESClient) UpdateElasticsearchDomainConfigWithContext(ctx aws.Context, input *es.UpdateElasticsearchDomainConfigInput, opts ...request.Option) (*es.UpdateElasticsearchDomainConfigOutput, error) {
 	m.mockDomainUpdated = true
 	return &es.UpdateElasticsearchDomainConfigOutput{}, nil
 }
 
-func (m *mockESClient) DeleteElasticsearchDomain(input *es.DeleteElasticsearchDomainInput) (*es.DeleteElasticsearchDomainOutput, error) {
+func (m *mockESClient) DeleteElasticsearchDomainWithContext(ctx aws.Context, input *es.DeleteElasticsearchDomainInput, opts ...request.Option) (*es.DeleteElasticsearchDomainOutput, error) {
 	m.deleteDomain = true
 	return &es.DeleteElasticsearchDomainOutput{}, nil
 }
 
-func (m *mockESClient) ListTags(input *es.ListTagsInput) (*es.ListTagsOutput, error) {
+func (m *mockESClient) ListTagsWithContext(ctx aws.Context, input *es.ListTagsInput, opts ...request.Option) (*es.ListTagsOutput, error) {
 	if aws.StringValue(input.ARN) != "arn:aws:es:us-west-2:1234567890:domain/test-domain" {
 		return nil, awserr.New(es.ErrCodeResourceNotFoundException, "awsmock_listtags: ES domain does not exist", errors.New(""))
 	}
@@ -202,7 +203,7 @@ func (m *mockESClient) ListTags(input *es.ListTagsInput) (*es.ListTagsOutput, er
 	}, nil
 }
 
-func (m *mockESClient) AddTags(input *es.AddTagsInput) (*es.AddTagsOutput, error) {
+func (m *mockESClient) AddTagsWithContext(ctx aws.Context, input *es.AddTagsInput, opts ...request.Option) (*es.AddTagsOutput, error) {
 	if aws.StringValue(input.TagList[0].Key) == "Ridecell-Operator" {
 		m.mockDomainHasTags = true
 	}
@@ -18,7 +18,6 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
@@ -36,13 +35,16 @@ import (
 const elasticSearchSecurityGroupFinalizer = "elasticsearch.securitygroup.finalizer"
 
 type esSecurityGroupComponent struct {
-	ec2API ec2iface.EC2API
+	ec2API    ec2iface.EC2API
+	finalizer *components.Finalizer
 }
 
 func NewESSecurityGroup() *esSecurityGroupComponent {
 	sess := session.Must(session.NewSession())
 	ec2Service := ec2.New(sess)
-	return &esSecurityGroupComponent{ec2API: ec2Service}
+	comp := &esSecurityGroupComponent{ec2API: ec2Service}
+	comp.finalizer = components.NewFinalizer(elasticSearchSecurityGroupFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *esSecurityGroupComponent) InjectAWSAPIs(ec2api ec2iface.EC2API) {
@@ -61,37 +63,12 @@ func (comp *esSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 	instance := ctx.Top.(*awsv1beta1.ElasticSearch)
 	securityGroupName := fmt.Sprintf("ridecell-operator-es-%s", instance.Name)
 
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(elasticSearchSecurityGroupFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(elasticSearchSecurityGroupFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "elasticsearch: failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(elasticSearchSecurityGroupFinalizer, instance) {
-			// If our database still exists we can't delete the security group
-			if helpers.ContainsFinalizer(elasticsearchFinalizer, instance) {
-				return components.Result{RequeueAfter: time.Second * 5}, nil
-			}
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				result, err := comp.deleteDependencies(ctx)
-				if err != nil {
-					return result, err
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(elasticSearchSecurityGroupFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "elasticsearch: failed to update instance while removing finalizer")
-			}
-		}
-		// If object is being deleted and has no finalizer exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
-	describeSecurityGroupsOutput, err := comp.ec2API.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+
+	describeSecurityGroupsOutput, err := comp.ec2API.DescribeSecurityGroupsWithContext(ctx.Context, &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
 			&ec2.Filter{
 				Name:   aws.String("group-name"),
@@ -105,7 +82,7 @@ func (comp *esSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 
 	if len(describeSecurityGroupsOutput.SecurityGroups) < 1 {
 		// Create Security group
-		_, err = comp.ec2API.CreateSecurityGroup(&ec2.CreateSecurityGroupInput{
+		_, err = comp.ec2API.CreateSecurityGroupWithContext(ctx.Context, &ec2.CreateSecurityGroupInput{
 			GroupName:   aws.String(securityGroupName),
 			Description: aws.String(fmt.Sprintf("%s: Created by ridecell-operator", securityGroupName)),
 			VpcId:       aws.String(instance.Spec.VPCID),
@@ -119,10 +96,10 @@ func (comp *esSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 	// Set the securityGroup field of instance spec
 	instance.Spec.SecurityGroupId = aws.StringValue(describeSecurityGroupsOutput.SecurityGroups[0].GroupId)
 	securityGroup := describeSecurityGroupsOutput.SecurityGroups[0]
-	sgOutput, err := comp.ec2API.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+	sgOutput, err := comp.ec2API.DescribeSecurityGroupsWithContext(ctx.Context, &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{&ec2.Filter{
 			Name:   aws.String("tag:Name"),
-			Values: []*string{aws.String(fmt.Sprintf("nodes.%s", os.Getenv("AWS_SUBNET_GROUP_NAME")))},
+			Values: []*string{aws.String(fmt.Sprintf("nodes.%s", ctx.Config().Get("AWS_SUBNET_GROUP_NAME")))},
 		},
 		},
 	})
@@ -144,7 +121,7 @@ func (comp *esSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 	}
 
 	if !hasIngressRule {
-		_, err := comp.ec2API.AuthorizeSecurityGroupIngress(&ec2.AuthorizeSecurityGroupIngressInput{
+		_, err := comp.ec2API.AuthorizeSecurityGroupIngressWithContext(ctx.Context, &ec2.AuthorizeSecurityGroupIngressInput{
 			GroupId: securityGroup.GroupId,
 			IpPermissions: []*ec2.IpPermission{
 				{
@@ -172,7 +149,7 @@ func (comp *esSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 	}
 
 	if !foundOperatorTag {
-		_, err := comp.ec2API.CreateTags(&ec2.CreateTagsInput{
+		_, err := comp.ec2API.CreateTagsWithContext(ctx.Context, &ec2.CreateTagsInput{
 			Resources: []*string{securityGroup.GroupId},
 			Tags: []*ec2.Tag{
 				&ec2.Tag{
@@ -196,7 +173,11 @@ func (comp *esSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 
 func (comp *esSecurityGroupComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*awsv1beta1.ElasticSearch)
-	describeSecurityGroupsOutput, _ := comp.ec2API.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+	// If our domain still exists we can't delete the security group
+	if helpers.ContainsFinalizer(elasticsearchFinalizer, instance) {
+		return components.Result{RequeueAfter: time.Second * 5}, nil
+	}
+	describeSecurityGroupsOutput, _ := comp.ec2API.DescribeSecurityGroupsWithContext(ctx.Context, &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
 			&ec2.Filter{
 				Name:   aws.String("group-name"),
@@ -211,7 +192,7 @@ func (comp *esSecurityGroupComponent) deleteDependencies(ctx *components.Compone
 		return components.Result{}, nil
 	}
 
-	_, err := comp.ec2API.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{
+	_, err := comp.ec2API.DeleteSecurityGroupWithContext(ctx.Context, &ec2.DeleteSecurityGroupInput{
 		GroupId: describeSecurityGroupsOutput.SecurityGroups[0].GroupId,
 	})
 	if err != nil {
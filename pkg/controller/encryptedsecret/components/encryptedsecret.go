@@ -83,7 +83,7 @@ func (comp *EncryptedSecretComponent) Reconcile(ctx *components.ComponentContext
 		if err != nil {
 			return components.Result{}, errors.Wrapf(err, "encryptedsecret: failed to base64 decode secret")
 		}
-		decryptedValue, err := comp.kmsAPI.Decrypt(&kms.DecryptInput{
+		decryptedValue, err := comp.kmsAPI.DecryptWithContext(ctx.Context, &kms.DecryptInput{
 			CiphertextBlob: decodedValue,
 			EncryptionContext: map[string]*string{
 				"RidecellOperator": aws.String("true"),
@@ -27,6 +27,7 @@ import (
 
 	gcpv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/gcp/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 	"github.com/Ridecell/ridecell-operator/pkg/errors"
 )
 
@@ -56,7 +57,7 @@ func (r *realCloudBilling) GetProjectBillingInfo(projectID string) (*cloudbillin
 
 func (r *realCloudBilling) UpdateProjectbillingInfo(projectID string) (*cloudbilling.ProjectBillingInfo, error) {
 	newBillingInfo := &cloudbilling.ProjectBillingInfo{
-		BillingAccountName: os.Getenv("GOOGLE_BILLING_ACCOUNT_NAME"),
+		BillingAccountName: config.Get("GOOGLE_BILLING_ACCOUNT_NAME"),
 	}
 	return r.svc.Projects.UpdateBillingInfo(fmt.Sprintf("projects/%s", projectID), newBillingInfo).Do()
 }
@@ -98,7 +99,7 @@ func (comp *billingComponent) Reconcile(ctx *components.ComponentContext) (compo
 		return components.Result{}, errors.New("gcpproject: firebase credentials not available")
 	}
 
-	if os.Getenv("GOOGLE_BILLING_ACCOUNT_NAME") == "" {
+	if ctx.Config().Get("GOOGLE_BILLING_ACCOUNT_NAME") == "" {
 		return components.Result{}, errors.New("gcpproject: google billing account name not available")
 	}
 
@@ -108,7 +109,7 @@ func (comp *billingComponent) Reconcile(ctx *components.ComponentContext) (compo
 			return components.Result{}, errors.Wrap(err, "gcpproject: failed to get project billing info")
 		}
 
-		if billingInfo.BillingAccountName != os.Getenv("GOOGLE_BILLING_ACCOUNT_NAME") {
+		if billingInfo.BillingAccountName != ctx.Config().Get("GOOGLE_BILLING_ACCOUNT_NAME") {
 			_, err := comp.billing.UpdateProjectbillingInfo(instance.Spec.ProjectID)
 			if err != nil {
 				return components.Result{}, errors.Wrap(err, "gcpproject: failed to update billing info")
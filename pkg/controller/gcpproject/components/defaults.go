@@ -17,8 +17,6 @@ limitations under the License.
 package components
 
 import (
-	"os"
-
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/Ridecell/ridecell-operator/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -61,7 +59,7 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 
 	if instance.Spec.RealtimeDatabaseRules == "" {
-		defaultRules := os.Getenv("FIREBASE_DATABASE_DEFAULT_RULES")
+		defaultRules := ctx.Config().Get("FIREBASE_DATABASE_DEFAULT_RULES")
 		if defaultRules == "" {
 			return components.Result{}, errors.New("gcpproject: FIREBASE_DATABASE_DEFAULT_RULES is not set")
 		}
@@ -19,7 +19,6 @@ package components
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
@@ -54,7 +53,7 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 
 	if instance.Spec.PermissionsBoundaryArn == "" {
-		defaultPermissionsBoundaryArn := os.Getenv("DEFAULT_PERMISSIONS_BOUNDARY_ARN")
+		defaultPermissionsBoundaryArn := ctx.Config().Get("DEFAULT_PERMISSIONS_BOUNDARY_ARN")
 		if defaultPermissionsBoundaryArn == "" {
 			return components.Result{}, errors.New("iam_role: DEFAULT_PERMISSIONS_BOUNDARY_ARN is not set")
 		}
@@ -62,7 +61,7 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 
 	if instance.Spec.AssumeRolePolicyDocument == "" {
-		trustedArnList := os.Getenv("TRUSTED_ROLE_ARNS")
+		trustedArnList := ctx.Config().Get("TRUSTED_ROLE_ARNS")
 		if trustedArnList == "" {
 			return components.Result{}, errors.New("iam_role: TRUSTED_ROLE_ARNS is not set")
 		}
@@ -20,29 +20,28 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/url"
-	"os"
 	"reflect"
 	"text/template"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
-	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	awsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/aws/v1beta1"
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	corev1 "k8s.io/api/core/v1"
 )
 
 const iamRoleFinalizer = "iamrole.finalizer"
 
 type iamRoleComponent struct {
-	iamAPI iamiface.IAMAPI
+	iamAPI    iamiface.IAMAPI
+	finalizer *components.Finalizer
 }
 
 type templatingData struct {
@@ -52,7 +51,9 @@ type templatingData struct {
 func NewIAMRole() *iamRoleComponent {
 	sess := session.Must(session.NewSession())
 	iamService := iam.New(sess)
-	return &iamRoleComponent{iamAPI: iamService}
+	comp := &iamRoleComponent{iamAPI: iamService}
+	comp.finalizer = components.NewFinalizer(iamRoleFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *iamRoleComponent) InjectIAMAPI(iamapi iamiface.IAMAPI) {
@@ -90,36 +91,11 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 		inlinePolicies[policyName] = parsedPolicy
 	}
 
-	// if object is not being deleted
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		// Is our finalizer attached to the object?
-		if !helpers.ContainsFinalizer(iamRoleFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(iamRoleFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "iamrole: failed to update instance while adding finalizer")
-			}
-			return components.Result{Requeue: true}, nil
-		}
-	} else {
-		if helpers.ContainsFinalizer(iamRoleFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				result, err := comp.deleteDependencies(roleName)
-				if err != nil {
-					return result, err
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(iamRoleFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "iamrole: failed to update instance while removing finalizer")
-			}
-			return components.Result{}, nil
-		}
-		// If object is being deleted and has no finalizer just exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
+
 	// check assumeRolePolicyDocument for valid JSON
 	// inlinepolicies is checked later in UnMarshal
 	if !json.Valid([]byte(assumePolicyDocument)) {
@@ -128,13 +104,13 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 
 	// Try to get our role, if it can't be found create it
 	var role *iam.Role
-	getRoleOutput, err := comp.iamAPI.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+	getRoleOutput, err := comp.iamAPI.GetRoleWithContext(ctx.Context, &iam.GetRoleInput{RoleName: aws.String(roleName)})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
 			return components.Result{}, errors.Wrapf(aerr, "iam_role: failed to get role")
 		}
 		// If role does not exist create it
-		createRoleOutput, err := comp.iamAPI.CreateRole(&iam.CreateRoleInput{
+		createRoleOutput, err := comp.iamAPI.CreateRoleWithContext(ctx.Context, &iam.CreateRoleInput{
 			RoleName:                 aws.String(roleName),
 			PermissionsBoundary:      aws.String(instance.Spec.PermissionsBoundaryArn),
 			AssumeRolePolicyDocument: aws.String(assumePolicyDocument),
@@ -159,7 +135,7 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}
 
 	// Get role tags
-	listRoleTagsOutput, err := comp.iamAPI.ListRoleTags(&iam.ListRoleTagsInput{RoleName: role.RoleName})
+	listRoleTagsOutput, err := comp.iamAPI.ListRoleTagsWithContext(ctx.Context, &iam.ListRoleTagsInput{RoleName: role.RoleName})
 	if err != nil {
 		return components.Result{}, errors.Wrapf(err, "iam_role: failed to list role tags")
 	}
@@ -179,7 +155,7 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}
 
 	if !foundKiamTag {
-		_, err = comp.iamAPI.TagRole(&iam.TagRoleInput{
+		_, err = comp.iamAPI.TagRoleWithContext(ctx.Context, &iam.TagRoleInput{
 			RoleName: role.RoleName,
 			Tags: []*iam.Tag{
 				&iam.Tag{
@@ -198,14 +174,14 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}
 
 	// Get inline role policy names
-	listRolePoliciesOutput, err := comp.iamAPI.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: role.RoleName})
+	listRolePoliciesOutput, err := comp.iamAPI.ListRolePoliciesWithContext(ctx.Context, &iam.ListRolePoliciesInput{RoleName: role.RoleName})
 	if err != nil {
 		return components.Result{}, errors.Wrapf(err, "iam_role: failed to list inline role policies")
 	}
 
 	rolePolicies := map[string]string{}
 	for _, rolePolicyName := range listRolePoliciesOutput.PolicyNames {
-		getRolePolicy, err := comp.iamAPI.GetRolePolicy(&iam.GetRolePolicyInput{
+		getRolePolicy, err := comp.iamAPI.GetRolePolicyWithContext(ctx.Context, &iam.GetRolePolicyInput{
 			PolicyName: rolePolicyName,
 			RoleName:   role.RoleName,
 		})
@@ -224,7 +200,7 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	for rolePolicyName := range rolePolicies {
 		_, ok := inlinePolicies[rolePolicyName]
 		if !ok {
-			_, err = comp.iamAPI.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+			_, err = comp.iamAPI.DeleteRolePolicyWithContext(ctx.Context, &iam.DeleteRolePolicyInput{
 				PolicyName: aws.String(rolePolicyName),
 				RoleName:   role.RoleName,
 			})
@@ -257,13 +233,13 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 			}
 		}
 
-		_, err = comp.iamAPI.PutRolePolicy(&iam.PutRolePolicyInput{
+		_, err = comp.iamAPI.PutRolePolicyWithContext(ctx.Context, &iam.PutRolePolicyInput{
 			PolicyDocument: aws.String(policyJSON),
 			PolicyName:     aws.String(policyName),
 			RoleName:       role.RoleName,
 		})
 		if err != nil {
-			glog.Errorf("[%s/%s] iamrole: error putting role policy: %#v %#v %#v", instance.Namespace, instance.Name, *role.RoleName, policyName, policyJSON)
+			ctx.Logger().Error(err, "Error putting role policy", "roleName", aws.StringValue(role.RoleName), "policyName", policyName, "policy", policyJSON)
 			return components.Result{}, errors.Wrapf(err, "iam_role: failed to put role policy %s", policyName)
 		}
 	}
@@ -288,7 +264,7 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}
 
 	if !reflect.DeepEqual(exsitingARPDObj, specARPDObj) {
-		_, err = comp.iamAPI.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+		_, err = comp.iamAPI.UpdateAssumeRolePolicyWithContext(ctx.Context, &iam.UpdateAssumeRolePolicyInput{
 			RoleName:       role.RoleName,
 			PolicyDocument: aws.String(assumePolicyDocument),
 		})
@@ -306,9 +282,15 @@ func (comp *iamRoleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}}, nil
 }
 
-func (comp *iamRoleComponent) deleteDependencies(roleName string) (components.Result, error) {
+func (comp *iamRoleComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*awsv1beta1.IAMRole)
+	roleName, err := comp.parseField(instance.Spec.RoleName)
+	if err != nil {
+		return components.Result{}, err
+	}
+
 	// check if the role exists before listing policies to prevent AccessDenied IAM edge case
-	_, err := comp.iamAPI.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+	_, err = comp.iamAPI.GetRoleWithContext(ctx.Context, &iam.GetRoleInput{RoleName: aws.String(roleName)})
 	if err != nil {
 		aerr, ok := err.(awserr.Error)
 		if ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
@@ -318,7 +300,7 @@ func (comp *iamRoleComponent) deleteDependencies(roleName string) (components.Re
 		return components.Result{}, errors.Wrapf(aerr, "iam_role: failed to get role")
 	}
 	// Have to delete attached policies before role deletion
-	listRolePoliciesOutput, err := comp.iamAPI.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	listRolePoliciesOutput, err := comp.iamAPI.ListRolePoliciesWithContext(ctx.Context, &iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
 	// If the role doesn't exist skip error
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
@@ -326,7 +308,7 @@ func (comp *iamRoleComponent) deleteDependencies(roleName string) (components.Re
 		}
 	}
 	for _, rolePolicy := range listRolePoliciesOutput.PolicyNames {
-		_, err = comp.iamAPI.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+		_, err = comp.iamAPI.DeleteRolePolicyWithContext(ctx.Context, &iam.DeleteRolePolicyInput{
 			RoleName:   aws.String(roleName),
 			PolicyName: rolePolicy,
 		})
@@ -334,7 +316,7 @@ func (comp *iamRoleComponent) deleteDependencies(roleName string) (components.Re
 			return components.Result{}, errors.Wrapf(err, "iam_role: failed to delete role policy for finalizer")
 		}
 	}
-	_, err = comp.iamAPI.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(roleName)})
+	_, err = comp.iamAPI.DeleteRoleWithContext(ctx.Context, &iam.DeleteRoleInput{RoleName: aws.String(roleName)})
 	// If the role doesn't exist skip error
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
@@ -346,7 +328,7 @@ func (comp *iamRoleComponent) deleteDependencies(roleName string) (components.Re
 
 func (comp *iamRoleComponent) parseField(field string) (string, error) {
 	templateData := templatingData{
-		Region: os.Getenv("AWS_REGION"),
+		Region: config.Get("AWS_REGION"),
 	}
 
 	buff := &bytes.Buffer{}
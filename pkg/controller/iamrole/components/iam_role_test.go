@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/pkg/errors"
@@ -131,7 +132,6 @@ var _ = Describe("iam_role aws Component", func() {
 		})
 
 		It("adds finalizer when there isn't one", func() {
-			mockIAM.finalizerTest = true
 			instance.ObjectMeta.Finalizers = []string{}
 
 			Expect(comp).To(ReconcileContext(ctx))
@@ -180,7 +180,7 @@ var _ = Describe("iam_role aws Component", func() {
 
 // Mock aws functions below
 
-func (m *mockIAMClient) GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+func (m *mockIAMClient) GetRoleWithContext(ctx aws.Context, input *iam.GetRoleInput, opts ...request.Option) (*iam.GetRoleOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.GetRoleOutput{}, errors.New("awsmock_getrole: given rolename does not match expected")
 	}
@@ -190,7 +190,7 @@ func (m *mockIAMClient) GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, er
 	return &iam.GetRoleOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_getrole: role does not exist", errors.New(""))
 }
 
-func (m *mockIAMClient) CreateRole(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+func (m *mockIAMClient) CreateRoleWithContext(ctx aws.Context, input *iam.CreateRoleInput, opts ...request.Option) (*iam.CreateRoleOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.CreateRoleOutput{}, errors.New("awsmock_createrole: given rolename does not match expected")
 	}
@@ -202,7 +202,7 @@ func (m *mockIAMClient) CreateRole(input *iam.CreateRoleInput) (*iam.CreateRoleO
 	return &iam.CreateRoleOutput{Role: &iam.Role{RoleName: input.RoleName, AssumeRolePolicyDocument: input.AssumeRolePolicyDocument}}, nil
 }
 
-func (m *mockIAMClient) ListRolePolicies(input *iam.ListRolePoliciesInput) (*iam.ListRolePoliciesOutput, error) {
+func (m *mockIAMClient) ListRolePoliciesWithContext(ctx aws.Context, input *iam.ListRolePoliciesInput, opts ...request.Option) (*iam.ListRolePoliciesOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName || (!m.mockRoleExists && m.finalizerTest) {
 		return &iam.ListRolePoliciesOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_listrolepolicies: given rolename does not match expected", errors.New(""))
 	}
@@ -224,7 +224,7 @@ func (m *mockIAMClient) ListRolePolicies(input *iam.ListRolePoliciesInput) (*iam
 	return &iam.ListRolePoliciesOutput{}, nil
 }
 
-func (m *mockIAMClient) GetRolePolicy(input *iam.GetRolePolicyInput) (*iam.GetRolePolicyOutput, error) {
+func (m *mockIAMClient) GetRolePolicyWithContext(ctx aws.Context, input *iam.GetRolePolicyInput, opts ...request.Option) (*iam.GetRolePolicyOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.GetRolePolicyOutput{}, errors.New("awsmock_getrolepolicy: given rolename does not match expected")
 	}
@@ -242,14 +242,14 @@ func (m *mockIAMClient) GetRolePolicy(input *iam.GetRolePolicyInput) (*iam.GetRo
 	return &iam.GetRolePolicyOutput{}, nil
 }
 
-func (m *mockIAMClient) PutRolePolicy(input *iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error) {
+func (m *mockIAMClient) PutRolePolicyWithContext(ctx aws.Context, input *iam.PutRolePolicyInput, opts ...request.Option) (*iam.PutRolePolicyOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.PutRolePolicyOutput{}, errors.New("awsmock_putrolepolicy: rolename did not match expected")
 	}
 	return &iam.PutRolePolicyOutput{}, nil
 }
 
-func (m *mockIAMClient) DeleteRolePolicy(input *iam.DeleteRolePolicyInput) (*iam.DeleteRolePolicyOutput, error) {
+func (m *mockIAMClient) DeleteRolePolicyWithContext(ctx aws.Context, input *iam.DeleteRolePolicyInput, opts ...request.Option) (*iam.DeleteRolePolicyOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.DeleteRolePolicyOutput{}, errors.New("awsmock_deleterolepolicy: rolename did not match expected")
 	}
@@ -260,7 +260,7 @@ func (m *mockIAMClient) DeleteRolePolicy(input *iam.DeleteRolePolicyInput) (*iam
 	return &iam.DeleteRolePolicyOutput{}, errors.New("awsmock_deleterolepolicy: policy shouldn't be getting deleted")
 }
 
-func (m *mockIAMClient) ListRoleTags(input *iam.ListRoleTagsInput) (*iam.ListRoleTagsOutput, error) {
+func (m *mockIAMClient) ListRoleTagsWithContext(ctx aws.Context, input *iam.ListRoleTagsInput, opts ...request.Option) (*iam.ListRoleTagsOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.ListRoleTagsOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_listroletags: rolename did not match expected", errors.New(""))
 	}
@@ -283,7 +283,7 @@ func (m *mockIAMClient) ListRoleTags(input *iam.ListRoleTagsInput) (*iam.ListRol
 	return &iam.ListRoleTagsOutput{}, nil
 }
 
-func (m *mockIAMClient) TagRole(input *iam.TagRoleInput) (*iam.TagRoleOutput, error) {
+func (m *mockIAMClient) TagRoleWithContext(ctx aws.Context, input *iam.TagRoleInput, opts ...request.Option) (*iam.TagRoleOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.TagRoleOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_tagrole: rolename did not match expected", errors.New(""))
 	}
@@ -291,7 +291,7 @@ func (m *mockIAMClient) TagRole(input *iam.TagRoleInput) (*iam.TagRoleOutput, er
 	return &iam.TagRoleOutput{}, nil
 }
 
-func (m *mockIAMClient) DeleteRole(input *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error) {
+func (m *mockIAMClient) DeleteRoleWithContext(ctx aws.Context, input *iam.DeleteRoleInput, opts ...request.Option) (*iam.DeleteRoleOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName || (!m.mockRoleExists && m.finalizerTest) {
 		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_deleterole: rolename did not match expected", errors.New(""))
 	}
@@ -299,7 +299,7 @@ func (m *mockIAMClient) DeleteRole(input *iam.DeleteRoleInput) (*iam.DeleteRoleO
 	return &iam.DeleteRoleOutput{}, nil
 }
 
-func (m *mockIAMClient) UpdateAssumeRolePolicy(input *iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error) {
+func (m *mockIAMClient) UpdateAssumeRolePolicyWithContext(ctx aws.Context, input *iam.UpdateAssumeRolePolicyInput, opts ...request.Option) (*iam.UpdateAssumeRolePolicyOutput, error) {
 	if aws.StringValue(input.RoleName) != m.expectedRoleName {
 		return &iam.UpdateAssumeRolePolicyOutput{}, errors.New("awsmock_updateassumerolepolicy: rolename did not match expected")
 	}
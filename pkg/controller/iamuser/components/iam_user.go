@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"os"
 	"reflect"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
@@ -29,14 +28,12 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
-	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	awsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/aws/v1beta1"
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,13 +42,16 @@ import (
 const iamUserFinalizer = "iamuser.finalizer"
 
 type iamUserComponent struct {
-	iamAPI iamiface.IAMAPI
+	iamAPI    iamiface.IAMAPI
+	finalizer *components.Finalizer
 }
 
 func NewIAMUser() *iamUserComponent {
 	sess := session.Must(session.NewSession())
 	iamService := iam.New(sess)
-	return &iamUserComponent{iamAPI: iamService}
+	comp := &iamUserComponent{iamAPI: iamService}
+	comp.finalizer = components.NewFinalizer(iamUserFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *iamUserComponent) InjectIAMAPI(iamapi iamiface.IAMAPI) {
@@ -69,46 +69,20 @@ func (_ *iamUserComponent) IsReconcilable(_ *components.ComponentContext) bool {
 func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*awsv1beta1.IAMUser)
 
-	// if object is not being deleted
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		// Is our finalizer attached to the object?
-		if !helpers.ContainsFinalizer(iamUserFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(iamUserFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "iamuser: failed to update instance while adding finalizer")
-			}
-			return components.Result{Requeue: true}, nil
-		}
-	} else {
-		if helpers.ContainsFinalizer(iamUserFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				result, err := comp.deleteDependencies(ctx)
-				if err != nil {
-					return result, err
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(iamUserFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "iamuser: failed to update instance while removing finalizer")
-			}
-			return components.Result{}, nil
-		}
-		// If object is being deleted and has no finalizer just exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
 	// Try to get our user, if it can't be found create it
 	var user *iam.User
-	getUserOutput, err := comp.iamAPI.GetUser(&iam.GetUserInput{UserName: aws.String(instance.Spec.UserName)})
+	getUserOutput, err := comp.iamAPI.GetUserWithContext(ctx.Context, &iam.GetUserInput{UserName: aws.String(instance.Spec.UserName)})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
 			return components.Result{}, errors.Wrapf(aerr, "iam_user: failed to get user")
 		}
 		// If user does not exist create it
-		createUserOutput, err := comp.iamAPI.CreateUser(&iam.CreateUserInput{
+		createUserOutput, err := comp.iamAPI.CreateUserWithContext(ctx.Context, &iam.CreateUserInput{
 			UserName:            aws.String(instance.Spec.UserName),
 			PermissionsBoundary: aws.String(instance.Spec.PermissionsBoundaryArn),
 		})
@@ -123,7 +97,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 
 	// Get user tags
 
-	listUserTagsOutput, err := comp.iamAPI.ListUserTags(&iam.ListUserTagsInput{UserName: user.UserName})
+	listUserTagsOutput, err := comp.iamAPI.ListUserTagsWithContext(ctx.Context, &iam.ListUserTagsInput{UserName: user.UserName})
 	if err != nil {
 		return components.Result{}, errors.Wrapf(err, "iam_user: failed to list user tags")
 	}
@@ -135,7 +109,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 		}
 	}
 	if !foundTag {
-		_, err = comp.iamAPI.TagUser(&iam.TagUserInput{
+		_, err = comp.iamAPI.TagUserWithContext(ctx.Context, &iam.TagUserInput{
 			UserName: user.UserName,
 			Tags: []*iam.Tag{
 				&iam.Tag{
@@ -150,7 +124,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}
 
 	// Get inline user policy names
-	listUserPoliciesOutput, err := comp.iamAPI.ListUserPolicies(&iam.ListUserPoliciesInput{UserName: user.UserName})
+	listUserPoliciesOutput, err := comp.iamAPI.ListUserPoliciesWithContext(ctx.Context, &iam.ListUserPoliciesInput{UserName: user.UserName})
 	if err != nil {
 		return components.Result{}, errors.Wrapf(err, "iam_user: failed to list inline user policies")
 	}
@@ -158,7 +132,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 	userPolicies := map[string]string{}
 	for _, userPolicyName := range listUserPoliciesOutput.PolicyNames {
 		// Not actually in use at the moment.
-		getUserPolicy, err := comp.iamAPI.GetUserPolicy(&iam.GetUserPolicyInput{
+		getUserPolicy, err := comp.iamAPI.GetUserPolicyWithContext(ctx.Context, &iam.GetUserPolicyInput{
 			PolicyName: userPolicyName,
 			UserName:   user.UserName,
 		})
@@ -177,7 +151,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 	for userPolicyName := range userPolicies {
 		_, ok := instance.Spec.InlinePolicies[userPolicyName]
 		if !ok {
-			_, err = comp.iamAPI.DeleteUserPolicy(&iam.DeleteUserPolicyInput{
+			_, err = comp.iamAPI.DeleteUserPolicyWithContext(ctx.Context, &iam.DeleteUserPolicyInput{
 				PolicyName: aws.String(userPolicyName),
 				UserName:   user.UserName,
 			})
@@ -210,13 +184,13 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 			}
 		}
 
-		_, err = comp.iamAPI.PutUserPolicy(&iam.PutUserPolicyInput{
+		_, err = comp.iamAPI.PutUserPolicyWithContext(ctx.Context, &iam.PutUserPolicyInput{
 			PolicyDocument: aws.String(policyJSON),
 			PolicyName:     aws.String(policyName),
 			UserName:       user.UserName,
 		})
 		if err != nil {
-			glog.Errorf("[%s/%s] iamuser: error putting user policy: %#v %#v %#v", instance.Namespace, instance.Name, *user.UserName, policyName, policyJSON)
+			ctx.Logger().Error(err, "Error putting user policy", "userName", aws.StringValue(user.UserName), "policyName", policyName, "policy", policyJSON)
 			return components.Result{}, errors.Wrapf(err, "iam_user: failed to put user policy %s", policyName)
 		}
 	}
@@ -233,7 +207,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 	fetchAccessKeyID, ok0 := fetchAccessKey.Data["AWS_ACCESS_KEY_ID"]
 	_, ok1 := fetchAccessKey.Data["AWS_SECRET_ACCESS_KEY"]
 
-	existingAccessKeys, err := comp.iamAPI.ListAccessKeys(&iam.ListAccessKeysInput{UserName: user.UserName})
+	existingAccessKeys, err := comp.iamAPI.ListAccessKeysWithContext(ctx.Context, &iam.ListAccessKeysInput{UserName: user.UserName})
 	if err != nil {
 		return components.Result{}, errors.Wrapf(err, "iam_user: failed to list access keys")
 	}
@@ -248,7 +222,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 			foundAccessKeyID = true
 		} else {
 			// If the access key isn't known to the controller delete it
-			_, err := comp.iamAPI.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			_, err := comp.iamAPI.DeleteAccessKeyWithContext(ctx.Context, &iam.DeleteAccessKeyInput{
 				AccessKeyId: accessKeyMeta.AccessKeyId,
 				UserName:    user.UserName,
 			})
@@ -260,7 +234,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 
 	if !foundAccessKeyID {
 		// Make new access key and put it in a secret
-		createAccessKeyOutput, err := comp.iamAPI.CreateAccessKey(&iam.CreateAccessKeyInput{UserName: user.UserName})
+		createAccessKeyOutput, err := comp.iamAPI.CreateAccessKeyWithContext(ctx.Context, &iam.CreateAccessKeyInput{UserName: user.UserName})
 		if err != nil {
 			return components.Result{}, errors.Wrapf(err, "iam_user: failed to create new access key")
 		}
@@ -284,6 +258,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 		if err != nil {
 			return components.Result{}, errors.Wrapf(err, "iam_user: failed to create or update secret")
 		}
+		ctx.Eventf(corev1.EventTypeNormal, "AccessKeyCreated", "Created IAM access key %s", aws.StringValue(createAccessKeyOutput.AccessKey.AccessKeyId))
 	}
 
 	return components.Result{StatusModifier: func(obj runtime.Object) error {
@@ -297,7 +272,7 @@ func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (compo
 func (comp *iamUserComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*awsv1beta1.IAMUser)
 	// Have to delete access keys before user deletion
-	listAccessKeysOutput, err := comp.iamAPI.ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(instance.Spec.UserName)})
+	listAccessKeysOutput, err := comp.iamAPI.ListAccessKeysWithContext(ctx.Context, &iam.ListAccessKeysInput{UserName: aws.String(instance.Spec.UserName)})
 	// If the user doesn't exist skip error
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
@@ -305,7 +280,7 @@ func (comp *iamUserComponent) deleteDependencies(ctx *components.ComponentContex
 		}
 	}
 	for _, accessKey := range listAccessKeysOutput.AccessKeyMetadata {
-		_, err = comp.iamAPI.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+		_, err = comp.iamAPI.DeleteAccessKeyWithContext(ctx.Context, &iam.DeleteAccessKeyInput{
 			UserName:    aws.String(instance.Spec.UserName),
 			AccessKeyId: accessKey.AccessKeyId,
 		})
@@ -314,7 +289,7 @@ func (comp *iamUserComponent) deleteDependencies(ctx *components.ComponentContex
 		}
 	}
 	// Have to delete attached policies before user deletion
-	listUserPoliciesOutput, err := comp.iamAPI.ListUserPolicies(&iam.ListUserPoliciesInput{UserName: aws.String(instance.Spec.UserName)})
+	listUserPoliciesOutput, err := comp.iamAPI.ListUserPoliciesWithContext(ctx.Context, &iam.ListUserPoliciesInput{UserName: aws.String(instance.Spec.UserName)})
 	// If the user doesn't exist skip error
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
@@ -322,7 +297,7 @@ func (comp *iamUserComponent) deleteDependencies(ctx *components.ComponentContex
 		}
 	}
 	for _, userPolicy := range listUserPoliciesOutput.PolicyNames {
-		_, err = comp.iamAPI.DeleteUserPolicy(&iam.DeleteUserPolicyInput{
+		_, err = comp.iamAPI.DeleteUserPolicyWithContext(ctx.Context, &iam.DeleteUserPolicyInput{
 			UserName:   aws.String(instance.Spec.UserName),
 			PolicyName: userPolicy,
 		})
@@ -330,7 +305,7 @@ func (comp *iamUserComponent) deleteDependencies(ctx *components.ComponentContex
 			return components.Result{}, errors.Wrapf(err, "iamuser: failed to delete user policy for finalizer")
 		}
 	}
-	_, err = comp.iamAPI.DeleteUser(&iam.DeleteUserInput{UserName: aws.String(instance.Spec.UserName)})
+	_, err = comp.iamAPI.DeleteUserWithContext(ctx.Context, &iam.DeleteUserInput{UserName: aws.String(instance.Spec.UserName)})
 	// If the user doesn't exist skip error
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
@@ -27,6 +27,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/pkg/errors"
@@ -158,7 +159,6 @@ var _ = Describe("iam_user aws Component", func() {
 		})
 
 		It("adds finalizer when there isn't one", func() {
-			mockIAM.finalizerTest = true
 			instance.ObjectMeta.Finalizers = []string{}
 
 			Expect(comp).To(ReconcileContext(ctx))
@@ -208,7 +208,7 @@ var _ = Describe("iam_user aws Component", func() {
 
 // Mock aws functions below
 
-func (m *mockIAMClient) GetUser(input *iam.GetUserInput) (*iam.GetUserOutput, error) {
+func (m *mockIAMClient) GetUserWithContext(ctx aws.Context, input *iam.GetUserInput, opts ...request.Option) (*iam.GetUserOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.GetUserOutput{}, errors.New("awsmock_getuser: given username does not match spec")
 	}
@@ -218,14 +218,14 @@ func (m *mockIAMClient) GetUser(input *iam.GetUserInput) (*iam.GetUserOutput, er
 	return &iam.GetUserOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_getuser: user does not exist", errors.New(""))
 }
 
-func (m *mockIAMClient) CreateUser(input *iam.CreateUserInput) (*iam.CreateUserOutput, error) {
+func (m *mockIAMClient) CreateUserWithContext(ctx aws.Context, input *iam.CreateUserInput, opts ...request.Option) (*iam.CreateUserOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.CreateUserOutput{}, errors.New("awsmock_createuser: given username does not match spec")
 	}
 	return &iam.CreateUserOutput{User: &iam.User{UserName: input.UserName}}, nil
 }
 
-func (m *mockIAMClient) ListUserPolicies(input *iam.ListUserPoliciesInput) (*iam.ListUserPoliciesOutput, error) {
+func (m *mockIAMClient) ListUserPoliciesWithContext(ctx aws.Context, input *iam.ListUserPoliciesInput, opts ...request.Option) (*iam.ListUserPoliciesOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName || (!m.mockUserExists && m.finalizerTest) {
 		return &iam.ListUserPoliciesOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_listuserpolicies: given username does not match spec", errors.New(""))
 	}
@@ -247,7 +247,7 @@ func (m *mockIAMClient) ListUserPolicies(input *iam.ListUserPoliciesInput) (*iam
 	return &iam.ListUserPoliciesOutput{}, nil
 }
 
-func (m *mockIAMClient) GetUserPolicy(input *iam.GetUserPolicyInput) (*iam.GetUserPolicyOutput, error) {
+func (m *mockIAMClient) GetUserPolicyWithContext(ctx aws.Context, input *iam.GetUserPolicyInput, opts ...request.Option) (*iam.GetUserPolicyOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.GetUserPolicyOutput{}, errors.New("awsmock_getuserpolicy: given username does not match spec")
 	}
@@ -265,14 +265,14 @@ func (m *mockIAMClient) GetUserPolicy(input *iam.GetUserPolicyInput) (*iam.GetUs
 	return &iam.GetUserPolicyOutput{}, nil
 }
 
-func (m *mockIAMClient) PutUserPolicy(input *iam.PutUserPolicyInput) (*iam.PutUserPolicyOutput, error) {
+func (m *mockIAMClient) PutUserPolicyWithContext(ctx aws.Context, input *iam.PutUserPolicyInput, opts ...request.Option) (*iam.PutUserPolicyOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.PutUserPolicyOutput{}, errors.New("awsmock_putuserpolicy: username did not match spec")
 	}
 	return &iam.PutUserPolicyOutput{}, nil
 }
 
-func (m *mockIAMClient) DeleteUserPolicy(input *iam.DeleteUserPolicyInput) (*iam.DeleteUserPolicyOutput, error) {
+func (m *mockIAMClient) DeleteUserPolicyWithContext(ctx aws.Context, input *iam.DeleteUserPolicyInput, opts ...request.Option) (*iam.DeleteUserPolicyOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.DeleteUserPolicyOutput{}, errors.New("awsmock_deleteuserpolicy: username did not match spec")
 	}
@@ -283,7 +283,7 @@ func (m *mockIAMClient) DeleteUserPolicy(input *iam.DeleteUserPolicyInput) (*iam
 	return &iam.DeleteUserPolicyOutput{}, errors.New("awsmock_deleteuserpolicy: policy shouldn't be getting deleted")
 }
 
-func (m *mockIAMClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
+func (m *mockIAMClient) CreateAccessKeyWithContext(ctx aws.Context, input *iam.CreateAccessKeyInput, opts ...request.Option) (*iam.CreateAccessKeyOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.CreateAccessKeyOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_createaccesskey: username did not match spec", errors.New(""))
 	}
@@ -299,7 +299,7 @@ func (m *mockIAMClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.C
 	}, nil
 }
 
-func (m *mockIAMClient) DeleteAccessKey(input *iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error) {
+func (m *mockIAMClient) DeleteAccessKeyWithContext(ctx aws.Context, input *iam.DeleteAccessKeyInput, opts ...request.Option) (*iam.DeleteAccessKeyOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.DeleteAccessKeyOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_deleteaccesskey: username did not match spec", errors.New(""))
 	}
@@ -309,7 +309,7 @@ func (m *mockIAMClient) DeleteAccessKey(input *iam.DeleteAccessKeyInput) (*iam.D
 	return &iam.DeleteAccessKeyOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_deleteaccesskey: access key does not exist", errors.New(""))
 }
 
-func (m *mockIAMClient) ListAccessKeys(input *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
+func (m *mockIAMClient) ListAccessKeysWithContext(ctx aws.Context, input *iam.ListAccessKeysInput, opts ...request.Option) (*iam.ListAccessKeysOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName || (!m.mockUserExists && m.finalizerTest) {
 		return &iam.ListAccessKeysOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_listaccesskeys: username did not match spec", errors.New(""))
 	}
@@ -319,7 +319,7 @@ func (m *mockIAMClient) ListAccessKeys(input *iam.ListAccessKeysInput) (*iam.Lis
 	return &iam.ListAccessKeysOutput{}, nil
 }
 
-func (m *mockIAMClient) ListUserTags(input *iam.ListUserTagsInput) (*iam.ListUserTagsOutput, error) {
+func (m *mockIAMClient) ListUserTagsWithContext(ctx aws.Context, input *iam.ListUserTagsInput, opts ...request.Option) (*iam.ListUserTagsOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.ListUserTagsOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_listusertags: username did not match spec", errors.New(""))
 	}
@@ -338,7 +338,7 @@ func (m *mockIAMClient) ListUserTags(input *iam.ListUserTagsInput) (*iam.ListUse
 	return &iam.ListUserTagsOutput{}, nil
 }
 
-func (m *mockIAMClient) TagUser(input *iam.TagUserInput) (*iam.TagUserOutput, error) {
+func (m *mockIAMClient) TagUserWithContext(ctx aws.Context, input *iam.TagUserInput, opts ...request.Option) (*iam.TagUserOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName {
 		return &iam.TagUserOutput{}, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_taguser: username did not match spec", errors.New(""))
 	}
@@ -346,7 +346,7 @@ func (m *mockIAMClient) TagUser(input *iam.TagUserInput) (*iam.TagUserOutput, er
 	return &iam.TagUserOutput{}, nil
 }
 
-func (m *mockIAMClient) DeleteUser(input *iam.DeleteUserInput) (*iam.DeleteUserOutput, error) {
+func (m *mockIAMClient) DeleteUserWithContext(ctx aws.Context, input *iam.DeleteUserInput, opts ...request.Option) (*iam.DeleteUserOutput, error) {
 	if aws.StringValue(input.UserName) != instance.Spec.UserName || (!m.mockUserExists && m.finalizerTest) {
 		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "awsmock_deleteuser: username did not match spec", errors.New(""))
 	}
@@ -17,7 +17,6 @@ limitations under the License.
 package components
 
 import (
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/Ridecell/ridecell-operator/pkg/utils"
@@ -26,15 +25,18 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"os"
 )
 
 const mockCarServerTenantFinalizer = "finalizer.mockcarservertenant.summon.ridecell.io"
 
-type MockCarServerTenantComponent struct{}
+type MockCarServerTenantComponent struct {
+	finalizer *components.Finalizer
+}
 
 func NewMockCarServerTenant() *MockCarServerTenantComponent {
-	return &MockCarServerTenantComponent{}
+	comp := &MockCarServerTenantComponent{}
+	comp.finalizer = components.NewFinalizer(mockCarServerTenantFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (_ *MockCarServerTenantComponent) WatchTypes() []runtime.Object {
@@ -52,40 +54,9 @@ func (comp *MockCarServerTenantComponent) Reconcile(ctx *components.ComponentCon
 		// Error reading the object - requeue the request.
 		return components.Result{}, errors.Wrapf(err, "instance of MockCarServerTenant not found")
 	}
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(mockCarServerTenantFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(mockCarServerTenantFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(mockCarServerTenantFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				isDeleted, err := utils.DeleteMockTenant(instance.Name)
-				if err != nil && !(isDeleted) {
-					return components.Result{}, errors.Wrapf(err, "failed to delete MockCarServerTenant from server")
-				}
-				secret := &corev1.Secret{}
-				err = ctx.Client.Get(ctx.Context, types.NamespacedName{Name: instance.Name + ".tenant-otakeys", Namespace: instance.Namespace}, secret)
-				if err == nil {
-					err = ctx.Delete(ctx.Context, secret)
-					if err != nil {
-						return components.Result{}, errors.Wrapf(err, "failed to delete MockCarServerTenant secret")
-					}
-				} else if err != nil && !k8serrors.IsNotFound(err) {
-					return components.Result{}, errors.Wrapf(err, "failed to delete MockCarServerTenant secret")
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(mockCarServerTenantFinalizer, instance)
-			err = ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update MockCarServerTenant while removing finalizer")
-			}
-		}
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 	// Get our password secret
 	otakeysSecret := &corev1.Secret{}
@@ -107,7 +78,7 @@ func (comp *MockCarServerTenantComponent) Reconcile(ctx *components.ComponentCon
 	postData["tenant_hardware_type"] = instance.Spec.TenantHardwareType
 	postData["callback_url"] = instance.Spec.CallbackUrl
 	// Create mock tenant
-	isCreated, err := utils.CreateOrUpdateMockTenant(postData)
+	isCreated, err := utils.CreateOrUpdateMockTenant(ctx.Context, postData)
 	if err != nil && !(isCreated) {
 		return components.Result{}, errors.Wrapf(err, "mockcarservertenant: failed to create otakeys")
 	}
@@ -118,3 +89,22 @@ func (comp *MockCarServerTenantComponent) Reconcile(ctx *components.ComponentCon
 		return nil
 	}}, nil
 }
+
+func (_ *MockCarServerTenantComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*summonv1beta1.MockCarServerTenant)
+	isDeleted, err := utils.DeleteMockTenant(ctx.Context, instance.Name)
+	if err != nil && !(isDeleted) {
+		return components.Result{}, errors.Wrapf(err, "failed to delete MockCarServerTenant from server")
+	}
+	secret := &corev1.Secret{}
+	err = ctx.Client.Get(ctx.Context, types.NamespacedName{Name: instance.Name + ".tenant-otakeys", Namespace: instance.Namespace}, secret)
+	if err == nil {
+		err = ctx.Delete(ctx.Context, secret)
+		if err != nil {
+			return components.Result{}, errors.Wrapf(err, "failed to delete MockCarServerTenant secret")
+		}
+	} else if !k8serrors.IsNotFound(err) {
+		return components.Result{}, errors.Wrapf(err, "failed to delete MockCarServerTenant secret")
+	}
+	return components.Result{}, nil
+}
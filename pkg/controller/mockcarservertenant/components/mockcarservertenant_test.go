@@ -55,7 +55,7 @@ var _ = Describe("MockCarServerTenant Component", func() {
 		secret := &corev1.Secret{}
 		err := ctx.Get(ctx.Context, types.NamespacedName{Name: "test-dev.tenant-otakeys", Namespace: "summon-dev"}, secret)
 		Expect(err).ToNot(HaveOccurred())
-		_, err = utils.GetMockTenant("test-dev")
+		_, err = utils.GetMockTenant(ctx.Context, "test-dev")
 		Expect(err).ToNot(HaveOccurred())
 	})
 
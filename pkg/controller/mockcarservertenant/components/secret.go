@@ -23,7 +23,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"math/rand"
-	"os"
 	"time"
 )
 
@@ -75,7 +74,7 @@ func (comp *secretComponent) Reconcile(ctx *components.ComponentContext) (compon
 		if !ok || len(val) == 0 {
 			existing.Data["OTAKEYS_PUSH_TOKEN"] = RandStringBytes(32)
 		}
-		existing.Data["OTAKEYS_BASE_URL"] = []byte(os.Getenv("MOCKCARSERVER_URI") + "/otakeys/")
+		existing.Data["OTAKEYS_BASE_URL"] = []byte(ctx.Config().Get("MOCKCARSERVER_URI") + "/otakeys/")
 		return nil
 	})
 	if err != nil {
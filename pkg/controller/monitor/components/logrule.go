@@ -19,10 +19,8 @@ package components
 import (
 	"encoding/base64"
 	"fmt"
-	"os"
 	"regexp"
 
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	monitoringv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/monitoring/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/Ridecell/ridecell-operator/pkg/utils/sumologic"
@@ -66,11 +64,12 @@ func (comp *logruleComponent) Reconcile(ctx *components.ComponentContext) (compo
 		return components.Result{}, nil
 	}
 	// Create sumologic client
-	client, _ := sumologic.NewClient("https://api.us2.sumologic.com", os.Getenv("SUMO_ACCESS_ID"), os.Getenv("SUMO_ACCESS_KEY"))
+	client, _ := sumologic.NewClient("https://api.us2.sumologic.com", ctx.Config().Get("SUMO_ACCESS_ID"), ctx.Config().Get("SUMO_ACCESS_KEY"))
 	// Run with mockserver
-	if len(os.Getenv("SUMO_MOCK_URL")) > 0 {
-		client, _ = sumologic.NewClient(os.Getenv("SUMO_MOCK_URL"), os.Getenv("SUMO_ACCESS_ID"), os.Getenv("SUMO_ACCESS_KEY"))
+	if len(ctx.Config().Get("SUMO_MOCK_URL")) > 0 {
+		client, _ = sumologic.NewClient(ctx.Config().Get("SUMO_MOCK_URL"), ctx.Config().Get("SUMO_ACCESS_ID"), ctx.Config().Get("SUMO_ACCESS_KEY"))
 	}
+	client = client.WithContext(ctx.Context)
 	connections, err := client.ListConnections()
 	if err != nil {
 		return components.Result{}, errors.Wrap(err, "Failed to list connections")
@@ -79,7 +78,7 @@ func (comp *logruleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	// Check connection
 	connectionToUse := ""
 	for _, connection := range connections.Data {
-		if connection.Name == os.Getenv("ALERTMANAGER_NAME") {
+		if connection.Name == ctx.Config().Get("ALERTMANAGER_NAME") {
 			connectionToUse = connection.ID
 		}
 
@@ -87,12 +86,12 @@ func (comp *logruleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	// Create connection
 	connection := sumologic.WebHookConnection{
 		Type:           "WebhookDefinition",
-		Name:           os.Getenv("ALERTMANAGER_NAME"),
+		Name:           ctx.Config().Get("ALERTMANAGER_NAME"),
 		Description:    "Created by ridecell-operator DO NOT modify",
-		URL:            fmt.Sprintf("https://%s/api/v1/alerts", os.Getenv("ALERTMANAGER_NAME")),
+		URL:            fmt.Sprintf("https://%s/api/v1/alerts", ctx.Config().Get("ALERTMANAGER_NAME")),
 		DefaultPayload: "{}",
 		Headers: []sumologic.WebHookHeaders{
-			{Name: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(os.Getenv("ALERTMANAGER_AUTH")))},
+			{Name: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(ctx.Config().Get("ALERTMANAGER_AUTH")))},
 		},
 		WebhookType: "Webhook",
 	}
@@ -128,40 +127,12 @@ func (comp *logruleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}
 
 	// Finalizer start here
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(logruleFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(logruleFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(logruleFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				contents, err := client.GetFolder(serviceFolderid)
-				if err != nil {
-					return components.Result{}, errors.Wrapf(err, "Failed to get folder at the time Finalizer")
-				}
-				for _, content := range contents.Children {
-					for _, rule := range instance.Spec.LogAlertRules {
-						if rule.Name == content.Name && content.ItemType == "Search" {
-							_, err := client.DeleteContent(content.ID)
-							if err != nil {
-								return components.Result{}, errors.Wrapf(err, "failed to delete rule with name %s", rule.Name)
-							}
-						}
-					}
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(logruleFinalizer, instance)
-			err = ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update LogAlertsRule while removing finalizer")
-			}
-		}
-		return components.Result{}, nil
+	finalizer := components.NewFinalizer(logruleFinalizer, func(ctx *components.ComponentContext) (components.Result, error) {
+		return comp.deleteRules(ctx, client, serviceFolderid)
+	})
+	res, deleting, err := finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
 	for _, rule := range instance.Spec.LogAlertRules {
@@ -248,3 +219,22 @@ func (comp *logruleComponent) Reconcile(ctx *components.ComponentContext) (compo
 	}
 	return components.Result{}, nil
 }
+
+func (_ *logruleComponent) deleteRules(ctx *components.ComponentContext, client *sumologic.Client, serviceFolderid string) (components.Result, error) {
+	instance := ctx.Top.(*monitoringv1beta1.Monitor)
+	contents, err := client.GetFolder(serviceFolderid)
+	if err != nil {
+		return components.Result{}, errors.Wrapf(err, "Failed to get folder at the time Finalizer")
+	}
+	for _, content := range contents.Children {
+		for _, rule := range instance.Spec.LogAlertRules {
+			if rule.Name == content.Name && content.ItemType == "Search" {
+				_, err := client.DeleteContent(content.ID)
+				if err != nil {
+					return components.Result{}, errors.Wrapf(err, "failed to delete rule with name %s", rule.Name)
+				}
+			}
+		}
+	}
+	return components.Result{}, nil
+}
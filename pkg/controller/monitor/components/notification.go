@@ -18,15 +18,14 @@ package components
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	monitoringv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/monitoring/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/utils"
 	pagerduty "github.com/heimweh/go-pagerduty/pagerduty"
 	alertmconfig "github.com/prometheus/alertmanager/config"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,10 +35,13 @@ const notificationFinalizer = "finalizer.notification.monitoring.ridecell.io"
 
 type notificationComponent struct {
 	PgBaseURL string
+	finalizer *components.Finalizer
 }
 
 func NewNotification() *notificationComponent {
-	return &notificationComponent{}
+	comp := &notificationComponent{}
+	comp.finalizer = components.NewFinalizer(notificationFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *notificationComponent) UpdateBaseURL(pgBaseURL string) {
@@ -69,42 +71,15 @@ func (comp *notificationComponent) Reconcile(ctx *components.ComponentContext) (
 		return components.Result{}, nil
 	}
 
-	client, _ := pagerduty.NewClient(&pagerduty.Config{Token: os.Getenv("PG_API_KEY"), BaseURL: "https://api.pagerduty.com"})
-	if len(os.Getenv("PG_MOCK_URL")) > 0 {
-		client, _ = pagerduty.NewClient(&pagerduty.Config{Token: os.Getenv("PG_API_KEY"), BaseURL: os.Getenv("PG_MOCK_URL")})
+	httpClient := utils.ContextHTTPClient(ctx.Context, nil)
+	client, _ := pagerduty.NewClient(&pagerduty.Config{Token: ctx.Config().Get("PG_API_KEY"), BaseURL: "https://api.pagerduty.com", HTTPClient: httpClient})
+	if len(ctx.Config().Get("PG_MOCK_URL")) > 0 {
+		client, _ = pagerduty.NewClient(&pagerduty.Config{Token: ctx.Config().Get("PG_API_KEY"), BaseURL: ctx.Config().Get("PG_MOCK_URL"), HTTPClient: httpClient})
 	}
 
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(notificationFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(notificationFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(notificationFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				//remove alertmanagrconfig
-				amc := &monitoringv1beta1.AlertManagerConfig{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      fmt.Sprintf("alertmanagerconfig-%s", instance.Name),
-						Namespace: instance.Namespace,
-					}}
-				err := ctx.Delete(ctx.Context, amc)
-				if err != nil {
-					return components.Result{}, errors.Wrapf(err, "failed to delete notification %s", instance.Name)
-				}
-				// TODO remove service/event rule from PG
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(notificationFinalizer, instance)
-			err = ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update notification while removing finalizer")
-			}
-		}
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
 	extras := map[string]interface{}{}
@@ -132,7 +107,7 @@ func (comp *notificationComponent) Reconcile(ctx *components.ComponentContext) (
 						Type: "button",
 					},
 					&alertmconfig.SlackAction{
-						URL:  fmt.Sprintf("https://%s/#/silences", os.Getenv("ALERTMANAGER_NAME")),
+						URL:  fmt.Sprintf("https://%s/#/silences", ctx.Config().Get("ALERTMANAGER_NAME")),
 						Text: "Silence :no_bell:",
 						Type: "button",
 					},
@@ -163,10 +138,10 @@ func (comp *notificationComponent) Reconcile(ctx *components.ComponentContext) (
 					NotifierConfig: alertmconfig.NotifierConfig{
 						VSendResolved: true,
 					},
-					RoutingKey:  alertmconfig.Secret(os.Getenv("PG_ROUTING_KEY")),
+					RoutingKey:  alertmconfig.Secret(ctx.Config().Get("PG_ROUTING_KEY")),
 					Severity:    `{{ if .CommonLabels.severity }}{{ .CommonLabels.severity | toLower }}{{ else }}critical{{ end }}`,
-					Client:      os.Getenv("ALERTMANAGER_NAME"),
-					ClientURL:   fmt.Sprintf("https://%s", os.Getenv("ALERTMANAGER_NAME")),
+					Client:      ctx.Config().Get("ALERTMANAGER_NAME"),
+					ClientURL:   fmt.Sprintf("https://%s", ctx.Config().Get("ALERTMANAGER_NAME")),
 					Description: `{{ template "pagerduty.default.description" .}}`},
 			}}
 
@@ -275,3 +250,19 @@ func (comp *notificationComponent) Reconcile(ctx *components.ComponentContext) (
 	}}, nil
 
 }
+
+func (_ *notificationComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*monitoringv1beta1.Monitor)
+	//remove alertmanagrconfig
+	amc := &monitoringv1beta1.AlertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("alertmanagerconfig-%s", instance.Name),
+			Namespace: instance.Namespace,
+		}}
+	err := ctx.Delete(ctx.Context, amc)
+	if err != nil {
+		return components.Result{}, errors.Wrapf(err, "failed to delete notification %s", instance.Name)
+	}
+	// TODO remove service/event rule from PG
+	return components.Result{}, nil
+}
@@ -20,9 +20,7 @@ import (
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"os"
 
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	monitoringv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/monitoring/v1beta1"
 	pomonitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
@@ -32,10 +30,13 @@ import (
 const promruleFinalizer = "finalizer.promrule.monitoring.ridecell.io"
 
 type promruleComponent struct {
+	finalizer *components.Finalizer
 }
 
 func NewPromrule() *promruleComponent {
-	return &promruleComponent{}
+	comp := &promruleComponent{}
+	comp.finalizer = components.NewFinalizer(promruleFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (_ *promruleComponent) WatchTypes() []runtime.Object {
@@ -56,40 +57,12 @@ func (comp *promruleComponent) Reconcile(ctx *components.ComponentContext) (comp
 		return components.Result{}, nil
 	}
 
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(promruleFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(promruleFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(promruleFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				promrule := &pomonitoringv1.PrometheusRule{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      instance.Name,
-						Namespace: instance.Namespace,
-					}}
-				err := ctx.Delete(ctx.Context, promrule)
-				if err != nil {
-					if !k8serr.IsNotFound(err) {
-						return components.Result{}, errors.Wrapf(err, "failed to delete PrometheusRule ")
-					}
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(promruleFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "failed to update PrometheusRule while removing finalizer")
-			}
-		}
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
-	res, _, err := ctx.CreateOrUpdate("prometheus_rule.yml.tpl", nil, func(goalObj, existingObj runtime.Object) error {
+	res, _, err = ctx.CreateOrUpdate("prometheus_rule.yml.tpl", nil, func(goalObj, existingObj runtime.Object) error {
 		goal := goalObj.(*pomonitoringv1.PrometheusRule)
 		existing := existingObj.(*pomonitoringv1.PrometheusRule)
 		existing.Spec = goal.Spec
@@ -100,3 +73,17 @@ func (comp *promruleComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 	return res, nil
 }
+
+func (_ *promruleComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*monitoringv1beta1.Monitor)
+	promrule := &pomonitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+		}}
+	err := ctx.Delete(ctx.Context, promrule)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return components.Result{}, errors.Wrapf(err, "failed to delete PrometheusRule ")
+	}
+	return components.Result{}, nil
+}
@@ -1,5 +1,3 @@
-// +build !release
-
 /*
 Copyright 2019 Ridecell, Inc.
 Licensed under the Apache License, Version 2.0 (the "License");
@@ -16,18 +14,8 @@ limitations under the License.
 package monitor
 
 import (
-	"net/http"
-	"path"
-	"runtime"
+	"github.com/Ridecell/ridecell-operator/pkg/templates"
 )
 
 //go:generate bash ../../../hack/assets_generate.sh controller/monitor monitor
-var Templates http.FileSystem
-
-func init() {
-	_, line, _, ok := runtime.Caller(0)
-	if !ok {
-		panic("Unable to find caller line")
-	}
-	Templates = http.Dir(path.Dir(line) + "/templates")
-}
+var Templates = templates.Named("controller/monitor", assets)
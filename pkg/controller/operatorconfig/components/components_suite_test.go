@@ -0,0 +1,49 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"testing"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis"
+	configv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/config/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+)
+
+var instance *configv1beta1.OperatorConfig
+var ctx *components.ComponentContext
+
+func TestTemplates(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	err := apis.AddToScheme(scheme.Scheme)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	ginkgo.RunSpecs(t, "OperatorConfig Components Suite @unit")
+}
+
+var _ = ginkgo.BeforeEach(func() {
+	// Set up default-y values for tests to use if they want.
+	instance = &configv1beta1.OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+	}
+	ctx = &components.ComponentContext{Top: instance, Client: fake.NewFakeClient(), Scheme: scheme.Scheme}
+})
@@ -0,0 +1,135 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/config/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// Name of the OperatorConfig this operator reads, others are ignored. Set from flags.
+var ConfigName = configv1beta1.DefaultOperatorConfigName
+
+// How often to reload, to pick up rotated secrets.
+const reloadInterval = 5 * time.Minute
+
+type operatorConfigComponent struct{}
+
+func NewOperatorConfig() *operatorConfigComponent {
+	return &operatorConfigComponent{}
+}
+
+func (_ *operatorConfigComponent) WatchTypes() []runtime.Object {
+	return []runtime.Object{}
+}
+
+func (_ *operatorConfigComponent) IsReconcilable(_ *components.ComponentContext) bool {
+	return true
+}
+
+func (_ *operatorConfigComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*configv1beta1.OperatorConfig)
+
+	if instance.Name != ConfigName {
+		return components.Result{StatusModifier: func(obj runtime.Object) error {
+			instance := obj.(*configv1beta1.OperatorConfig)
+			instance.Status.Status = configv1beta1.StatusError
+			instance.Status.Message = "Ignored, the operator reads " + ConfigName
+			return nil
+		}}, nil
+	}
+
+	// Keep whatever was in effect before if this one is broken.
+	cfg, err := Load(ctx.Context, ctx, instance)
+	if err != nil {
+		return components.Result{}, err
+	}
+	config.SetCurrent(cfg)
+	ctx.Logger().Info("Loaded operator config", "generation", instance.Generation)
+
+	return components.Result{RequeueAfter: reloadInterval, StatusModifier: func(obj runtime.Object) error {
+		instance := obj.(*configv1beta1.OperatorConfig)
+		instance.Status.Status = configv1beta1.StatusReady
+		instance.Status.Message = ""
+		instance.Status.ObservedGeneration = instance.Generation
+		instance.Status.Settings = settingStatuses(cfg)
+		instance.Status.FeatureGates = cfg.EnabledGates()
+		return nil
+	}}, nil
+}
+
+// Validate an OperatorConfig and resolve its secrets into a Config. Validation errors are
+// permanent, problems reading the secrets are left to be retried.
+func Load(ctx context.Context, c client.Client, instance *configv1beta1.OperatorConfig) (*config.Config, error) {
+	plain := []string{}
+	for name := range instance.Spec.Settings {
+		plain = append(plain, name)
+	}
+	secret := []string{}
+	for name := range instance.Spec.SecretSettings {
+		secret = append(secret, name)
+	}
+	gates := []string{}
+	for name := range instance.Spec.FeatureGates {
+		gates = append(gates, name)
+	}
+	err := config.Validate(plain, secret, gates)
+	if err != nil {
+		return nil, errors.InvalidSpec(err)
+	}
+
+	values := map[string]string{}
+	sources := map[string]string{}
+	for name, value := range instance.Spec.Settings {
+		values[name] = value
+		sources[name] = config.SourceOperatorConfig
+	}
+	for name, ref := range instance.Spec.SecretSettings {
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting secret %s/%s for %s", ref.Namespace, ref.Name, name)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, errors.Errorf("secret %s/%s for %s has no key %s", ref.Namespace, ref.Name, name, ref.Key)
+		}
+		values[name] = string(value)
+		sources[name] = config.SourceSecret
+	}
+	return config.New(values, sources, instance.Spec.FeatureGates), nil
+}
+
+func settingStatuses(cfg *config.Config) []configv1beta1.SettingStatus {
+	statuses := []configv1beta1.SettingStatus{}
+	for _, setting := range config.Settings {
+		statuses = append(statuses, configv1beta1.SettingStatus{Name: setting.Name, Source: cfg.Source(setting.Name)})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/config/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	occomponents "github.com/Ridecell/ridecell-operator/pkg/controller/operatorconfig/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
+)
+
+var _ = Describe("operatorconfig Component", func() {
+	AfterEach(func() {
+		config.SetCurrent(nil)
+	})
+
+	It("loads plain and secret settings", func() {
+		instance.Spec.Settings = map[string]string{"AWS_REGION": "us-west-2"}
+		instance.Spec.SecretSettings = map[string]configv1beta1.SecretKeyRef{
+			"SLACK_API_KEY": {Namespace: "ridecell-operator", Name: "slack", Key: "token"},
+		}
+		instance.Spec.FeatureGates = map[string]bool{config.FeatureFinalizers: true}
+		ctx.Client = fake.NewFakeClient(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "ridecell-operator"},
+			Data:       map[string][]byte{"token": []byte("xoxb-123")},
+		})
+
+		comp := occomponents.NewOperatorConfig()
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(configv1beta1.StatusReady))
+		Expect(config.Get("AWS_REGION")).To(Equal("us-west-2"))
+		Expect(config.Get("SLACK_API_KEY")).To(Equal("xoxb-123"))
+		Expect(config.Enabled(config.FeatureFinalizers)).To(BeTrue())
+		Expect(instance.Status.Settings).To(ContainElement(configv1beta1.SettingStatus{Name: "SLACK_API_KEY", Source: config.SourceSecret}))
+	})
+
+	It("rejects a secret setting given as plain text", func() {
+		instance.Spec.Settings = map[string]string{"SLACK_API_KEY": "xoxb-123"}
+
+		comp := occomponents.NewOperatorConfig()
+		Expect(comp).NotTo(ReconcileContext(ctx))
+		Expect(config.Current()).To(BeNil())
+	})
+
+	It("rejects an unknown feature gate", func() {
+		instance.Spec.FeatureGates = map[string]bool{"Teleportation": true}
+
+		comp := occomponents.NewOperatorConfig()
+		Expect(comp).NotTo(ReconcileContext(ctx))
+	})
+
+	It("fails when a referenced secret is missing", func() {
+		instance.Spec.SecretSettings = map[string]configv1beta1.SecretKeyRef{
+			"SLACK_API_KEY": {Namespace: "ridecell-operator", Name: "slack", Key: "token"},
+		}
+
+		comp := occomponents.NewOperatorConfig()
+		Expect(comp).NotTo(ReconcileContext(ctx))
+	})
+
+	It("treats validation errors as permanent", func() {
+		instance.Spec.FeatureGates = map[string]bool{"Teleportation": true}
+
+		_, err := occomponents.Load(context.TODO(), fake.NewFakeClient(), instance)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.ClassOf(err)).To(Equal(errors.ClassPermanent))
+	})
+
+	It("retries when a referenced secret can't be read", func() {
+		instance.Spec.SecretSettings = map[string]configv1beta1.SecretKeyRef{
+			"SLACK_API_KEY": {Namespace: "ridecell-operator", Name: "slack", Key: "token"},
+		}
+
+		_, err := occomponents.Load(context.TODO(), fake.NewFakeClient(), instance)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.ClassOf(err)).ToNot(Equal(errors.ClassPermanent))
+	})
+
+	It("ignores configs with another name", func() {
+		instance.Name = "other"
+		instance.Spec.Settings = map[string]string{"AWS_REGION": "us-west-2"}
+
+		comp := occomponents.NewOperatorConfig()
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(configv1beta1.StatusError))
+		Expect(config.Current()).To(BeNil())
+	})
+})
@@ -53,7 +53,7 @@ func (comp *databaseComponent) Reconcile(ctx *components.ComponentContext) (comp
 		return components.Result{}, err
 	}
 
-	row := db.QueryRow(`SELECT COUNT(*) FROM pg_catalog.pg_database WHERE datname = $1`, instance.Spec.DatabaseName)
+	row := db.QueryRowContext(ctx.Context, `SELECT COUNT(*) FROM pg_catalog.pg_database WHERE datname = $1`, instance.Spec.DatabaseName)
 	var count int
 	err = row.Scan(&count)
 	if err != nil {
@@ -61,7 +61,7 @@ func (comp *databaseComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 
 	// Checks if adminuser already a member of the database owner
-	row = db.QueryRow(`SELECT pg_has_role($1, $2, 'member')`, instance.Status.AdminConnection.Username, instance.Spec.Owner)
+	row = db.QueryRowContext(ctx.Context, `SELECT pg_has_role($1, $2, 'member')`, instance.Status.AdminConnection.Username, instance.Spec.Owner)
 	var aMember bool
 	err = row.Scan(&aMember)
 	if err != nil {
@@ -70,7 +70,7 @@ func (comp *databaseComponent) Reconcile(ctx *components.ComponentContext) (comp
 
 	if !aMember {
 		// Grant our admin user access to the owner role. This matters on RDS where the admin user is not a true superuser.
-		_, err := db.Exec(fmt.Sprintf(`GRANT %s TO %s`, pq.QuoteIdentifier(instance.Spec.Owner), pq.QuoteIdentifier(instance.Status.AdminConnection.Username)))
+		_, err := db.ExecContext(ctx.Context, fmt.Sprintf(`GRANT %s TO %s`, pq.QuoteIdentifier(instance.Spec.Owner), pq.QuoteIdentifier(instance.Status.AdminConnection.Username)))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: error granting role")
 		}
@@ -78,7 +78,7 @@ func (comp *databaseComponent) Reconcile(ctx *components.ComponentContext) (comp
 
 	if count == 0 {
 		// Time to make the database.
-		_, err = db.Exec(fmt.Sprintf(`CREATE DATABASE %s WITH OWNER = %s`, pq.QuoteIdentifier(instance.Spec.DatabaseName), utils.QuoteLiteral(instance.Spec.Owner)))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf(`CREATE DATABASE %s WITH OWNER = %s`, pq.QuoteIdentifier(instance.Spec.DatabaseName), utils.QuoteLiteral(instance.Spec.Owner)))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: error creating database")
 		}
@@ -57,7 +57,7 @@ func (comp *periscopeUserComponent) Reconcile(ctx *components.ComponentContext)
 		return components.Result{}, err
 	}
 
-	row := db.QueryRow(`select COUNT(*) from pg_user where usename='periscope'`)
+	row := db.QueryRowContext(ctx.Context, `select COUNT(*) from pg_user where usename='periscope'`)
 	var count int
 	err = row.Scan(&count)
 
@@ -66,7 +66,7 @@ func (comp *periscopeUserComponent) Reconcile(ctx *components.ComponentContext)
 	}
 
 	// Check if periscope was already granted permissions before.
-	row = db.QueryRow(`SELECT COUNT(*) FROM information_schema.table_privileges where grantee='periscope'`)
+	row = db.QueryRowContext(ctx.Context, `SELECT COUNT(*) FROM information_schema.table_privileges where grantee='periscope'`)
 	err = row.Scan(&count)
 	if err != nil {
 		return components.Result{}, errors.Wrap(err, "database: error running db check query for periscope grants")
@@ -75,13 +75,13 @@ func (comp *periscopeUserComponent) Reconcile(ctx *components.ComponentContext)
 	// if it hasn't occurred before, connect to summon instance's database and grant periscope read-permissions to public schema.
 	if count == 0 {
 		// Grant read access to periscope user for the postgres database.
-		_, err = db.Exec("GRANT SELECT ON ALL TABLES IN SCHEMA public TO periscope")
+		_, err = db.ExecContext(ctx.Context, "GRANT SELECT ON ALL TABLES IN SCHEMA public TO periscope")
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: error granting periscope user read-permissions")
 		}
 
 		// Grant periscope read permissions to any future tables added to public schema for the database
-		_, err = db.Exec(fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA public GRANT SELECT ON TABLES TO periscope", pq.QuoteIdentifier(instance.Spec.Owner)))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA public GRANT SELECT ON TABLES TO periscope", pq.QuoteIdentifier(instance.Spec.Owner)))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: error granting periscope user read-permissions for future public schema tables")
 		}
@@ -54,25 +54,25 @@ func (comp *databaseComponent) Reconcile(ctx *components.ComponentContext) (comp
 	// Two codepaths because both queries look very different depending on if we have a version or not.
 	if instance.Spec.Version == "" {
 		// Create the extension if it doesn't exist already.
-		_, err = db.Exec(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s", pq.QuoteIdentifier(instance.Spec.ExtensionName)))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s", pq.QuoteIdentifier(instance.Spec.ExtensionName)))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: Error running CREATE EXTENSION")
 		}
 
 		// Upgrade the extension if it did exist.
-		_, err = db.Exec(fmt.Sprintf("ALTER EXTENSION %s UPDATE", pq.QuoteIdentifier(instance.Spec.ExtensionName)))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf("ALTER EXTENSION %s UPDATE", pq.QuoteIdentifier(instance.Spec.ExtensionName)))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: Error running ALTER EXTENSION")
 		}
 	} else {
 		// Create the extension if it doesn't exist already.
-		_, err = db.Exec(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s WITH VERSION %s", pq.QuoteIdentifier(instance.Spec.ExtensionName), pq.QuoteIdentifier(instance.Spec.Version)))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s WITH VERSION %s", pq.QuoteIdentifier(instance.Spec.ExtensionName), pq.QuoteIdentifier(instance.Spec.Version)))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: Error running CREATE EXTENSION")
 		}
 
 		// Upgrade the extension if it did exist.
-		_, err = db.Exec(fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", pq.QuoteIdentifier(instance.Spec.ExtensionName), pq.QuoteIdentifier(instance.Spec.Version)))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", pq.QuoteIdentifier(instance.Spec.ExtensionName), pq.QuoteIdentifier(instance.Spec.Version)))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "database: Error running ALTER EXTENSION")
 		}
@@ -59,7 +59,7 @@ func (comp *PostgresUserComponent) Reconcile(ctx *components.ComponentContext) (
 	}
 
 	// Check if user exists
-	userRows, err := db.Query("SELECT usename FROM pg_user")
+	userRows, err := db.QueryContext(ctx.Context, "SELECT usename FROM pg_user")
 	if err != nil {
 		return components.Result{}, errors.Wrap(err, "postgres_user: failed to query users")
 	}
@@ -90,7 +90,7 @@ func (comp *PostgresUserComponent) Reconcile(ctx *components.ComponentContext) (
 	quotedPassword := utils.QuoteLiteral(password)
 	// Create the user if it doesn't exist
 	if !userExists {
-		_, err = db.Exec(fmt.Sprintf("CREATE USER %s WITH PASSWORD %s", quotedUsername, quotedPassword))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf("CREATE USER %s WITH PASSWORD %s", quotedUsername, quotedPassword))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "postgres_user: failed to create database user")
 		}
@@ -108,7 +108,7 @@ func (comp *PostgresUserComponent) Reconcile(ctx *components.ComponentContext) (
 	}
 
 	var invalidPassword bool
-	noOpRows, err := testdb.Query(`SELECT 1`)
+	noOpRows, err := testdb.QueryContext(ctx.Context, `SELECT 1`)
 	if err != nil {
 		// 28P01 == invalid password
 		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code == "28P01" {
@@ -121,7 +121,7 @@ func (comp *PostgresUserComponent) Reconcile(ctx *components.ComponentContext) (
 	}
 
 	if invalidPassword {
-		_, err = db.Exec(fmt.Sprintf("ALTER USER %s WITH PASSWORD %s", quotedUsername, quotedPassword))
+		_, err = db.ExecContext(ctx.Context, fmt.Sprintf("ALTER USER %s WITH PASSWORD %s", quotedUsername, quotedPassword))
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "postgres_user: failed to update user password")
 		}
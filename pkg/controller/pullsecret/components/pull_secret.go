@@ -53,7 +53,7 @@ func (_ *pullSecretComponent) IsReconcilable(_ *components.ComponentContext) boo
 func (comp *pullSecretComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*secretsv1beta1.PullSecret)
 
-	operatorNamespace := os.Getenv("NAMESPACE")
+	operatorNamespace := ctx.Config().Get("NAMESPACE")
 	if operatorNamespace == "" {
 		var err error
 		operatorNamespace, err = getInClusterNamespace()
@@ -19,6 +19,7 @@ package components
 import (
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -39,6 +40,11 @@ func (_ *defaultsComponent) IsReconcilable(_ *components.ComponentContext) bool
 func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RabbitmqVhost)
 
+	err := Validate(instance)
+	if err != nil {
+		return components.Result{}, errors.InvalidSpec(err)
+	}
+
 	// Fill in defaults.
 	if instance.Spec.VhostName == "" {
 		// Default extension name is just the name of the resource.
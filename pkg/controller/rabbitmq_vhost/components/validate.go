@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	rabbithole "github.com/michaelklishin/rabbit-hole"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+)
+
+// Check a RabbitmqVhost spec before anything is sent to RabbitMQ. Shared with the admission webhook.
+func Validate(instance *dbv1beta1.RabbitmqVhost) error {
+	errs := []error{}
+	for policyName, policy := range instance.Spec.Policies {
+		if policy.Pattern == "" {
+			errs = append(errs, errors.Errorf("policy %s has no pattern", policyName))
+		}
+		switch policy.ApplyTo {
+		case "", "all", "queues", "exchanges":
+		default:
+			errs = append(errs, errors.Errorf("policy %s has invalid apply-to %s, must be one of all, queues, or exchanges", policyName, policy.ApplyTo))
+		}
+		_, err := parsePolicyDefinition(policy)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error unable to parse policy definition for %s", policyName))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func parsePolicyDefinition(policy dbv1beta1.RabbitmqPolicy) (rabbithole.PolicyDefinition, error) {
+	var definition rabbithole.PolicyDefinition
+	err := yaml.Unmarshal([]byte(policy.Definition), &definition)
+	return definition, err
+}
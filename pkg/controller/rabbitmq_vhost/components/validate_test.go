@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	rmqvcomponents "github.com/Ridecell/ridecell-operator/pkg/controller/rabbitmq_vhost/components"
+)
+
+var _ = Describe("RabbitmqVhost Validate", func() {
+	It("accepts a valid policy", func() {
+		instance.Spec.Policies = map[string]dbv1beta1.RabbitmqPolicy{
+			"ha": dbv1beta1.RabbitmqPolicy{Pattern: "^ha\\.", ApplyTo: "queues", Definition: `{"ha-mode": "all"}`},
+		}
+		Expect(rmqvcomponents.Validate(instance)).To(Succeed())
+	})
+
+	It("rejects a policy with a bad apply-to and definition", func() {
+		instance.Spec.Policies = map[string]dbv1beta1.RabbitmqPolicy{
+			"ha": dbv1beta1.RabbitmqPolicy{Pattern: "^ha\\.", ApplyTo: "everything", Definition: "[not a map"},
+		}
+		err := rmqvcomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid apply-to everything"))
+		Expect(err.Error()).To(ContainSubstring("error unable to parse policy definition for ha"))
+	})
+
+	It("fails the defaults component on an invalid policy", func() {
+		instance.Spec.Policies = map[string]dbv1beta1.RabbitmqPolicy{
+			"ha": dbv1beta1.RabbitmqPolicy{Definition: `{"ha-mode": "all"}`},
+		}
+		_, err := rmqvcomponents.NewDefaults().Reconcile(ctx)
+		Expect(err).To(MatchError("policy ha has no pattern"))
+	})
+})
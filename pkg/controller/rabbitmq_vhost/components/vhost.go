@@ -23,7 +23,6 @@ import (
 
 	rabbithole "github.com/michaelklishin/rabbit-hole"
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -52,7 +51,13 @@ func (_ *vhostComponent) IsReconcilable(_ *components.ComponentContext) bool {
 	return true
 }
 
+// Errors from the RabbitMQ API are classified by status code, so bad specs aren't retried.
 func (comp *vhostComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	res, err := comp.reconcile(ctx)
+	return res, utils.RabbitError(err)
+}
+
+func (comp *vhostComponent) reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RabbitmqVhost)
 
 	// Connect to the rabbitmq cluster
@@ -80,7 +85,7 @@ func (comp *vhostComponent) Reconcile(ctx *components.ComponentContext) (compone
 			return components.Result{}, errors.Wrapf(err, "error creating vhost %s", instance.Spec.VhostName)
 		}
 		if resp.StatusCode != 201 {
-			return components.Result{}, errors.Errorf("unable to create vhost %s, got response code %v", instance.Spec.VhostName, resp.StatusCode)
+			return components.Result{}, utils.RabbitStatusError(resp.StatusCode, errors.Errorf("unable to create vhost %s, got response code %v", instance.Spec.VhostName, resp.StatusCode))
 		}
 	}
 
@@ -106,7 +111,7 @@ func (comp *vhostComponent) Reconcile(ctx *components.ComponentContext) (compone
 		newPolicy.Pattern = policy.Pattern
 		newPolicy.ApplyTo = policy.ApplyTo
 		newPolicy.Priority = policy.Priority
-		err := yaml.Unmarshal([]byte(policy.Definition), &newPolicy.Definition)
+		newPolicy.Definition, err = parsePolicyDefinition(policy)
 		if err != nil {
 			return components.Result{}, errors.Wrapf(err, "error unable to parse policy definition for %s", policyName)
 		}
@@ -49,7 +49,13 @@ func (_ *userComponent) IsReconcilable(_ *components.ComponentContext) bool {
 	return true
 }
 
+// Errors from the RabbitMQ API are classified by status code, so bad specs aren't retried.
 func (comp *userComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	res, err := comp.reconcile(ctx)
+	return res, utils.RabbitError(err)
+}
+
+func (comp *userComponent) reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RabbitmqUser)
 
 	// Connect to the rabbitmq cluster
@@ -77,7 +83,7 @@ func (comp *userComponent) Reconcile(ctx *components.ComponentContext) (componen
 		if err != nil {
 			return components.Result{}, errors.Wrapf(err, "error reading PutUser response: %s", resp.Status)
 		}
-		return components.Result{}, errors.Errorf("unable to create rabbitmq user %s: %s %s", instance.Spec.Username, resp.Status, body)
+		return components.Result{}, utils.RabbitStatusError(resp.StatusCode, errors.Errorf("unable to create rabbitmq user %s: %s %s", instance.Spec.Username, resp.Status, body))
 	}
 
 	//Policies
@@ -17,12 +17,11 @@ limitations under the License.
 package components
 
 import (
-	"os"
-
 	"k8s.io/apimachinery/pkg/runtime"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
 )
 
 type defaultsComponent struct {
@@ -43,6 +42,11 @@ func (_ *defaultsComponent) IsReconcilable(_ *components.ComponentContext) bool
 func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSInstance)
 
+	err := Validate(instance)
+	if err != nil {
+		return components.Result{}, errors.InvalidSpec(err)
+	}
+
 	if instance.Spec.AllocatedStorage == 0 {
 		instance.Spec.AllocatedStorage = 100
 	}
@@ -69,7 +73,7 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 
 	if instance.Spec.SubnetGroupName == "" {
-		instance.Spec.SubnetGroupName = os.Getenv("AWS_SUBNET_GROUP_NAME")
+		instance.Spec.SubnetGroupName = ctx.Config().Get("AWS_SUBNET_GROUP_NAME")
 	}
 
 	if instance.Spec.Username == "" {
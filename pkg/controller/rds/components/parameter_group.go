@@ -18,7 +18,6 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
@@ -37,13 +36,16 @@ import (
 const rdsInstanceParameterGroupFinalizer = "rdsinstance.parametergroup.finalizer"
 
 type dbParameterGroupComponent struct {
-	rdsAPI rdsiface.RDSAPI
+	rdsAPI    rdsiface.RDSAPI
+	finalizer *components.Finalizer
 }
 
 func NewDBParameterGroup() *dbParameterGroupComponent {
 	sess := session.Must(session.NewSession())
 	rdsService := rds.New(sess)
-	return &dbParameterGroupComponent{rdsAPI: rdsService}
+	comp := &dbParameterGroupComponent{rdsAPI: rdsService}
+	comp.finalizer = components.NewFinalizer(rdsInstanceParameterGroupFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *dbParameterGroupComponent) InjectRDSAPI(rdsapi rdsiface.RDSAPI) {
@@ -61,44 +63,18 @@ func (_ *dbParameterGroupComponent) IsReconcilable(_ *components.ComponentContex
 func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSInstance)
 
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(rdsInstanceParameterGroupFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(rdsInstanceParameterGroupFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrap(err, "rds: failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(rdsInstanceParameterGroupFinalizer, instance) {
-			// If our database still exists we can't delete the security group
-			if helpers.ContainsFinalizer(RDSInstanceDatabaseFinalizer, instance) {
-				return components.Result{RequeueAfter: time.Minute * 1}, nil
-			}
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				result, err := comp.deleteDependencies(ctx)
-				if err != nil {
-					return result, err
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(rdsInstanceParameterGroupFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrap(err, "rds: failed to update instance while removing finalizer")
-			}
-		}
-		// If object is being deleted and has no finalizer just exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
 	var parameterGroup *rds.DBParameterGroup
-	describeDBParameterGroupsOutput, err := comp.rdsAPI.DescribeDBParameterGroups(&rds.DescribeDBParameterGroupsInput{
+	describeDBParameterGroupsOutput, err := comp.rdsAPI.DescribeDBParameterGroupsWithContext(ctx.Context, &rds.DescribeDBParameterGroupsInput{
 		DBParameterGroupName: aws.String(instance.Name),
 	})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBParameterGroupNotFoundFault {
-			createDBParameterGroupOutput, err := comp.rdsAPI.CreateDBParameterGroup(&rds.CreateDBParameterGroupInput{
+			createDBParameterGroupOutput, err := comp.rdsAPI.CreateDBParameterGroupWithContext(ctx.Context, &rds.CreateDBParameterGroupInput{
 				DBParameterGroupName:   aws.String(instance.Name),
 				DBParameterGroupFamily: aws.String(fmt.Sprintf("%s%s", instance.Spec.Engine, instance.Spec.EngineVersion)),
 				Description:            aws.String("Created by ridecell-operator"),
@@ -125,7 +101,7 @@ func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContex
 	}
 
 	// handle tagging
-	listTagsForResourceOutput, err := comp.rdsAPI.ListTagsForResource(&rds.ListTagsForResourceInput{
+	listTagsForResourceOutput, err := comp.rdsAPI.ListTagsForResourceWithContext(ctx.Context, &rds.ListTagsForResourceInput{
 		ResourceName: parameterGroup.DBParameterGroupArn,
 	})
 	if err != nil {
@@ -151,7 +127,7 @@ func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContex
 		tagsToAdd = append(tagsToAdd, &rds.Tag{Key: aws.String("tentant"), Value: aws.String(instance.Name)})
 	}
 	if len(tagsToAdd) > 0 {
-		_, err = comp.rdsAPI.AddTagsToResource(&rds.AddTagsToResourceInput{
+		_, err = comp.rdsAPI.AddTagsToResourceWithContext(ctx.Context, &rds.AddTagsToResourceInput{
 			ResourceName: parameterGroup.DBParameterGroupArn,
 			Tags:         tagsToAdd,
 		})
@@ -162,7 +138,7 @@ func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContex
 
 	// Get default parameter group values
 	var defaultDBParams []*rds.Parameter
-	err = comp.rdsAPI.DescribeDBParametersPages(&rds.DescribeDBParametersInput{
+	err = comp.rdsAPI.DescribeDBParametersPagesWithContext(ctx.Context, &rds.DescribeDBParametersInput{
 		DBParameterGroupName: aws.String(fmt.Sprintf("default.%s%s", instance.Spec.Engine, instance.Spec.EngineVersion)),
 	}, func(page *rds.DescribeDBParametersOutput, lastPage bool) bool {
 		defaultDBParams = append(defaultDBParams, page.Parameters...)
@@ -175,7 +151,7 @@ func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContex
 
 	// Get current parameter group values
 	var dbParams []*rds.Parameter
-	err = comp.rdsAPI.DescribeDBParametersPages(&rds.DescribeDBParametersInput{
+	err = comp.rdsAPI.DescribeDBParametersPagesWithContext(ctx.Context, &rds.DescribeDBParametersInput{
 		DBParameterGroupName: aws.String(instance.Name),
 	}, func(page *rds.DescribeDBParametersOutput, lastPage bool) bool {
 		dbParams = append(dbParams, page.Parameters...)
@@ -222,7 +198,7 @@ func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContex
 	}
 
 	if len(updateParameters) > 0 {
-		_, err = comp.rdsAPI.ModifyDBParameterGroup(&rds.ModifyDBParameterGroupInput{
+		_, err = comp.rdsAPI.ModifyDBParameterGroupWithContext(ctx.Context, &rds.ModifyDBParameterGroupInput{
 			DBParameterGroupName: aws.String(instance.Name),
 			Parameters:           updateParameters,
 		})
@@ -237,7 +213,7 @@ func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContex
 	}
 
 	if len(resetParameters) > 0 {
-		_, err := comp.rdsAPI.ResetDBParameterGroup(&rds.ResetDBParameterGroupInput{
+		_, err := comp.rdsAPI.ResetDBParameterGroupWithContext(ctx.Context, &rds.ResetDBParameterGroupInput{
 			DBParameterGroupName: aws.String(instance.Name),
 			Parameters:           resetParameters,
 			ResetAllParameters:   aws.Bool(false),
@@ -257,7 +233,11 @@ func (comp *dbParameterGroupComponent) Reconcile(ctx *components.ComponentContex
 
 func (comp *dbParameterGroupComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSInstance)
-	describeDBParameterGroupsOutput, err := comp.rdsAPI.DescribeDBParameterGroups(&rds.DescribeDBParameterGroupsInput{
+	// If our database still exists we can't delete this yet
+	if helpers.ContainsFinalizer(RDSInstanceDatabaseFinalizer, instance) {
+		return components.Result{RequeueAfter: time.Minute * 1}, nil
+	}
+	describeDBParameterGroupsOutput, err := comp.rdsAPI.DescribeDBParameterGroupsWithContext(ctx.Context, &rds.DescribeDBParameterGroupsInput{
 		DBParameterGroupName: aws.String(instance.Name),
 	})
 	if err != nil {
@@ -267,7 +247,7 @@ func (comp *dbParameterGroupComponent) deleteDependencies(ctx *components.Compon
 		return components.Result{}, errors.Wrap(err, "rds: failed to describe parameter group for finalizer")
 	}
 
-	_, err = comp.rdsAPI.DeleteDBParameterGroup(&rds.DeleteDBParameterGroupInput{
+	_, err = comp.rdsAPI.DeleteDBParameterGroupWithContext(ctx.Context, &rds.DeleteDBParameterGroupInput{
 		DBParameterGroupName: describeDBParameterGroupsOutput.DBParameterGroups[0].DBParameterGroupName,
 	})
 	if err != nil {
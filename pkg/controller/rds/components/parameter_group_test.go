@@ -26,6 +26,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/pkg/errors"
@@ -161,7 +162,7 @@ var _ = Describe("rds parameter group Component", func() {
 })
 
 // Mock aws functions below
-func (m *mockRDSPGClient) DescribeDBParameterGroups(input *rds.DescribeDBParameterGroupsInput) (*rds.DescribeDBParameterGroupsOutput, error) {
+func (m *mockRDSPGClient) DescribeDBParameterGroupsWithContext(ctx aws.Context, input *rds.DescribeDBParameterGroupsInput, opts ...request.Option) (*rds.DescribeDBParameterGroupsOutput, error) {
 	if aws.StringValue(input.DBParameterGroupName) != instance.Name {
 		return nil, errors.New("mock_rds: input parameter group name did not match expected value")
 	}
@@ -178,7 +179,7 @@ func (m *mockRDSPGClient) DescribeDBParameterGroups(input *rds.DescribeDBParamet
 	return nil, awserr.New(rds.ErrCodeDBParameterGroupNotFoundFault, "", nil)
 }
 
-func (m *mockRDSPGClient) CreateDBParameterGroup(input *rds.CreateDBParameterGroupInput) (*rds.CreateDBParameterGroupOutput, error) {
+func (m *mockRDSPGClient) CreateDBParameterGroupWithContext(ctx aws.Context, input *rds.CreateDBParameterGroupInput, opts ...request.Option) (*rds.CreateDBParameterGroupOutput, error) {
 	if aws.StringValue(input.DBParameterGroupName) != instance.Name {
 		return nil, errors.New("mock_rds: input parameter group name did not match expected value")
 	}
@@ -192,7 +193,7 @@ func (m *mockRDSPGClient) CreateDBParameterGroup(input *rds.CreateDBParameterGro
 	}, nil
 }
 
-func (m *mockRDSPGClient) DescribeDBParametersPages(input *rds.DescribeDBParametersInput, fn func(*rds.DescribeDBParametersOutput, bool) bool) error {
+func (m *mockRDSPGClient) DescribeDBParametersPagesWithContext(ctx aws.Context, input *rds.DescribeDBParametersInput, fn func(*rds.DescribeDBParametersOutput, bool) bool, opts ...request.Option) error {
 	if aws.StringValue(input.DBParameterGroupName) == "default.postgres11" {
 		fn(&rds.DescribeDBParametersOutput{Parameters: m.defaultParameters}, false)
 		return nil
@@ -214,7 +215,7 @@ func (m *mockRDSPGClient) DescribeDBParametersPages(input *rds.DescribeDBParamet
 }
 
 // Why in the world does this single function differ from the rest of the sdk?????
-func (m *mockRDSPGClient) ModifyDBParameterGroup(input *rds.ModifyDBParameterGroupInput) (*rds.DBParameterGroupNameMessage, error) {
+func (m *mockRDSPGClient) ModifyDBParameterGroupWithContext(ctx aws.Context, input *rds.ModifyDBParameterGroupInput, opts ...request.Option) (*rds.DBParameterGroupNameMessage, error) {
 	if aws.StringValue(input.DBParameterGroupName) != instance.Name {
 		return nil, errors.New("mock_rds: input parameter group name did not match expected value")
 	}
@@ -232,7 +233,7 @@ func (m *mockRDSPGClient) ModifyDBParameterGroup(input *rds.ModifyDBParameterGro
 	return nil, nil
 }
 
-func (m *mockRDSPGClient) ResetDBParameterGroup(input *rds.ResetDBParameterGroupInput) (*rds.DBParameterGroupNameMessage, error) {
+func (m *mockRDSPGClient) ResetDBParameterGroupWithContext(ctx aws.Context, input *rds.ResetDBParameterGroupInput, opts ...request.Option) (*rds.DBParameterGroupNameMessage, error) {
 	if aws.StringValue(input.DBParameterGroupName) != instance.Name {
 		return nil, errors.New("mock_rds: input parameter group name did not match expected value")
 	}
@@ -262,7 +263,7 @@ func (m *mockRDSPGClient) ResetDBParameterGroup(input *rds.ResetDBParameterGroup
 	return &rds.DBParameterGroupNameMessage{}, nil
 }
 
-func (m *mockRDSPGClient) DeleteDBParameterGroup(input *rds.DeleteDBParameterGroupInput) (*rds.DeleteDBParameterGroupOutput, error) {
+func (m *mockRDSPGClient) DeleteDBParameterGroupWithContext(ctx aws.Context, input *rds.DeleteDBParameterGroupInput, opts ...request.Option) (*rds.DeleteDBParameterGroupOutput, error) {
 	if aws.StringValue(input.DBParameterGroupName) != instance.Name {
 		return nil, errors.New("mock_rds: input parameter group name did not match expected value")
 	}
@@ -270,7 +271,7 @@ func (m *mockRDSPGClient) DeleteDBParameterGroup(input *rds.DeleteDBParameterGro
 	return &rds.DeleteDBParameterGroupOutput{}, nil
 }
 
-func (m *mockRDSPGClient) ListTagsForResource(input *rds.ListTagsForResourceInput) (*rds.ListTagsForResourceOutput, error) {
+func (m *mockRDSPGClient) ListTagsForResourceWithContext(ctx aws.Context, input *rds.ListTagsForResourceInput, opts ...request.Option) (*rds.ListTagsForResourceOutput, error) {
 	if m.hasTags {
 		tags := []*rds.Tag{
 			&rds.Tag{
@@ -287,7 +288,7 @@ func (m *mockRDSPGClient) ListTagsForResource(input *rds.ListTagsForResourceInpu
 	return &rds.ListTagsForResourceOutput{}, nil
 }
 
-func (m *mockRDSPGClient) AddTagsToResource(input *rds.AddTagsToResourceInput) (*rds.AddTagsToResourceOutput, error) {
+func (m *mockRDSPGClient) AddTagsToResourceWithContext(ctx aws.Context, input *rds.AddTagsToResourceInput, opts ...request.Option) (*rds.AddTagsToResourceOutput, error) {
 	m.addedTags = true
 	return &rds.AddTagsToResourceOutput{}, nil
 }
@@ -18,7 +18,6 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -35,20 +34,22 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 	corev1 "k8s.io/api/core/v1"
 )
 
 const RDSInstanceDatabaseFinalizer = "rdsinstance.database.finalizer"
 
 type rdsInstanceComponent struct {
-	rdsAPI rdsiface.RDSAPI
+	rdsAPI    rdsiface.RDSAPI
+	finalizer *components.Finalizer
 }
 
 func NewRDSInstance() *rdsInstanceComponent {
 	sess := session.Must(session.NewSession())
 	rdsService := rds.New(sess)
-	return &rdsInstanceComponent{rdsAPI: rdsService}
+	comp := &rdsInstanceComponent{rdsAPI: rdsService}
+	comp.finalizer = components.NewFinalizer(RDSInstanceDatabaseFinalizer, comp.cleanup)
+	return comp
 }
 
 func (comp *rdsInstanceComponent) InjectRDSAPI(rdsapi rdsiface.RDSAPI) {
@@ -66,50 +67,14 @@ func (_ *rdsInstanceComponent) IsReconcilable(ctx *components.ComponentContext)
 func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSInstance)
 
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(RDSInstanceDatabaseFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(RDSInstanceDatabaseFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "rds: failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(RDSInstanceDatabaseFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				describeDBInstancesOutput, err := comp.rdsAPI.DescribeDBInstances(&rds.DescribeDBInstancesInput{
-					DBInstanceIdentifier: aws.String(instance.Spec.InstanceID),
-				})
-				if err != nil {
-					if aerr, ok := err.(awserr.Error); ok && aerr.Code() != rds.ErrCodeDBInstanceNotFoundFault {
-						return components.Result{}, errors.Wrapf(err, "rds: unable to describe db instance")
-					}
-				} else {
-					// If there was no error our instance exists
-					if aws.StringValue(describeDBInstancesOutput.DBInstances[0].DBInstanceStatus) == "deleting" {
-						return components.Result{RequeueAfter: time.Minute * 1}, nil
-					}
-					// if the instance is not currently being deleted, attempt a delete and exit accordingly.
-					result, err := comp.deleteDependencies(ctx)
-					if err != nil {
-						return result, err
-					}
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(RDSInstanceDatabaseFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "rds: failed to update instance while removing finalizer")
-			}
-		}
-		// If object is being deleted and has no finalizer just exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
 	// Get our password secret
 	fetchSecret := &corev1.Secret{}
-	err := ctx.Client.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s.rds-user-password", instance.Name), Namespace: instance.Namespace}, fetchSecret)
+	err = ctx.Client.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s.rds-user-password", instance.Name), Namespace: instance.Namespace}, fetchSecret)
 	if err != nil {
 		return components.Result{}, errors.Wrap(err, "rds: failed to get password secret")
 	}
@@ -127,7 +92,7 @@ func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (c
 		return components.Result{}, errors.New("rds: aws_subnet_group_name var not set")
 	}
 
-	describeDBInstancesOutput, err := comp.rdsAPI.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+	describeDBInstancesOutput, err := comp.rdsAPI.DescribeDBInstancesWithContext(ctx.Context, &rds.DescribeDBInstancesInput{
 		DBInstanceIdentifier: aws.String(instance.Spec.InstanceID),
 	})
 	if err != nil {
@@ -138,7 +103,7 @@ func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (c
 	}
 
 	if databaseNotExist {
-		createDBInstanceOutput, err := comp.rdsAPI.CreateDBInstance(&rds.CreateDBInstanceInput{
+		createDBInstanceOutput, err := comp.rdsAPI.CreateDBInstanceWithContext(ctx.Context, &rds.CreateDBInstanceInput{
 			MasterUsername:             aws.String(databaseUsername),
 			DBInstanceIdentifier:       aws.String(instance.Spec.InstanceID),
 			MasterUserPassword:         aws.String(string(password)),
@@ -175,7 +140,7 @@ func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (c
 	}
 
 	// Handle tagging
-	listTagsForResourceOutput, err := comp.rdsAPI.ListTagsForResource(&rds.ListTagsForResourceInput{
+	listTagsForResourceOutput, err := comp.rdsAPI.ListTagsForResourceWithContext(ctx.Context, &rds.ListTagsForResourceInput{
 		ResourceName: database.DBInstanceArn,
 	})
 	if err != nil {
@@ -200,7 +165,7 @@ func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (c
 		tagsToAdd = append(tagsToAdd, &rds.Tag{Key: aws.String("tentant"), Value: aws.String(instance.Name)})
 	}
 	if len(tagsToAdd) > 0 {
-		_, err = comp.rdsAPI.AddTagsToResource(&rds.AddTagsToResourceInput{
+		_, err = comp.rdsAPI.AddTagsToResourceWithContext(ctx.Context, &rds.AddTagsToResourceInput{
 			ResourceName: database.DBInstanceArn,
 			Tags:         tagsToAdd,
 		})
@@ -237,7 +202,7 @@ func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (c
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "rds: failed to open db connection")
 		}
-		databaseRows, err := db.Query(`SELECT 1;`)
+		databaseRows, err := db.QueryContext(ctx.Context, `SELECT 1;`)
 		if err != nil {
 			// If the error is invalid password update the database to reflect the expected password
 			// 28P01 == Invalid Password
@@ -255,7 +220,7 @@ func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (c
 	dbStatus := aws.StringValue(database.DBInstanceStatus)
 	// Only try to update the database if the status is available, otherwise a change may already be in progress.
 	if (dbStatus == "available" || dbStatus == "pending-reboot") && needsUpdate {
-		err = comp.modifyRDSInstance(databaseModifyInput)
+		err = comp.modifyRDSInstance(ctx, databaseModifyInput)
 		if err != nil {
 			return components.Result{}, errors.Wrap(err, "rds: failed to modify db instance")
 		}
@@ -308,18 +273,38 @@ func (comp *rdsInstanceComponent) Reconcile(ctx *components.ComponentContext) (c
 	}, RequeueAfter: time.Second * 30}, nil
 }
 
-func (comp *rdsInstanceComponent) modifyRDSInstance(modifyInput *rds.ModifyDBInstanceInput) error {
-	_, err := comp.rdsAPI.ModifyDBInstance(modifyInput)
+func (comp *rdsInstanceComponent) modifyRDSInstance(ctx *components.ComponentContext, modifyInput *rds.ModifyDBInstanceInput) error {
+	_, err := comp.rdsAPI.ModifyDBInstanceWithContext(ctx.Context, modifyInput)
 	if err != nil {
 		return errors.Wrap(err, "rds: failed to update rds instance")
 	}
 	return nil
 }
 
+// Finalizer cleanup, start deleting the database unless that's already in progress.
+func (comp *rdsInstanceComponent) cleanup(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*dbv1beta1.RDSInstance)
+	describeDBInstancesOutput, err := comp.rdsAPI.DescribeDBInstancesWithContext(ctx.Context, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(instance.Spec.InstanceID),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() != rds.ErrCodeDBInstanceNotFoundFault {
+			return components.Result{}, errors.Wrapf(err, "rds: unable to describe db instance")
+		}
+		return components.Result{}, nil
+	}
+	// If there was no error our instance exists
+	if aws.StringValue(describeDBInstancesOutput.DBInstances[0].DBInstanceStatus) == "deleting" {
+		return components.Result{RequeueAfter: time.Minute * 1}, nil
+	}
+	// if the instance is not currently being deleted, attempt a delete and exit accordingly.
+	return comp.deleteDependencies(ctx)
+}
+
 func (comp *rdsInstanceComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSInstance)
 
-	_, err := comp.rdsAPI.DeleteDBInstance(&rds.DeleteDBInstanceInput{
+	_, err := comp.rdsAPI.DeleteDBInstanceWithContext(ctx.Context, &rds.DeleteDBInstanceInput{
 		DBInstanceIdentifier:      aws.String(instance.Spec.InstanceID),
 		FinalDBSnapshotIdentifier: aws.String(fmt.Sprintf("final-%s-%s", instance.Spec.InstanceID, time.Now().UTC().Format("2006-01-02-15-04"))),
 	})
@@ -30,6 +30,7 @@ import (
 	"github.com/Ridecell/ridecell-operator/pkg/dbpool"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/lib/pq"
@@ -240,7 +241,7 @@ var _ = Describe("rds aws Component", func() {
 
 // Mock aws functions below
 
-func (m *mockRDSDBClient) DescribeDBInstances(input *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+func (m *mockRDSDBClient) DescribeDBInstancesWithContext(ctx aws.Context, input *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
 	if m.dbInstanceExists {
 		dbInstances := []*rds.DBInstance{
 			&rds.DBInstance{
@@ -260,7 +261,7 @@ func (m *mockRDSDBClient) DescribeDBInstances(input *rds.DescribeDBInstancesInpu
 	return nil, awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "", nil)
 }
 
-func (m *mockRDSDBClient) CreateDBInstance(input *rds.CreateDBInstanceInput) (*rds.CreateDBInstanceOutput, error) {
+func (m *mockRDSDBClient) CreateDBInstanceWithContext(ctx aws.Context, input *rds.CreateDBInstanceInput, opts ...request.Option) (*rds.CreateDBInstanceOutput, error) {
 	dbInstance := &rds.DBInstance{
 		Endpoint: &rds.Endpoint{
 			Address: aws.String("endpoint.test"),
@@ -275,7 +276,7 @@ func (m *mockRDSDBClient) CreateDBInstance(input *rds.CreateDBInstanceInput) (*r
 	return &rds.CreateDBInstanceOutput{DBInstance: dbInstance}, nil
 }
 
-func (m *mockRDSDBClient) ModifyDBInstance(input *rds.ModifyDBInstanceInput) (*rds.ModifyDBInstanceOutput, error) {
+func (m *mockRDSDBClient) ModifyDBInstanceWithContext(ctx aws.Context, input *rds.ModifyDBInstanceInput, opts ...request.Option) (*rds.ModifyDBInstanceOutput, error) {
 	if input.MasterUserPassword != nil && aws.StringValue(input.MasterUserPassword) != string(passwordSecret.Data["password"]) {
 		return nil, errors.New("mock_rds: received incorrect password in modify")
 	}
@@ -283,7 +284,7 @@ func (m *mockRDSDBClient) ModifyDBInstance(input *rds.ModifyDBInstanceInput) (*r
 	return &rds.ModifyDBInstanceOutput{}, nil
 }
 
-func (m *mockRDSDBClient) DeleteDBInstance(input *rds.DeleteDBInstanceInput) (*rds.DeleteDBInstanceOutput, error) {
+func (m *mockRDSDBClient) DeleteDBInstanceWithContext(ctx aws.Context, input *rds.DeleteDBInstanceInput, opts ...request.Option) (*rds.DeleteDBInstanceOutput, error) {
 	if aws.StringValue(input.DBInstanceIdentifier) != instance.Name {
 		return nil, errors.New("mock_rds: instance identifier did not match expected value")
 	}
@@ -291,7 +292,7 @@ func (m *mockRDSDBClient) DeleteDBInstance(input *rds.DeleteDBInstanceInput) (*r
 	return &rds.DeleteDBInstanceOutput{}, nil
 }
 
-func (m *mockRDSDBClient) ListTagsForResource(input *rds.ListTagsForResourceInput) (*rds.ListTagsForResourceOutput, error) {
+func (m *mockRDSDBClient) ListTagsForResourceWithContext(ctx aws.Context, input *rds.ListTagsForResourceInput, opts ...request.Option) (*rds.ListTagsForResourceOutput, error) {
 	if m.hasTags {
 		tags := []*rds.Tag{
 			&rds.Tag{
@@ -308,7 +309,7 @@ func (m *mockRDSDBClient) ListTagsForResource(input *rds.ListTagsForResourceInpu
 	return &rds.ListTagsForResourceOutput{}, nil
 }
 
-func (m *mockRDSDBClient) AddTagsToResource(input *rds.AddTagsToResourceInput) (*rds.AddTagsToResourceOutput, error) {
+func (m *mockRDSDBClient) AddTagsToResourceWithContext(ctx aws.Context, input *rds.AddTagsToResourceInput, opts ...request.Option) (*rds.AddTagsToResourceOutput, error) {
 	m.addedTags = true
 	return &rds.AddTagsToResourceOutput{}, nil
 }
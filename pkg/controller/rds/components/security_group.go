@@ -18,7 +18,6 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
@@ -38,18 +37,21 @@ import (
 const rdsInstanceSecurityGroupFinalizer = "rdsinstance.securitygroup.finalizer"
 
 type dbSecurityGroupComponent struct {
-	ec2API ec2iface.EC2API
-	rdsAPI rdsiface.RDSAPI
+	ec2API    ec2iface.EC2API
+	rdsAPI    rdsiface.RDSAPI
+	finalizer *components.Finalizer
 }
 
 func NewDBSecurityGroup() *dbSecurityGroupComponent {
 	sess := session.Must(session.NewSession())
 	ec2Service := ec2.New(sess)
 	rdsService := rds.New(sess)
-	return &dbSecurityGroupComponent{
+	comp := &dbSecurityGroupComponent{
 		ec2API: ec2Service,
 		rdsAPI: rdsService,
 	}
+	comp.finalizer = components.NewFinalizer(rdsInstanceSecurityGroupFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *dbSecurityGroupComponent) InjectAWSAPIs(ec2api ec2iface.EC2API, rdsapi rdsiface.RDSAPI) {
@@ -70,38 +72,12 @@ func (comp *dbSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 
 	securityGroupName := fmt.Sprintf("ridecell-operator-rds-%s", instance.Name)
 
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !helpers.ContainsFinalizer(rdsInstanceSecurityGroupFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(rdsInstanceSecurityGroupFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "rds: failed to update instance while adding finalizer")
-			}
-		}
-	} else {
-		if helpers.ContainsFinalizer(rdsInstanceSecurityGroupFinalizer, instance) {
-			// If our database still exists we can't delete the security group
-			if helpers.ContainsFinalizer(RDSInstanceDatabaseFinalizer, instance) {
-				return components.Result{RequeueAfter: time.Minute * 1}, nil
-			}
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				result, err := comp.deleteDependencies(ctx)
-				if err != nil {
-					return result, err
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(rdsInstanceSecurityGroupFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance.DeepCopy())
-			if err != nil {
-				return components.Result{}, errors.Wrapf(err, "rds: failed to update instance while removing finalizer")
-			}
-		}
-		// If object is being deleted and has no finalizer exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
-	describeSecurityGroupsOutput, err := comp.ec2API.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+	describeSecurityGroupsOutput, err := comp.ec2API.DescribeSecurityGroupsWithContext(ctx.Context, &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
 			&ec2.Filter{
 				Name:   aws.String("group-name"),
@@ -118,7 +94,7 @@ func (comp *dbSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 		if err != nil {
 			return components.Result{}, err
 		}
-		_, err = comp.ec2API.CreateSecurityGroup(&ec2.CreateSecurityGroupInput{
+		_, err = comp.ec2API.CreateSecurityGroupWithContext(ctx.Context, &ec2.CreateSecurityGroupInput{
 			GroupName:   aws.String(securityGroupName),
 			Description: aws.String(fmt.Sprintf("%s: Created by ridecell-operator", securityGroupName)),
 			VpcId:       vpcID,
@@ -144,7 +120,7 @@ func (comp *dbSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 	}
 
 	if !hasIngressRule {
-		_, err := comp.ec2API.AuthorizeSecurityGroupIngress(&ec2.AuthorizeSecurityGroupIngressInput{
+		_, err := comp.ec2API.AuthorizeSecurityGroupIngressWithContext(ctx.Context, &ec2.AuthorizeSecurityGroupIngressInput{
 			CidrIp:     aws.String("0.0.0.0/0"),
 			FromPort:   aws.Int64(int64(5432)),
 			ToPort:     aws.Int64(int64(5432)),
@@ -169,7 +145,7 @@ func (comp *dbSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 	}
 
 	if !foundOperatorTag || !foundTenantTag {
-		_, err := comp.ec2API.CreateTags(&ec2.CreateTagsInput{
+		_, err := comp.ec2API.CreateTagsWithContext(ctx.Context, &ec2.CreateTagsInput{
 			Resources: []*string{securityGroup.GroupId},
 			Tags: []*ec2.Tag{
 				&ec2.Tag{
@@ -196,7 +172,11 @@ func (comp *dbSecurityGroupComponent) Reconcile(ctx *components.ComponentContext
 
 func (comp *dbSecurityGroupComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSInstance)
-	describeSecurityGroupsOutput, _ := comp.ec2API.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+	// If our database still exists we can't delete this yet
+	if helpers.ContainsFinalizer(RDSInstanceDatabaseFinalizer, instance) {
+		return components.Result{RequeueAfter: time.Minute * 1}, nil
+	}
+	describeSecurityGroupsOutput, _ := comp.ec2API.DescribeSecurityGroupsWithContext(ctx.Context, &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
 			&ec2.Filter{
 				Name:   aws.String("group-name"),
@@ -211,7 +191,7 @@ func (comp *dbSecurityGroupComponent) deleteDependencies(ctx *components.Compone
 		return components.Result{}, nil
 	}
 
-	_, err := comp.ec2API.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{
+	_, err := comp.ec2API.DeleteSecurityGroupWithContext(ctx.Context, &ec2.DeleteSecurityGroupInput{
 		GroupId: describeSecurityGroupsOutput.SecurityGroups[0].GroupId,
 	})
 	if err != nil {
@@ -223,7 +203,7 @@ func (comp *dbSecurityGroupComponent) deleteDependencies(ctx *components.Compone
 
 func (comp *dbSecurityGroupComponent) getVPCID(ctx *components.ComponentContext) (*string, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSInstance)
-	describeDBSubnetGroups, err := comp.rdsAPI.DescribeDBSubnetGroups(&rds.DescribeDBSubnetGroupsInput{
+	describeDBSubnetGroups, err := comp.rdsAPI.DescribeDBSubnetGroupsWithContext(ctx.Context, &rds.DescribeDBSubnetGroupsInput{
 		DBSubnetGroupName: aws.String(instance.Spec.SubnetGroupName),
 	})
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/rds"
@@ -141,7 +142,7 @@ var _ = Describe("rds security group Component", func() {
 })
 
 // Mock aws functions below
-func (m *mockEC2SGClient) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+func (m *mockEC2SGClient) DescribeSecurityGroupsWithContext(ctx aws.Context, input *ec2.DescribeSecurityGroupsInput, opts ...request.Option) (*ec2.DescribeSecurityGroupsOutput, error) {
 	if aws.StringValue(input.Filters[0].Values[0]) != "ridecell-operator-rds-test" {
 		return nil, errors.New("mock_ec2: input security group name did not match expected value")
 	}
@@ -177,7 +178,7 @@ func (m *mockEC2SGClient) DescribeSecurityGroups(input *ec2.DescribeSecurityGrou
 	return &ec2.DescribeSecurityGroupsOutput{}, nil
 }
 
-func (m *mockEC2SGClient) CreateSecurityGroup(input *ec2.CreateSecurityGroupInput) (*ec2.CreateSecurityGroupOutput, error) {
+func (m *mockEC2SGClient) CreateSecurityGroupWithContext(ctx aws.Context, input *ec2.CreateSecurityGroupInput, opts ...request.Option) (*ec2.CreateSecurityGroupOutput, error) {
 	if aws.StringValue(input.GroupName) != "ridecell-operator-rds-test" {
 		return nil, errors.New("mock_ec2: input security group name did not match expected value")
 	}
@@ -188,7 +189,7 @@ func (m *mockEC2SGClient) CreateSecurityGroup(input *ec2.CreateSecurityGroupInpu
 	return nil, nil
 }
 
-func (m *mockEC2SGClient) AuthorizeSecurityGroupIngress(input *ec2.AuthorizeSecurityGroupIngressInput) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+func (m *mockEC2SGClient) AuthorizeSecurityGroupIngressWithContext(ctx aws.Context, input *ec2.AuthorizeSecurityGroupIngressInput, opts ...request.Option) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
 	if aws.StringValue(input.GroupId) != "abcdf-1293238923" {
 		return nil, errors.New("mock_ec2: input security group id did not match expected value")
 	}
@@ -196,7 +197,7 @@ func (m *mockEC2SGClient) AuthorizeSecurityGroupIngress(input *ec2.AuthorizeSecu
 	return nil, nil
 }
 
-func (m *mockEC2SGClient) CreateTags(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+func (m *mockEC2SGClient) CreateTagsWithContext(ctx aws.Context, input *ec2.CreateTagsInput, opts ...request.Option) (*ec2.CreateTagsOutput, error) {
 	if aws.StringValue(input.Resources[0]) != "abcdf-1293238923" {
 		return nil, errors.New("mock_ec2: resource id did not match expected value")
 	}
@@ -204,12 +205,12 @@ func (m *mockEC2SGClient) CreateTags(input *ec2.CreateTagsInput) (*ec2.CreateTag
 	return nil, nil
 }
 
-func (m *mockEC2SGClient) DeleteSecurityGroup(input *ec2.DeleteSecurityGroupInput) (*ec2.DeleteSecurityGroupOutput, error) {
+func (m *mockEC2SGClient) DeleteSecurityGroupWithContext(ctx aws.Context, input *ec2.DeleteSecurityGroupInput, opts ...request.Option) (*ec2.DeleteSecurityGroupOutput, error) {
 	m.deletedSecurityGroup = true
 	return &ec2.DeleteSecurityGroupOutput{}, nil
 }
 
-func (m *mockRDSSGClient) DescribeDBSubnetGroups(input *rds.DescribeDBSubnetGroupsInput) (*rds.DescribeDBSubnetGroupsOutput, error) {
+func (m *mockRDSSGClient) DescribeDBSubnetGroupsWithContext(ctx aws.Context, input *rds.DescribeDBSubnetGroupsInput, opts ...request.Option) (*rds.DescribeDBSubnetGroupsOutput, error) {
 	return &rds.DescribeDBSubnetGroupsOutput{
 		DBSubnetGroups: []*rds.DBSubnetGroup{
 			&rds.DBSubnetGroup{VpcId: aws.String("test")},
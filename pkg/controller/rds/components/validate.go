@@ -0,0 +1,44 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+)
+
+// Same format as the pattern on the CRD, ddd:hh24:mi-ddd:hh24:mi.
+var maintenanceWindowRe = regexp.MustCompile(`^\D*:\d{2}:\d{2}-\D*:\d{2}:\d{2}$`)
+
+// Check an RDSInstance spec for values RDS will never accept. Shared with the admission webhook.
+func Validate(instance *dbv1beta1.RDSInstance) error {
+	errs := []error{}
+	if instance.Spec.AllocatedStorage < 0 {
+		errs = append(errs, errors.Errorf("allocatedStorage cannot be negative: %v", instance.Spec.AllocatedStorage))
+	}
+	if instance.Spec.Engine != "" && instance.Spec.Engine != "postgres" {
+		errs = append(errs, errors.Errorf("unsupported engine %s, only postgres is supported", instance.Spec.Engine))
+	}
+	if instance.Spec.MaintenanceWindow != "" && !maintenanceWindowRe.MatchString(instance.Spec.MaintenanceWindow) {
+		errs = append(errs, errors.Errorf("invalid maintenanceWindow %s, must be in the format ddd:hh24:mi-ddd:hh24:mi", instance.Spec.MaintenanceWindow))
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -127,7 +127,7 @@ func (comp *RDSSnapshotComponent) Reconcile(ctx *components.ComponentContext) (c
 	}
 
 	var dbSnapshot *rds.DBSnapshot
-	describeDBSnapshotsOutput, err := comp.rdsAPI.DescribeDBSnapshots(&rds.DescribeDBSnapshotsInput{
+	describeDBSnapshotsOutput, err := comp.rdsAPI.DescribeDBSnapshotsWithContext(ctx.Context, &rds.DescribeDBSnapshotsInput{
 		DBSnapshotIdentifier: aws.String(instance.Spec.SnapshotID),
 	})
 	if err != nil {
@@ -135,7 +135,7 @@ func (comp *RDSSnapshotComponent) Reconcile(ctx *components.ComponentContext) (c
 			return components.Result{}, errors.Wrap(err, "rds_snapshot: failed to describe snapshot")
 		}
 		// if our snapshot doesn't exist create it
-		createDBSnapshotOutput, err := comp.rdsAPI.CreateDBSnapshot(&rds.CreateDBSnapshotInput{
+		createDBSnapshotOutput, err := comp.rdsAPI.CreateDBSnapshotWithContext(ctx.Context, &rds.CreateDBSnapshotInput{
 			DBInstanceIdentifier: aws.String(instance.Spec.RDSInstanceID),
 			DBSnapshotIdentifier: aws.String(instance.Spec.SnapshotID),
 			Tags:                 snapshotTags,
@@ -177,7 +177,7 @@ func (comp *RDSSnapshotComponent) Reconcile(ctx *components.ComponentContext) (c
 func (comp *RDSSnapshotComponent) deleteDependencies(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*dbv1beta1.RDSSnapshot)
 
-	_, err := comp.rdsAPI.DeleteDBSnapshot(&rds.DeleteDBSnapshotInput{DBSnapshotIdentifier: aws.String(instance.Spec.SnapshotID)})
+	_, err := comp.rdsAPI.DeleteDBSnapshotWithContext(ctx.Context, &rds.DeleteDBSnapshotInput{DBSnapshotIdentifier: aws.String(instance.Spec.SnapshotID)})
 	if err != nil {
 		// if the snapshot isn't found don't consider it an error
 		if aerr, ok := err.(awserr.Error); ok && aerr.Code() != rds.ErrCodeDBSnapshotNotFoundFault {
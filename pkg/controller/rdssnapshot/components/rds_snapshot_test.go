@@ -28,6 +28,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/pkg/errors"
@@ -113,7 +114,7 @@ var _ = Describe("rdssnapshot db Component", func() {
 
 // Mock aws functions below
 
-func (m *mockRDSDBClient) DescribeDBSnapshots(input *rds.DescribeDBSnapshotsInput) (*rds.DescribeDBSnapshotsOutput, error) {
+func (m *mockRDSDBClient) DescribeDBSnapshotsWithContext(ctx aws.Context, input *rds.DescribeDBSnapshotsInput, opts ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
 	if m.snapshotExists {
 		return &rds.DescribeDBSnapshotsOutput{
 			DBSnapshots: []*rds.DBSnapshot{
@@ -128,7 +129,7 @@ func (m *mockRDSDBClient) DescribeDBSnapshots(input *rds.DescribeDBSnapshotsInpu
 	return &rds.DescribeDBSnapshotsOutput{}, awserr.New(rds.ErrCodeDBSnapshotNotFoundFault, "", nil)
 }
 
-func (m *mockRDSDBClient) CreateDBSnapshot(input *rds.CreateDBSnapshotInput) (*rds.CreateDBSnapshotOutput, error) {
+func (m *mockRDSDBClient) CreateDBSnapshotWithContext(ctx aws.Context, input *rds.CreateDBSnapshotInput, opts ...request.Option) (*rds.CreateDBSnapshotOutput, error) {
 	match := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*[a-zA-Z0-9]$`).MatchString(aws.StringValue(input.DBSnapshotIdentifier))
 	if strings.Contains("--", aws.StringValue(input.DBSnapshotIdentifier)) || !match {
 		return &rds.CreateDBSnapshotOutput{}, errors.Errorf("mock_rds_snapshot: input snapshot id (%s) did not match regex", aws.StringValue(input.DBSnapshotIdentifier))
@@ -148,7 +149,7 @@ func (m *mockRDSDBClient) CreateDBSnapshot(input *rds.CreateDBSnapshotInput) (*r
 	}, nil
 }
 
-func (m *mockRDSDBClient) DeleteDBSnapshot(input *rds.DeleteDBSnapshotInput) (*rds.DeleteDBSnapshotOutput, error) {
+func (m *mockRDSDBClient) DeleteDBSnapshotWithContext(ctx aws.Context, input *rds.DeleteDBSnapshotInput, opts ...request.Option) (*rds.DeleteDBSnapshotOutput, error) {
 	m.snapshotDeleted = true
 	if m.snapshotExists {
 		return &rds.DeleteDBSnapshotOutput{}, nil
@@ -18,7 +18,6 @@ package components
 
 import (
 	"encoding/json"
-	"os"
 	"reflect"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
@@ -31,7 +30,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	awsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/aws/v1beta1"
-	helpers "github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
 )
 
 const s3BucketFinalizer = "s3bucket.finalizer"
@@ -42,6 +40,7 @@ type s3BucketComponent struct {
 	// Keep an S3API per region.
 	s3Services map[string]s3iface.S3API
 	s3Factory  S3Factory
+	finalizer  *components.Finalizer
 }
 
 func realS3Factory(region string) (s3iface.S3API, error) {
@@ -55,10 +54,12 @@ func realS3Factory(region string) (s3iface.S3API, error) {
 }
 
 func NewS3Bucket() *s3BucketComponent {
-	return &s3BucketComponent{
+	comp := &s3BucketComponent{
 		s3Services: map[string]s3iface.S3API{},
 		s3Factory:  realS3Factory,
 	}
+	comp.finalizer = components.NewFinalizer(s3BucketFinalizer, comp.deleteDependencies)
+	return comp
 }
 
 func (comp *s3BucketComponent) InjectS3Factory(factory S3Factory) {
@@ -76,35 +77,9 @@ func (_ *s3BucketComponent) IsReconcilable(_ *components.ComponentContext) bool
 func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*awsv1beta1.S3Bucket)
 
-	// if object is not being deleted
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		// Is our finalizer attached to the object?
-		if !helpers.ContainsFinalizer(s3BucketFinalizer, instance) {
-			instance.ObjectMeta.Finalizers = helpers.AppendFinalizer(s3BucketFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{Requeue: true}, errors.Wrapf(err, "s3bucket: failed to update instance while adding finalizer")
-			}
-			return components.Result{Requeue: true}, nil
-		}
-	} else {
-		if helpers.ContainsFinalizer(s3BucketFinalizer, instance) {
-			if flag := instance.Annotations["ridecell.io/skip-finalizer"]; flag != "true" && os.Getenv("ENABLE_FINALIZERS") == "true" {
-				result, err := comp.deleteDependencies(ctx)
-				if err != nil {
-					return result, err
-				}
-			}
-			// All operations complete, remove finalizer
-			instance.ObjectMeta.Finalizers = helpers.RemoveFinalizer(s3BucketFinalizer, instance)
-			err := ctx.Update(ctx.Context, instance)
-			if err != nil {
-				return components.Result{Requeue: true}, errors.Wrapf(err, "s3bucket: failed to update instance while removing finalizer")
-			}
-			return components.Result{}, nil
-		}
-		// If object is being deleted and has no finalizer just exit.
-		return components.Result{}, nil
+	res, deleting, err := comp.finalizer.Handle(ctx)
+	if deleting || err != nil {
+		return res, err
 	}
 
 	// Get an S3 API to work with. This has to match the bucket region.
@@ -115,7 +90,7 @@ func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (comp
 
 	// Run a ListBucket call to check if this bucket exists.
 	bucketExists := true
-	_, err = s3Service.ListObjects(&s3.ListObjectsInput{
+	_, err = s3Service.ListObjectsWithContext(ctx.Context, &s3.ListObjectsInput{
 		Bucket:  aws.String(instance.Spec.BucketName),
 		MaxKeys: aws.Int64(1), // We don't actually care about the keys, so set this down for perf.
 	})
@@ -129,7 +104,7 @@ func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (comp
 
 	// If the bucket does not exist create it
 	if !bucketExists {
-		_, err = s3Service.CreateBucket(&s3.CreateBucketInput{
+		_, err = s3Service.CreateBucketWithContext(ctx.Context, &s3.CreateBucketInput{
 			Bucket: aws.String(instance.Spec.BucketName),
 			CreateBucketConfiguration: &s3.CreateBucketConfiguration{
 				LocationConstraint: aws.String(instance.Spec.Region),
@@ -141,7 +116,7 @@ func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 
 	// Look for ridecell-operator tag, if it doesn't exist create it
-	getBucketTags, err := s3Service.GetBucketTagging(&s3.GetBucketTaggingInput{Bucket: aws.String(instance.Spec.BucketName)})
+	getBucketTags, err := s3Service.GetBucketTaggingWithContext(ctx.Context, &s3.GetBucketTaggingInput{Bucket: aws.String(instance.Spec.BucketName)})
 	if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "NoSuchTagSet" {
 		// There is no tag set associated with the bucket.
 		getBucketTags = &s3.GetBucketTaggingOutput{TagSet: []*s3.Tag{}}
@@ -156,7 +131,7 @@ func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (comp
 		}
 	}
 	if !foundTag {
-		_, err := s3Service.PutBucketTagging(&s3.PutBucketTaggingInput{
+		_, err := s3Service.PutBucketTaggingWithContext(ctx.Context, &s3.PutBucketTaggingInput{
 			Bucket: aws.String(instance.Spec.BucketName),
 			Tagging: &s3.Tagging{
 				TagSet: []*s3.Tag{
@@ -174,7 +149,7 @@ func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (comp
 
 	// Try to grab the existing bucket policy.
 	bucketHasPolicy := true
-	getBucketPolicyObj, err := s3Service.GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: aws.String(instance.Spec.BucketName)})
+	getBucketPolicyObj, err := s3Service.GetBucketPolicyWithContext(ctx.Context, &s3.GetBucketPolicyInput{Bucket: aws.String(instance.Spec.BucketName)})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchBucketPolicy" {
 			bucketHasPolicy = false
@@ -186,7 +161,7 @@ func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (comp
 	// If the policy is "", we need to delete if set. Otherwise we need to check for == and then put.
 	if instance.Spec.BucketPolicy == "" {
 		if bucketHasPolicy {
-			_, err := s3Service.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{
+			_, err := s3Service.DeleteBucketPolicyWithContext(ctx.Context, &s3.DeleteBucketPolicyInput{
 				Bucket: aws.String(instance.Spec.BucketName),
 			})
 			if err != nil {
@@ -215,7 +190,7 @@ func (comp *s3BucketComponent) Reconcile(ctx *components.ComponentContext) (comp
 
 		// Update or create the bucket policy.
 		if bucketPolicyNeedsUpdate {
-			_, err := s3Service.PutBucketPolicy(&s3.PutBucketPolicyInput{
+			_, err := s3Service.PutBucketPolicyWithContext(ctx.Context, &s3.PutBucketPolicyInput{
 				Bucket: aws.String(instance.Spec.BucketName),
 				Policy: aws.String(instance.Spec.BucketPolicy),
 			})
@@ -257,7 +232,7 @@ func (comp *s3BucketComponent) deleteDependencies(ctx *components.ComponentConte
 
 	// All objects in the bucket must be deleted prior to bucket deletion
 	listObjectsOutput := []*s3.Object{}
-	err = s3Service.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+	err = s3Service.ListObjectsV2PagesWithContext(ctx.Context, &s3.ListObjectsV2Input{
 		Bucket: aws.String(instance.Spec.BucketName),
 	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
 		listObjectsOutput = append(listObjectsOutput, page.Contents...)
@@ -271,7 +246,7 @@ func (comp *s3BucketComponent) deleteDependencies(ctx *components.ComponentConte
 	}
 
 	for _, s3Object := range listObjectsOutput {
-		_, err := s3Service.DeleteObject(&s3.DeleteObjectInput{
+		_, err := s3Service.DeleteObjectWithContext(ctx.Context, &s3.DeleteObjectInput{
 			Bucket: aws.String(instance.Spec.BucketName),
 			Key:    s3Object.Key,
 		})
@@ -280,7 +255,7 @@ func (comp *s3BucketComponent) deleteDependencies(ctx *components.ComponentConte
 		}
 	}
 
-	_, err = s3Service.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(instance.Spec.BucketName)})
+	_, err = s3Service.DeleteBucketWithContext(ctx.Context, &s3.DeleteBucketInput{Bucket: aws.String(instance.Spec.BucketName)})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != s3.ErrCodeNoSuchBucket {
 			return components.Result{}, errors.Wrapf(aerr, "s3bucket: failed to delete bucket for finalizer")
@@ -26,6 +26,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/pkg/errors"
@@ -196,7 +197,7 @@ var _ = Describe("s3bucket aws Component", func() {
 
 // Mock aws functions below
 
-func (m *mockS3Client) ListObjects(input *s3.ListObjectsInput) (*s3.ListObjectsOutput, error) {
+func (m *mockS3Client) ListObjectsWithContext(ctx aws.Context, input *s3.ListObjectsInput, opts ...request.Option) (*s3.ListObjectsOutput, error) {
 	if m.mockBucketExists {
 		return &s3.ListObjectsOutput{}, nil
 	} else {
@@ -204,7 +205,7 @@ func (m *mockS3Client) ListObjects(input *s3.ListObjectsInput) (*s3.ListObjectsO
 	}
 }
 
-func (m *mockS3Client) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+func (m *mockS3Client) ListObjectsV2PagesWithContext(ctx aws.Context, input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool, opts ...request.Option) error {
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName {
 		return awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
 	}
@@ -212,7 +213,7 @@ func (m *mockS3Client) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(
 	return nil
 }
 
-func (m *mockS3Client) CreateBucket(input *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+func (m *mockS3Client) CreateBucketWithContext(ctx aws.Context, input *s3.CreateBucketInput, opts ...request.Option) (*s3.CreateBucketOutput, error) {
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName {
 		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
 	}
@@ -225,7 +226,7 @@ func (m *mockS3Client) CreateBucket(input *s3.CreateBucketInput) (*s3.CreateBuck
 	return &s3.CreateBucketOutput{}, nil
 }
 
-func (m *mockS3Client) GetBucketPolicy(input *s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error) {
+func (m *mockS3Client) GetBucketPolicyWithContext(ctx aws.Context, input *s3.GetBucketPolicyInput, opts ...request.Option) (*s3.GetBucketPolicyOutput, error) {
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName {
 		return &s3.GetBucketPolicyOutput{}, errors.New("awsmock_getbucketpolicy: bucketname was incorrect")
 	}
@@ -235,7 +236,7 @@ func (m *mockS3Client) GetBucketPolicy(input *s3.GetBucketPolicyInput) (*s3.GetB
 	return &s3.GetBucketPolicyOutput{Policy: m.mockBucketPolicy}, nil
 }
 
-func (m *mockS3Client) PutBucketPolicy(input *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error) {
+func (m *mockS3Client) PutBucketPolicyWithContext(ctx aws.Context, input *s3.PutBucketPolicyInput, opts ...request.Option) (*s3.PutBucketPolicyOutput, error) {
 	// Check bucket name.
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName {
 		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
@@ -251,7 +252,7 @@ func (m *mockS3Client) PutBucketPolicy(input *s3.PutBucketPolicyInput) (*s3.PutB
 	return &s3.PutBucketPolicyOutput{}, nil
 }
 
-func (m *mockS3Client) DeleteBucketPolicy(input *s3.DeleteBucketPolicyInput) (*s3.DeleteBucketPolicyOutput, error) {
+func (m *mockS3Client) DeleteBucketPolicyWithContext(ctx aws.Context, input *s3.DeleteBucketPolicyInput, opts ...request.Option) (*s3.DeleteBucketPolicyOutput, error) {
 	// Check bucket name.
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName {
 		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
@@ -260,7 +261,7 @@ func (m *mockS3Client) DeleteBucketPolicy(input *s3.DeleteBucketPolicyInput) (*s
 	return &s3.DeleteBucketPolicyOutput{}, nil
 }
 
-func (m *mockS3Client) GetBucketTagging(input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+func (m *mockS3Client) GetBucketTaggingWithContext(ctx aws.Context, input *s3.GetBucketTaggingInput, opts ...request.Option) (*s3.GetBucketTaggingOutput, error) {
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName {
 		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
 	}
@@ -277,7 +278,7 @@ func (m *mockS3Client) GetBucketTagging(input *s3.GetBucketTaggingInput) (*s3.Ge
 	return &s3.GetBucketTaggingOutput{}, awserr.New("NoSuchTagSet", "", nil)
 }
 
-func (m *mockS3Client) PutBucketTagging(input *s3.PutBucketTaggingInput) (*s3.PutBucketTaggingOutput, error) {
+func (m *mockS3Client) PutBucketTaggingWithContext(ctx aws.Context, input *s3.PutBucketTaggingInput, opts ...request.Option) (*s3.PutBucketTaggingOutput, error) {
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName {
 		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
 	}
@@ -285,7 +286,7 @@ func (m *mockS3Client) PutBucketTagging(input *s3.PutBucketTaggingInput) (*s3.Pu
 	return &s3.PutBucketTaggingOutput{}, nil
 }
 
-func (m *mockS3Client) DeleteBucket(input *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+func (m *mockS3Client) DeleteBucketWithContext(ctx aws.Context, input *s3.DeleteBucketInput, opts ...request.Option) (*s3.DeleteBucketOutput, error) {
 	if aws.StringValue(input.Bucket) != instance.Spec.BucketName || !m.mockBucketExists {
 		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
 	}
@@ -1,5 +1,3 @@
-// +build !release
-
 /*
 Copyright 2018-2019 Ridecell, Inc.
 
@@ -19,18 +17,8 @@ limitations under the License.
 package serviceaccount
 
 import (
-	"net/http"
-	"path"
-	"runtime"
+	"github.com/Ridecell/ridecell-operator/pkg/templates"
 )
 
 //go:generate bash ../../../hack/assets_generate.sh controller/serviceaccount serviceaccount
-var Templates http.FileSystem
-
-func init() {
-	_, line, _, ok := runtime.Caller(0)
-	if !ok {
-		panic("Unable to find caller line")
-	}
-	Templates = http.Dir(path.Dir(line) + "/templates")
-}
+var Templates = templates.Named("controller/serviceaccount", assets)
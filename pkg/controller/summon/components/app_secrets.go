@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/Ridecell/ridecell-operator/pkg/errors"
@@ -127,11 +128,14 @@ func (comp *appSecretComponent) Reconcile(ctx *components.ComponentContext) (com
 
 	specInputSecrets, err := comp.fetchSecrets(ctx, instance, comp.specSecrets(instance), false)
 	if err != nil {
+		if kerrors.IsNotFound(errors.Cause(err)) {
+			return components.Result{StatusModifier: components.ConditionModifier(summonv1beta1.ConditionSecretsValid, conditions.ConditionFalse, "SecretMissing", err.Error())}, err
+		}
 		return components.Result{}, err
 	}
 	dynamicInputSecrets, err := comp.fetchSecrets(ctx, instance, comp.inputSecrets(instance), true)
 	if err != nil {
-		return components.Result{Requeue: true}, err
+		return components.Result{}, err
 	}
 
 	// This order must match the one in inputSecrets().
@@ -189,9 +193,10 @@ func (comp *appSecretComponent) Reconcile(ctx *components.ComponentContext) (com
 	}
 
 	// If OTAKEYS_API_KEY is provided externally and EnableMockCarServer is also true, it is a conflict
-	v := appSecretsData["OTAKEYS_API_KEY"]
-	if v != nil && len(v.(string)) > 0 && instance.Spec.EnableMockCarServer {
-		return components.Result{}, errors.Errorf("app_secrets: Conflict in OTA Keys configuration, cannot provide OTAKEYS_API_KEY and enableMockCarServer")
+	err = ValidateSecrets(instance, specInputSecrets)
+	if err != nil {
+		err = errors.Wrap(err, "app_secrets")
+		return components.Result{StatusModifier: components.ConditionModifier(summonv1beta1.ConditionSecretsValid, conditions.ConditionFalse, "SecretsInvalid", err.Error())}, errors.Permanent(err)
 	}
 	if instance.Spec.EnableMockCarServer {
 		for k, v := range mockCarServerSecret.Data {
@@ -334,7 +339,7 @@ func (comp *appSecretComponent) Reconcile(ctx *components.ComponentContext) (com
 		return components.Result{}, errors.Wrapf(err, "app_secrets: Failed to update comp-trip-share secret object")
 	}
 
-	return components.Result{}, nil
+	return components.Result{StatusModifier: components.ConditionModifier(summonv1beta1.ConditionSecretsValid, conditions.ConditionTrue, "SecretsValid", "")}, nil
 }
 
 func (_ *appSecretComponent) formatFernetKeys(fernetData map[string][]byte) ([]string, error) {
@@ -380,10 +385,7 @@ func (c *appSecretComponent) inputSecrets(instance *summonv1beta1.SummonPlatform
 }
 
 func (c *appSecretComponent) specSecrets(instance *summonv1beta1.SummonPlatform) []string {
-	if len(instance.Spec.Secrets) == 0 {
-		return []string{instance.Namespace, instance.Name}
-	}
-	return instance.Spec.Secrets
+	return SpecSecrets(instance)
 }
 
 func (_ *appSecretComponent) fetchSecrets(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform, secretNames []string, allowMissing bool) ([]*corev1.Secret, error) {
@@ -397,7 +399,8 @@ func (_ *appSecretComponent) fetchSecrets(ctx *components.ComponentContext, inst
 		err := ctx.Get(ctx.Context, types.NamespacedName{Name: secretName, Namespace: instance.Namespace}, secret)
 		if err != nil {
 			if kerrors.IsNotFound(err) && allowMissing {
-				err = errors.NoNotify(err)
+				// Created by another component, wait for it to show up.
+				err = errors.Waiting(err)
 			}
 			label := "input"
 			if allowMissing {
@@ -30,9 +30,11 @@ import (
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
 	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
 )
 
@@ -199,11 +201,43 @@ var _ = Describe("app_secrets Component", func() {
 		ctx.Client = fake.NewFakeClient(inSecret, postgresSecret, fernetKeys)
 		res, err := comp.Reconcile(ctx)
 		Expect(err).To(MatchError(`app_secrets: error fetching derived app secret foo-dev.secret-key: secrets "foo-dev.secret-key" not found`))
-		Expect(res.Requeue).To(BeTrue())
+		Expect(res.Requeue).To(BeFalse())
+		Expect(errors.ClassOf(err)).To(Equal(errors.ClassWaiting))
+		Expect(errors.ShouldNotify(err)).To(BeFalse())
 	})
 
 	It("runs reconcile with all values set", func() {
 		Expect(comp).To(ReconcileContext(ctx))
+		Expect(conditions.IsTrue(instance.Status.Conditions, summonv1beta1.ConditionSecretsValid)).To(BeTrue())
+	})
+
+	It("reports a missing input secret as a condition", func() {
+		ctx.Client = fake.NewFakeClient(postgresSecret, fernetKeys, secretKey, accessKey, rabbitmqPassword)
+		res, err := comp.Reconcile(ctx)
+		Expect(err).To(MatchError(`app_secrets: error fetching input app secret testsecret: secrets "testsecret" not found`))
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		cond := conditions.Find(instance.Status.Conditions, summonv1beta1.ConditionSecretsValid)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(conditions.ConditionFalse))
+		Expect(cond.Reason).To(Equal("SecretMissing"))
+	})
+
+	It("reports conflicting input secrets as a condition", func() {
+		instance.Spec.EnableMockCarServer = true
+		inSecret.Data["OTAKEYS_API_KEY"] = []byte("key")
+		otakeys := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-dev.tenant-otakeys", Namespace: "summon-dev"},
+			Data:       map[string][]byte{"OTAKEYS_API_KEY": []byte("mockkey")},
+		}
+		ctx.Client = fake.NewFakeClient(inSecret, postgresSecret, fernetKeys, secretKey, accessKey, rabbitmqPassword, otakeys)
+		res, err := comp.Reconcile(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.ClassOf(err)).To(Equal(errors.ClassPermanent))
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		cond := conditions.Find(instance.Status.Conditions, summonv1beta1.ConditionSecretsValid)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(conditions.ConditionFalse))
+		Expect(cond.Reason).To(Equal("SecretsInvalid"))
 	})
 
 	It("overwrites values using multiple secrets", func() {
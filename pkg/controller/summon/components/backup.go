@@ -17,14 +17,17 @@ limitations under the License.
 package components
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 )
 
@@ -67,6 +70,9 @@ func (comp *backupComponent) Reconcile(ctx *components.ComponentContext) (compon
 		return components.Result{StatusModifier: func(obj runtime.Object) error {
 			instance := obj.(*summonv1beta1.SummonPlatform)
 			instance.Status.Status = summonv1beta1.StatusMigrating
+			if instance.Status.BackupVersion != instance.Spec.Version {
+				components.SetCondition(instance, summonv1beta1.ConditionBackupTaken, conditions.ConditionFalse, "BackupSkipped", fmt.Sprintf("No RDS instance to back up for version %s", instance.Spec.Version))
+			}
 			instance.Status.BackupVersion = instance.Spec.Version
 			return nil
 		}}, nil
@@ -93,25 +99,30 @@ func (comp *backupComponent) Reconcile(ctx *components.ComponentContext) (compon
 			instance := obj.(*summonv1beta1.SummonPlatform)
 			instance.Status.Status = summonv1beta1.StatusMigrating
 			instance.Status.BackupVersion = instance.Spec.Version
+			components.SetCondition(instance, summonv1beta1.ConditionBackupTaken, conditions.ConditionTrue, "BackupStarted", fmt.Sprintf("Started RDS snapshot %s without waiting", existing.Name))
 			return nil
 		}}, nil
 	}
 
 	if existing.Status.Status == dbv1beta1.StatusError {
-		return components.Result{}, errors.Wrapf(err, "backup: rdssnapshot %s is in an error state", existing.Name)
+		return components.Result{
+			StatusModifier: components.ConditionModifier(summonv1beta1.ConditionBackupTaken, conditions.ConditionFalse, "BackupFailed", existing.Status.Message),
+		}, errors.Errorf("backup: rdssnapshot %s is in an error state: %s", existing.Name, existing.Status.Message)
 	}
 
 	// We can just return at this point.
 	// When the rdssnapshot is finished it will trigger this component to reconcile.
 	if existing.Status.Status == dbv1beta1.StatusCreating {
-		return components.Result{StatusModifier: setStatus(summonv1beta1.StatusCreatingBackup)}, nil
+		return components.Result{StatusModifier: setStatusAndCondition(summonv1beta1.StatusCreatingBackup, summonv1beta1.ConditionBackupTaken, conditions.ConditionFalse, "BackupRunning", fmt.Sprintf("Waiting for RDS snapshot %s", existing.Name))}, nil
 	}
 
 	if existing.Status.Status == dbv1beta1.StatusReady {
+		ctx.Eventf(corev1.EventTypeNormal, "BackupReady", "RDS snapshot %s is ready", existing.Name)
 		return components.Result{StatusModifier: func(obj runtime.Object) error {
 			instance := obj.(*summonv1beta1.SummonPlatform)
 			instance.Status.Status = summonv1beta1.StatusMigrating
 			instance.Status.BackupVersion = instance.Spec.Version
+			components.SetCondition(instance, summonv1beta1.ConditionBackupTaken, conditions.ConditionTrue, "BackupReady", fmt.Sprintf("RDS snapshot %s is ready", existing.Name))
 			return nil
 		}}, nil
 	}
@@ -26,9 +26,11 @@ import (
 
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
 	corev1 "k8s.io/api/core/v1"
@@ -102,6 +104,7 @@ var _ = Describe("SummonPlatform backup Component", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusCreatingBackup))
 		Expect(instance.Status.BackupVersion).ToNot(Equal(instance.Spec.Version))
+		Expect(ctx.Recorder.(*record.FakeRecorder).Events).ToNot(Receive())
 
 		// Set snapshot status to ready
 		rdsSnapshot.Status.Status = dbv1beta1.StatusReady
@@ -114,12 +117,37 @@ var _ = Describe("SummonPlatform backup Component", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusMigrating))
 		Expect(instance.Status.BackupVersion).To(Equal(instance.Spec.Version))
+		Expect(ctx.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal("Normal BackupReady RDS snapshot foo-dev-1.2.3 is ready")))
 
 		Expect(fetchRDSSnapshot.Spec.TTL).To(Equal(instance.Spec.Backup.TTL))
 
 		Expect(fetchRDSSnapshot.Spec.RDSInstanceID).To(Equal(postgresDatabase.Status.RDSInstanceID))
 	})
 
+	It("fails when the snapshot is in an error state", func() {
+		rdsSnapshot := &dbv1beta1.RDSSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-dev-1.2.3",
+				Namespace: instance.Namespace,
+			},
+			Status: dbv1beta1.RDSSnapshotStatus{
+				Status:  dbv1beta1.StatusError,
+				Message: "snapshot quota exceeded",
+			},
+		}
+		ctx.Client = fake.NewFakeClient(postgresDatabase, rdsSnapshot)
+
+		res, err := comp.Reconcile(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("backup: rdssnapshot foo-dev-1.2.3 is in an error state: snapshot quota exceeded"))
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		cond := conditions.Find(instance.Status.Conditions, summonv1beta1.ConditionBackupTaken)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(conditions.ConditionFalse))
+		Expect(cond.Reason).To(Equal("BackupFailed"))
+		Expect(instance.Status.BackupVersion).ToNot(Equal(instance.Spec.Version))
+	})
+
 	It("does not wait until snapshot is ready", func() {
 		falseBool := false
 		instance.Spec.Backup.WaitUntilReady = &falseBool
@@ -0,0 +1,392 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	secretsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/secrets/v1beta1"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// How often to check on a baking canary.
+const canaryCheckInterval = 30 * time.Second
+
+// The parts of the instance which get canary pods. Each has a <part>/canary.yml.tpl template.
+var canaryParts = []string{"web", "daphne"}
+
+// Interface for querying Prometheus to allow for a mock implementation.
+//go:generate moq -out zz_generated.mock_canarymetricsclient_test.go . CanaryMetricsClient
+type CanaryMetricsClient interface {
+	// Run an instant query which returns a single value. ok is false if there is no data, like
+	// when the canary hasn't had any traffic yet.
+	Query(ctx context.Context, prometheusURL string, query string) (value float64, ok bool, err error)
+}
+
+type realCanaryMetricsClient struct{}
+
+// Real implementation of Query using the Prometheus HTTP API.
+func (c *realCanaryMetricsClient) Query(ctx context.Context, prometheusURL string, query string) (float64, bool, error) {
+	queryURL := fmt.Sprintf("%s/api/v1/query?%s", strings.TrimSuffix(prometheusURL, "/"), url.Values{"query": []string{query}}.Encode())
+	req, err := http.NewRequest("GET", queryURL, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyContent, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return 0, false, err
+		}
+		return 0, false, errors.Errorf("error from prometheus %v: %s", resp.StatusCode, bodyContent)
+	}
+
+	result := struct {
+		Data struct {
+			Result []struct {
+				Value []interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "unable to decode prometheus response")
+	}
+	if len(result.Data.Result) == 0 || len(result.Data.Result[0].Value) != 2 {
+		return 0, false, nil
+	}
+	// Values come back as [timestamp, "string value"].
+	rawValue, ok := result.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, false, errors.Errorf("unexpected prometheus value %#v", result.Data.Result[0].Value[1])
+	}
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "unable to parse prometheus value")
+	}
+	// A rate over no requests comes out as NaN.
+	if math.IsNaN(value) {
+		return 0, false, nil
+	}
+	return value, true, nil
+}
+
+type canaryComponent struct {
+	metricsClient CanaryMetricsClient
+}
+
+func NewCanary() *canaryComponent {
+	return &canaryComponent{metricsClient: &realCanaryMetricsClient{}}
+}
+
+func (comp *canaryComponent) InjectMetricsClient(client CanaryMetricsClient) {
+	comp.metricsClient = client
+}
+
+func (_ *canaryComponent) WatchTypes() []runtime.Object {
+	return []runtime.Object{
+		&appsv1.Deployment{},
+	}
+}
+
+func (_ *canaryComponent) IsReconcilable(ctx *components.ComponentContext) bool {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	// Same requirements as the Deployments themselves.
+	if instance.Status.PullSecretStatus != secretsv1beta1.StatusReady {
+		return false
+	}
+	if instance.Status.PostgresStatus != dbv1beta1.StatusReady {
+		return false
+	}
+	return true
+}
+
+// While a canary runs the overall status is Canary rather than Deploying, which holds every other
+// Deployment on the old version until the canary is promoted.
+func (comp *canaryComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+
+	// Canaries only start once migrations are done.
+	if instance.Status.Status != summonv1beta1.StatusDeploying {
+		return components.Result{}, nil
+	}
+
+	canary := instance.Status.Canary
+	if !instance.Spec.Canary.Enabled || rolledBack(instance) {
+		// Clean up after a canary which was turned off or rolled back part way through.
+		err := comp.removeCanaries(ctx)
+		if err != nil || canary.Phase == "" {
+			return components.Result{}, err
+		}
+		return components.Result{StatusModifier: func(obj runtime.Object) error {
+			instance := obj.(*summonv1beta1.SummonPlatform)
+			instance.Status.Canary = summonv1beta1.CanaryStatus{}
+			return nil
+		}}, nil
+	}
+
+	if canary.Version != instance.Spec.Version {
+		// A version we haven't seen before, check what it is replacing.
+		stableVersion, err := comp.stableVersion(ctx)
+		if err != nil {
+			return components.Result{}, err
+		}
+		if stableVersion == "" || stableVersion == instance.Spec.Version {
+			// A new instance or the version is already out, nothing to compare the canary to.
+			return components.Result{}, comp.removeCanaries(ctx)
+		}
+		canary = summonv1beta1.CanaryStatus{
+			Phase:         summonv1beta1.CanaryPhaseProgressing,
+			Version:       instance.Spec.Version,
+			StableVersion: stableVersion,
+		}
+		ctx.Eventf(corev1.EventTypeNormal, "CanaryStarted", "Starting canary of version %s alongside %s", canary.Version, canary.StableVersion)
+	}
+
+	switch canary.Phase {
+	case summonv1beta1.CanaryPhasePromoted:
+		return components.Result{}, comp.removeCanaries(ctx)
+	case summonv1beta1.CanaryPhaseAborted:
+		// Stay on the stable version until someone picks a different one.
+		err := comp.removeCanaries(ctx)
+		if err != nil {
+			return components.Result{}, err
+		}
+		return components.Result{StatusModifier: comp.abortedModifier(canary)}, nil
+	}
+
+	deployments, err := comp.reconcileCanaries(ctx, canary.Version)
+	if err != nil {
+		return components.Result{}, err
+	}
+	ready := true
+	for _, deployment := range deployments {
+		if canaryProgressFailed(deployment) {
+			return comp.abort(ctx, canary, fmt.Sprintf("%s canary pods did not become ready", deployment.Name))
+		}
+		if !canaryReady(deployment) {
+			ready = false
+		}
+	}
+
+	if canary.Phase == summonv1beta1.CanaryPhaseProgressing {
+		if !ready {
+			canary.Message = "Waiting for canary pods to become ready"
+			// The Deployment watch will trigger a reconcile when they are.
+			return components.Result{StatusModifier: comp.canaryModifier(canary)}, nil
+		}
+		canary.Phase = summonv1beta1.CanaryPhaseBaking
+		canary.BakeStarted = time.Now().Format(time.RFC3339)
+	}
+
+	// Baking.
+	if !ready {
+		return comp.abort(ctx, canary, "canary pods stopped being ready")
+	}
+	bakeStarted, err := time.Parse(time.RFC3339, canary.BakeStarted)
+	if err != nil {
+		return components.Result{}, errors.Wrap(err, "canary: failed to parse bake start time")
+	}
+
+	healthy := true
+	canary.Message = ""
+	prometheusURL := ctx.Config().Get("PROMETHEUS_URL")
+	if prometheusURL != "" {
+		errorRate, ok, err := comp.metricsClient.Query(ctx.Context, prometheusURL, instance.Spec.Canary.ErrorRateQuery)
+		if err != nil {
+			// Don't abort over a monitoring problem, but don't promote without a check either.
+			ctx.Logger().Error(err, "Error checking canary error rate")
+			healthy = false
+			canary.Message = fmt.Sprintf("Unable to check error rate: %s", err)
+		} else if ok {
+			canary.ErrorRate = strconv.FormatFloat(errorRate, 'f', 4, 64)
+			if errorRate > *instance.Spec.Canary.MaxErrorRate {
+				return comp.abort(ctx, canary, fmt.Sprintf("error rate %s is above %v", canary.ErrorRate, *instance.Spec.Canary.MaxErrorRate))
+			}
+		}
+	}
+
+	remaining := instance.Spec.Canary.BakeTime.Duration - time.Since(bakeStarted)
+	if remaining > 0 || !healthy {
+		if canary.Message == "" {
+			canary.Message = fmt.Sprintf("Baking, %s left", remaining.Round(time.Second))
+		}
+		requeue := canaryCheckInterval
+		if remaining > 0 && remaining < requeue {
+			requeue = remaining
+		}
+		return components.Result{StatusModifier: comp.canaryModifier(canary), RequeueAfter: requeue}, nil
+	}
+
+	// Baked, let the rest of the deploy continue.
+	err = comp.removeCanaries(ctx)
+	if err != nil {
+		return components.Result{}, err
+	}
+	ctx.Eventf(corev1.EventTypeNormal, "CanaryPromoted", "Promoting version %s after baking for %s", canary.Version, instance.Spec.Canary.BakeTime.Duration)
+	canary.Phase = summonv1beta1.CanaryPhasePromoted
+	canary.Message = ""
+	return components.Result{StatusModifier: func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Canary = canary
+		return nil
+	}}, nil
+}
+
+// Find the version the web pods are running now, or "" if there aren't any yet.
+func (comp *canaryComponent) stableVersion(ctx *components.ComponentContext) (string, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	web := &appsv1.Deployment{}
+	err := ctx.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s-web", instance.Name), Namespace: instance.Namespace}, web)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "canary: failed to get web deployment")
+	}
+	return web.Labels["app.kubernetes.io/version"], nil
+}
+
+// Create or update the canary Deployments and fetch them back.
+func (comp *canaryComponent) reconcileCanaries(ctx *components.ComponentContext, version string) ([]*appsv1.Deployment, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	extra, err := deploymentHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	extra["canary"] = true
+	extra["version"] = version
+
+	deployments := []*appsv1.Deployment{}
+	for _, part := range canaryParts {
+		extra["canaryReplicas"] = canaryReplicas(instance, part)
+		_, _, err := ctx.CreateOrUpdate(part+"/canary.yml.tpl", extra, func(goalObj, existingObj runtime.Object) error {
+			goal := goalObj.(*appsv1.Deployment)
+			existing := existingObj.(*appsv1.Deployment)
+			existing.Spec = goal.Spec
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "canary: failed to update %s canary", part)
+		}
+		deployment := &appsv1.Deployment{}
+		err = ctx.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s-%s-canary", instance.Name, part), Namespace: instance.Namespace}, deployment)
+		if err != nil {
+			return nil, errors.Wrapf(err, "canary: failed to get %s canary", part)
+		}
+		deployments = append(deployments, deployment)
+	}
+	return deployments, nil
+}
+
+// Render the canary templates empty so any canary Deployments get pruned.
+func (comp *canaryComponent) removeCanaries(ctx *components.ComponentContext) error {
+	for _, part := range canaryParts {
+		_, _, err := ctx.CreateOrUpdate(part+"/canary.yml.tpl", map[string]interface{}{}, func(_, _ runtime.Object) error { return nil })
+		if err != nil {
+			return errors.Wrapf(err, "canary: failed to remove %s canary", part)
+		}
+	}
+	return nil
+}
+
+func (comp *canaryComponent) abort(ctx *components.ComponentContext, canary summonv1beta1.CanaryStatus, reason string) (components.Result, error) {
+	err := comp.removeCanaries(ctx)
+	if err != nil {
+		return components.Result{}, err
+	}
+	ctx.Eventf(corev1.EventTypeWarning, "CanaryAborted", "Aborted canary of version %s: %s", canary.Version, reason)
+	canary.Phase = summonv1beta1.CanaryPhaseAborted
+	canary.Message = reason
+	return components.Result{StatusModifier: comp.abortedModifier(canary)}, nil
+}
+
+func (comp *canaryComponent) canaryModifier(canary summonv1beta1.CanaryStatus) components.StatusModifier {
+	return func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Status = summonv1beta1.StatusCanary
+		instance.Status.Message = fmt.Sprintf("Canary of version %s: %s", canary.Version, canary.Message)
+		instance.Status.Canary = canary
+		return nil
+	}
+}
+
+func (comp *canaryComponent) abortedModifier(canary summonv1beta1.CanaryStatus) components.StatusModifier {
+	return func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Status = summonv1beta1.StatusCanaryAborted
+		instance.Status.Message = fmt.Sprintf("Canary of version %s aborted, staying on %s: %s", canary.Version, canary.StableVersion, canary.Message)
+		instance.Status.Canary = canary
+		return nil
+	}
+}
+
+// Number of canary pods for a part, Spec.Canary.Weight percent of its replicas rounded up.
+func canaryReplicas(instance *summonv1beta1.SummonPlatform, part string) int32 {
+	var replicas *int32
+	switch part {
+	case "web":
+		replicas = instance.Spec.Replicas.Web
+	case "daphne":
+		replicas = instance.Spec.Replicas.Daphne
+	}
+	if replicas == nil || *replicas == 0 {
+		return 0
+	}
+	return int32(math.Ceil(float64(*replicas) * float64(instance.Spec.Canary.Weight) / 100))
+}
+
+func canaryReady(deployment *appsv1.Deployment) bool {
+	replicas := deployment.Spec.Replicas
+	return replicas != nil &&
+		deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == *replicas &&
+		deployment.Status.ReadyReplicas == *replicas &&
+		deployment.Status.UnavailableReplicas == 0
+}
+
+func canaryProgressFailed(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse && condition.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	secretsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/secrets/v1beta1"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
+)
+
+var _ = Describe("SummonPlatform Canary Component", func() {
+	comp := summoncomponents.NewCanary()
+	var mockedMetricsClient *summoncomponents.CanaryMetricsClientMock
+	var objects []runtime.Object
+
+	// A Deployment for one part of the instance, on the given version.
+	deployment := func(name string, version string, ready bool) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("foo-dev-%s", name),
+				Namespace: instance.Namespace,
+				Labels:    map[string]string{"app.kubernetes.io/version": version},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: intp(1)},
+		}
+		if ready {
+			deployment.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1}
+		}
+		return deployment
+	}
+
+	getCanary := func(part string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("foo-dev-%s-canary", part), Namespace: instance.Namespace}, deployment)
+		Expect(err).ToNot(HaveOccurred())
+		return deployment
+	}
+
+	BeforeEach(func() {
+		comp = summoncomponents.NewCanary()
+		mockedMetricsClient = &summoncomponents.CanaryMetricsClientMock{
+			QueryFunc: func(_ context.Context, _ string, _ string) (float64, bool, error) {
+				return 0.01, true, nil
+			},
+		}
+		comp.InjectMetricsClient(mockedMetricsClient)
+		os.Setenv("PROMETHEUS_URL", "http://prometheus")
+
+		instance.Spec.Canary.Enabled = true
+		summoncomponents.SetDefaults(instance)
+		instance.Status.Status = summonv1beta1.StatusDeploying
+		instance.Status.PullSecretStatus = secretsv1beta1.StatusReady
+		instance.Status.PostgresStatus = dbv1beta1.StatusReady
+
+		objects = []runtime.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-dev-config", Namespace: instance.Namespace},
+				Data:       map[string]string{"summon-platform.yml": "{}\n"},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-dev.app-secrets", Namespace: instance.Namespace},
+				Data:       map[string][]byte{"filler": []byte("test")},
+			},
+			deployment("web", "1.2.2", true),
+		}
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("PROMETHEUS_URL")
+	})
+
+	It("does nothing when canaries are disabled", func() {
+		instance.Spec.Canary.Enabled = false
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeploying))
+		Expect(instance.Status.Canary.Phase).To(Equal(""))
+	})
+
+	It("does nothing for a new instance", func() {
+		ctx.Client = fake.NewFakeClient(objects[:2]...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeploying))
+		Expect(instance.Status.Canary.Phase).To(Equal(""))
+	})
+
+	It("does nothing if the version is already deployed", func() {
+		objects[2] = deployment("web", "1.2.3", true)
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeploying))
+		Expect(instance.Status.Canary.Phase).To(Equal(""))
+	})
+
+	It("starts a canary for a new version", func() {
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusCanary))
+		Expect(instance.Status.Canary.Phase).To(Equal(summonv1beta1.CanaryPhaseProgressing))
+		Expect(instance.Status.Canary.Version).To(Equal("1.2.3"))
+		Expect(instance.Status.Canary.StableVersion).To(Equal("1.2.2"))
+
+		web := getCanary("web")
+		Expect(*web.Spec.Replicas).To(BeEquivalentTo(1))
+		Expect(web.Spec.Template.Spec.Containers[0].Image).To(Equal("us.gcr.io/ridecell-1/summon:1.2.3"))
+		Expect(web.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app.kubernetes.io/instance", "foo-dev-web"))
+		Expect(web.Spec.Selector.MatchLabels).To(HaveKeyWithValue("summon.ridecell.io/track", "canary"))
+		Expect(web.Spec.Template.Spec.Containers[0].ReadinessProbe).ToNot(BeNil())
+		daphne := getCanary("daphne")
+		Expect(daphne.Spec.Template.Spec.Containers[0].Image).To(Equal("us.gcr.io/ridecell-1/summon:1.2.3"))
+	})
+
+	It("sizes the canary from the weight", func() {
+		instance.Spec.Replicas.Web = intp(8)
+		instance.Spec.Canary.Weight = 20
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(*getCanary("web").Spec.Replicas).To(BeEquivalentTo(2))
+	})
+
+	It("starts baking once the canary pods are ready", func() {
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseProgressing, Version: "1.2.3", StableVersion: "1.2.2"}
+		objects = append(objects, deployment("web-canary", "1.2.3", true), deployment("daphne-canary", "1.2.3", true))
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusCanary))
+		Expect(instance.Status.Canary.Phase).To(Equal(summonv1beta1.CanaryPhaseBaking))
+		Expect(instance.Status.Canary.BakeStarted).ToNot(Equal(""))
+		Expect(instance.Status.Canary.ErrorRate).To(Equal("0.0100"))
+		Expect(mockedMetricsClient.QueryCalls()).To(HaveLen(1))
+		Expect(mockedMetricsClient.QueryCalls()[0].Query).To(ContainSubstring(`pod=~"foo-dev-web-canary-.*"`))
+	})
+
+	It("promotes the canary after baking", func() {
+		bakeStarted := time.Now().Add(-15 * time.Minute).Format(time.RFC3339)
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseBaking, Version: "1.2.3", StableVersion: "1.2.2", BakeStarted: bakeStarted}
+		objects = append(objects, deployment("web-canary", "1.2.3", true), deployment("daphne-canary", "1.2.3", true))
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeploying))
+		Expect(instance.Status.Canary.Phase).To(Equal(summonv1beta1.CanaryPhasePromoted))
+	})
+
+	It("does not promote if the error rate can't be checked", func() {
+		mockedMetricsClient.QueryFunc = func(_ context.Context, _ string, _ string) (float64, bool, error) {
+			return 0, false, fmt.Errorf("connection refused")
+		}
+		bakeStarted := time.Now().Add(-15 * time.Minute).Format(time.RFC3339)
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseBaking, Version: "1.2.3", StableVersion: "1.2.2", BakeStarted: bakeStarted}
+		objects = append(objects, deployment("web-canary", "1.2.3", true), deployment("daphne-canary", "1.2.3", true))
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusCanary))
+		Expect(instance.Status.Canary.Phase).To(Equal(summonv1beta1.CanaryPhaseBaking))
+		Expect(instance.Status.Canary.Message).To(ContainSubstring("connection refused"))
+	})
+
+	It("aborts when the error rate is too high", func() {
+		mockedMetricsClient.QueryFunc = func(_ context.Context, _ string, _ string) (float64, bool, error) {
+			return 0.5, true, nil
+		}
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseBaking, Version: "1.2.3", StableVersion: "1.2.2", BakeStarted: time.Now().Format(time.RFC3339)}
+		objects = append(objects, deployment("web-canary", "1.2.3", true), deployment("daphne-canary", "1.2.3", true))
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusCanaryAborted))
+		Expect(instance.Status.Canary.Phase).To(Equal(summonv1beta1.CanaryPhaseAborted))
+		Expect(instance.Status.Canary.Message).To(Equal("error rate 0.5000 is above 0.05"))
+	})
+
+	It("aborts when the canary pods stop being ready", func() {
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseBaking, Version: "1.2.3", StableVersion: "1.2.2", BakeStarted: time.Now().Format(time.RFC3339)}
+		objects = append(objects, deployment("web-canary", "1.2.3", false), deployment("daphne-canary", "1.2.3", true))
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusCanaryAborted))
+		Expect(instance.Status.Canary.Message).To(Equal("canary pods stopped being ready"))
+	})
+
+	It("stays aborted for the same version", func() {
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseAborted, Version: "1.2.3", StableVersion: "1.2.2", Message: "canary pods stopped being ready"}
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusCanaryAborted))
+		Expect(instance.Status.Message).To(Equal("Canary of version 1.2.3 aborted, staying on 1.2.2: canary pods stopped being ready"))
+	})
+
+	It("clears the canary after a rollback", func() {
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseBaking, Version: "1.2.3", StableVersion: "1.2.2"}
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{FailedVersion: "1.2.3", Version: "1.2.2"}
+		ctx.Client = fake.NewFakeClient(objects...)
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeploying))
+		Expect(instance.Status.Canary.Phase).To(Equal(""))
+	})
+})
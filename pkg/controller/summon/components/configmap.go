@@ -47,8 +47,23 @@ func (_ *configmapComponent) IsReconcilable(_ *components.ComponentContext) bool
 }
 
 func (comp *configmapComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
-	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	extra, err := configMapData(ctx.Top.(*summonv1beta1.SummonPlatform))
+	if err != nil {
+		return components.Result{}, err
+	}
 
+	res, _, err := ctx.CreateOrUpdate(comp.templatePath, extra, func(goalObj, existingObj runtime.Object) error {
+		goal := goalObj.(*corev1.ConfigMap)
+		existing := existingObj.(*corev1.ConfigMap)
+		// Copy the data over.
+		existing.Data = goal.Data
+		return nil
+	})
+	return res, err
+}
+
+// Template data for the ConfigMap, with the config rendered as the summon-platform.yml.
+func configMapData(instance *summonv1beta1.SummonPlatform) (map[string]interface{}, error) {
 	// Create the map that will be the summon-platform.yml
 	config := map[string]interface{}{}
 	for key, value := range instance.Spec.Config {
@@ -58,19 +73,11 @@ func (comp *configmapComponent) Reconcile(ctx *components.ComponentContext) (com
 	// Render to JSON (which is a subset of YAML).
 	b, err := json.Marshal(config)
 	if err != nil {
-		return components.Result{}, errors.Wrapf(err, "configmap: unable to serialize config JSON for %s/%s", instance.Namespace, instance.Name)
+		return nil, errors.Wrapf(err, "configmap: unable to serialize config JSON for %s/%s", instance.Namespace, instance.Name)
 	}
 
 	// Set up the extra data map for the template.
 	extra := map[string]interface{}{}
 	extra["SummonYaml"] = string(b)
-
-	res, _, err := ctx.CreateOrUpdate(comp.templatePath, extra, func(goalObj, existingObj runtime.Object) error {
-		goal := goalObj.(*corev1.ConfigMap)
-		existing := existingObj.(*corev1.ConfigMap)
-		// Copy the data over.
-		existing.Data = goal.Data
-		return nil
-	})
-	return res, err
+	return extra, nil
 }
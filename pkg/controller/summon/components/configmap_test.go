@@ -108,4 +108,45 @@ var _ = Describe("SummonPlatform Configmap Component", func() {
 			Expect(configmap.Data["summon-platform.yml"]).To(Equal("{\"foo\":true}\n"))
 		})
 	})
+
+	Context("with an int config value", func() {
+		It("creates a config file without losing precision", func() {
+			instance.Spec.Config = map[string]summonv1beta1.ConfigValue{}
+			val := int64(9007199254740993)
+			instance.Spec.Config["foo"] = summonv1beta1.ConfigValue{Int: &val}
+
+			comp := summoncomponents.NewConfigMap("configmap.yml.tpl")
+			Expect(comp).To(ReconcileContext(ctx))
+
+			configmap := &corev1.ConfigMap{}
+			err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-config", Namespace: "summon-dev"}, configmap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configmap.Data).To(HaveKey("summon-platform.yml"))
+			Expect(configmap.Data["summon-platform.yml"]).To(Equal("{\"foo\":9007199254740993}\n"))
+		})
+	})
+
+	Context("with list and map config values", func() {
+		It("creates a config file", func() {
+			a := "a.example.com"
+			b := "b.example.com"
+			timeout := int64(60)
+			enabled := true
+			instance.Spec.Config = map[string]summonv1beta1.ConfigValue{}
+			instance.Spec.Config["hosts"] = summonv1beta1.ConfigValue{List: []summonv1beta1.ConfigValue{{String: &a}, {String: &b}}}
+			instance.Spec.Config["cache"] = summonv1beta1.ConfigValue{Map: map[string]summonv1beta1.ConfigValue{
+				"timeout": {Int: &timeout},
+				"options": {Map: map[string]summonv1beta1.ConfigValue{"enabled": {Bool: &enabled}}},
+			}}
+
+			comp := summoncomponents.NewConfigMap("configmap.yml.tpl")
+			Expect(comp).To(ReconcileContext(ctx))
+
+			configmap := &corev1.ConfigMap{}
+			err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-config", Namespace: "summon-dev"}, configmap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configmap.Data).To(HaveKey("summon-platform.yml"))
+			Expect(configmap.Data["summon-platform.yml"]).To(Equal("{\"cache\":{\"options\":{\"enabled\":true},\"timeout\":60},\"hosts\":[\"a.example.com\",\"b.example.com\"]}\n"))
+		})
+	})
 })
@@ -18,15 +18,15 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
-	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
 )
 
 const defaultFernetKeysLifespan = "8760h"
@@ -36,6 +36,13 @@ var zeroSeconds time.Duration
 
 var configDefaults map[string]summonv1beta1.ConfigValue
 
+// The version of the defaults applied by SetDefaults, recorded in DefaultsVersionAnnotation when
+// the defaulting webhook persists them. Bump this when changing the default for an existing field.
+const DefaultsVersion = "1"
+
+// Annotation recording which DefaultsVersion was persisted into an instance.
+const DefaultsVersionAnnotation = "summon.ridecell.io/defaults-version"
+
 type defaultsComponent struct {
 }
 
@@ -55,25 +62,27 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
 
 	// Set error status to prevent further deployments until it is resolved.
-	if instance.Spec.Version == "" && instance.Spec.AutoDeploy == "" {
-		return components.Result{}, errors.New("Spec.Version OR Spec.AutoDeploy must be set. No Version set for deployment.")
+	err := Validate(instance)
+	if err != nil {
+		return components.Result{}, errors.InvalidSpec(err)
 	}
 
-	if instance.Spec.Version != "" && instance.Spec.AutoDeploy != "" {
-		return components.Result{}, errors.New("Spec.Version and Spec.AutoDeploy are both set. Must specify only one.")
-	}
+	SetDefaults(instance)
+	setDerivedValues(instance)
 
+	return components.Result{}, nil
+}
+
+// Fill in defaults for any unset fields. Everything set here is safe to persist into the stored
+// object, which the defaulting webhook does on create. When changing what this sets for
+// existing fields, bump DefaultsVersion.
+func SetDefaults(instance *summonv1beta1.SummonPlatform) {
 	// Enable web prometheus metrics exporting everywhere.
 	if instance.Spec.Metrics.Web == nil {
 		val := true
 		instance.Spec.Metrics.Web = &val
 	}
 
-	// If the persistentVolumeClaim for redis changes this integer should as well.
-	if instance.Spec.Redis.RAM > 10*1024 {
-		return components.Result{}, errors.New("redis memory limit cannot surpass available disk space")
-	}
-
 	// Set redis defaults
 	if instance.Spec.Redis.RAM == 0 {
 		instance.Spec.Redis.RAM = 200
@@ -113,10 +122,7 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 		}
 		instance.Spec.Hostname = instance.Name + baseHostname
 	}
-	err := comp.replicaDefaults(instance)
-	if err != nil {
-		return components.Result{}, errors.Wrap(err, "error setting replica defaults")
-	}
+	replicaDefaults(instance)
 	if instance.Spec.PullSecret == "" {
 		instance.Spec.PullSecret = "pull-secret"
 	}
@@ -126,7 +132,7 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 		instance.Spec.FernetKeyLifetime = parsedTimeDuration
 	}
 	if instance.Spec.AwsRegion == "" {
-		instance.Spec.AwsRegion = os.Getenv("AWS_REGION")
+		instance.Spec.AwsRegion = config.Get("AWS_REGION")
 		// If the env var isn't present, assume us-west-2. Mostly for local testing stuff.
 		if instance.Spec.AwsRegion == "" {
 			instance.Spec.AwsRegion = "us-west-2"
@@ -176,6 +182,28 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 			instance.Spec.Backup.WaitUntilReady = &devWaitBool
 		}
 	}
+	// Canary settings, only used when canaries are enabled.
+	if instance.Spec.Canary.Weight == 0 {
+		instance.Spec.Canary.Weight = 10
+	}
+	if instance.Spec.Canary.BakeTime.Duration == 0 {
+		instance.Spec.Canary.BakeTime.Duration = 10 * time.Minute
+	}
+	if instance.Spec.Canary.MaxErrorRate == nil {
+		val := 0.05
+		instance.Spec.Canary.MaxErrorRate = &val
+	}
+	if instance.Spec.Canary.ErrorRateQuery == "" {
+		selector := fmt.Sprintf(`namespace="%s",pod=~"%s-web-canary-.*"`, instance.Namespace, instance.Name)
+		instance.Spec.Canary.ErrorRateQuery = fmt.Sprintf(`sum(rate(django_http_responses_total_by_status_total{%s,status=~"5.."}[5m])) / sum(rate(django_http_responses_total_by_status_total{%s}[5m]))`, selector, selector)
+	}
+	if instance.Spec.Rollback.DeployDeadline.Duration == 0 {
+		instance.Spec.Rollback.DeployDeadline.Duration = 30 * time.Minute
+	}
+	if instance.Spec.Maintenance.Message == "" {
+		instance.Spec.Maintenance.Message = "We are performing scheduled maintenance and will be back shortly."
+	}
+
 	if instance.Spec.Environment == "uat" || instance.Spec.Environment == "prod" {
 		defVal("FIREBASE_APP", "ridecell")
 
@@ -196,6 +224,57 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 	}
 
 	// Fill in the config values that need the instance name in them.
+	defVal("FIREBASE_ROOT_NODE", "%s", instance.Name)
+	defVal("TENANT_ID", "%s", instance.Name)
+	defVal("NEWRELIC_NAME", "%s-summon-platform", instance.Name)
+	defVal("AWS_STORAGE_BUCKET_NAME", "ridecell-%s-static", instance.Name)
+	defVal("HWAUX_BASE_URL", "http://%s-hwaux:8000/", instance.Name)
+
+	if instance.Spec.Environment == "dev" || instance.Spec.Environment == "qa" {
+		// Enable DEBUG automatically for dev/qa.
+		defBoolVal("DEBUG", true)
+		defBoolVal("ENABLE_JSON_LOGGING", true)
+	}
+
+	// Set debug to false globally if not already set.
+	defBoolVal("DEBUG", false)
+}
+
+// Values computed from other fields on every reconcile. These are never persisted since they
+// have to follow changes to the fields they come from.
+func setDerivedValues(instance *summonv1beta1.SummonPlatform) {
+	replicas := &instance.Spec.Replicas
+	intp := func(i int32) *int32 { return &i }
+
+	// If no component version is set, override replicas to 0.
+	if instance.Spec.Dispatch.Version == "" {
+		replicas.Dispatch = intp(0)
+	}
+	if instance.Spec.BusinessPortal.Version == "" {
+		replicas.BusinessPortal = intp(0)
+	}
+	if instance.Spec.TripShare.Version == "" {
+		replicas.TripShare = intp(0)
+	}
+	if instance.Spec.HwAux.Version == "" {
+		replicas.HwAux = intp(0)
+	}
+
+	defVal := func(key, valueTemplate string, args ...interface{}) {
+		_, ok := instance.Spec.Config[key]
+		if !ok {
+			value := fmt.Sprintf(valueTemplate, args...)
+			instance.Spec.Config[key] = summonv1beta1.ConfigValue{String: &value}
+		}
+	}
+	defBoolVal := func(key string, value bool) {
+		_, ok := instance.Spec.Config[key]
+		if !ok {
+			instance.Spec.Config[key] = summonv1beta1.ConfigValue{Bool: &value}
+		}
+	}
+
+	// Config values that follow the aliases, Redis and AWS settings.
 	webURL := instance.Spec.Hostname
 	if instance.Spec.Aliases != nil && len(instance.Spec.Aliases) > 0 {
 		webURL = instance.Spec.Aliases[0]
@@ -210,28 +289,11 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 		defVal("CACHE_URL", "redis://%s-redis/1", instance.Name)
 	}
 
-	defVal("FIREBASE_ROOT_NODE", "%s", instance.Name)
-	defVal("TENANT_ID", "%s", instance.Name)
-	defVal("NEWRELIC_NAME", "%s-summon-platform", instance.Name)
 	defVal("AWS_REGION", "%s", instance.Spec.AwsRegion)
-	defVal("AWS_STORAGE_BUCKET_NAME", "ridecell-%s-static", instance.Name)
 	defVal("DATA_PIPELINE_SQS_QUEUE_NAME", "%s", instance.Spec.SQSQueue)
-	defVal("HWAUX_BASE_URL", "http://%s-hwaux:8000/", instance.Name)
-	// NOTE: For now, only set the dispatch URL if the component is enabled. This was a miscommunication with
-	// the backend team and can be removed some time after PCR 2020-6 goes out. Confirm with the Ridesharing
-	// team before putting this back to always being set as a default.
-	if instance.Spec.Replicas.Dispatch != nil && *instance.Spec.Replicas.Dispatch > 0 {
-		defVal("DISPATCH_BASE_URL", "http://%s-dispatch:8000/", instance.Name)
-	}
-
-	// Indicator flags for if each component is enabled or not.
-	defBoolVal("DISPATCH_ENABLED", instance.Spec.Replicas.Dispatch != nil && *instance.Spec.Replicas.Dispatch > 0)
-	defBoolVal("HWAUX_ENABLED", instance.Spec.Replicas.HwAux != nil && *instance.Spec.Replicas.HwAux > 0)
-	defBoolVal("BUSINESSPORTAL_ENABLED", instance.Spec.Replicas.BusinessPortal != nil && *instance.Spec.Replicas.BusinessPortal > 0)
-	defBoolVal("TRIPSHARE_ENABLED", instance.Spec.Replicas.TripShare != nil && *instance.Spec.Replicas.TripShare > 0)
 
 	// Translate our aws region into a usable region
-	untranslatedRegion := strings.Split(os.Getenv("AWS_REGION"), "-")[0]
+	untranslatedRegion := strings.Split(config.Get("AWS_REGION"), "-")[0]
 	translatedRegion := untranslatedRegion
 	if untranslatedRegion == "ap" {
 		translatedRegion = "in"
@@ -239,30 +301,34 @@ func (comp *defaultsComponent) Reconcile(ctx *components.ComponentContext) (comp
 
 	// Set our gateway environment for GATEWAY_BASE_URL
 	gatewayEnv := "prod"
-
 	if instance.Spec.Environment == "dev" || instance.Spec.Environment == "qa" {
-		// Enable DEBUG automatically for dev/qa.
-		defBoolVal("DEBUG", true)
-		defBoolVal("ENABLE_JSON_LOGGING", true)
 		gatewayEnv = "master"
 	}
 
-	// Set debug to false globally if not already set.
-	defBoolVal("DEBUG", false)
-
 	// Use our translated region and gateway env to set GATEWAY_BASE_URL
 	defVal("GATEWAY_BASE_URL", "https://global.%s.%s.svc.ridecell.io/", translatedRegion, gatewayEnv)
 
+	// NOTE: For now, only set the dispatch URL if the component is enabled. This was a miscommunication with
+	// the backend team and can be removed some time after PCR 2020-6 goes out. Confirm with the Ridesharing
+	// team before putting this back to always being set as a default.
+	if instance.Spec.Replicas.Dispatch != nil && *instance.Spec.Replicas.Dispatch > 0 {
+		defVal("DISPATCH_BASE_URL", "http://%s-dispatch:8000/", instance.Name)
+	}
+
+	// Indicator flags for if each component is enabled or not.
+	defBoolVal("DISPATCH_ENABLED", instance.Spec.Replicas.Dispatch != nil && *instance.Spec.Replicas.Dispatch > 0)
+	defBoolVal("HWAUX_ENABLED", instance.Spec.Replicas.HwAux != nil && *instance.Spec.Replicas.HwAux > 0)
+	defBoolVal("BUSINESSPORTAL_ENABLED", instance.Spec.Replicas.BusinessPortal != nil && *instance.Spec.Replicas.BusinessPortal > 0)
+	defBoolVal("TRIPSHARE_ENABLED", instance.Spec.Replicas.TripShare != nil && *instance.Spec.Replicas.TripShare > 0)
+
 	// Enable NewRelic if requested.
 	if instance.Spec.EnableNewRelic != nil && *instance.Spec.EnableNewRelic {
 		val := true
 		instance.Spec.Config["ENABLE_NEW_RELIC"] = summonv1beta1.ConfigValue{Bool: &val}
 	}
-
-	return components.Result{}, nil
 }
 
-func (comp *defaultsComponent) replicaDefaults(instance *summonv1beta1.SummonPlatform) error {
+func replicaDefaults(instance *summonv1beta1.SummonPlatform) {
 	replicas := &instance.Spec.Replicas
 	intp := func(i int32) *int32 { return &i }
 	defaultsForEnv := func(dev, qa, uat, prod int32) *int32 {
@@ -313,46 +379,34 @@ func (comp *defaultsComponent) replicaDefaults(instance *summonv1beta1.SummonPla
 	if replicas.HwAux == nil {
 		replicas.HwAux = defaultsForEnv(1, 1, 2, 2)
 	}
+}
 
-	// If no component version is set, override replicas to 0.
-	if instance.Spec.Dispatch.Version == "" {
-		replicas.Dispatch = intp(0)
-	}
-	if instance.Spec.BusinessPortal.Version == "" {
-		replicas.BusinessPortal = intp(0)
-	}
-	if instance.Spec.TripShare.Version == "" {
-		replicas.TripShare = intp(0)
-	}
-	if instance.Spec.HwAux.Version == "" {
-		replicas.HwAux = intp(0)
-	}
-
-	// Quick error check.
-	if !(*replicas.CeleryBeat == 0 || *replicas.CeleryBeat == 1) {
-		return errors.Errorf("Invalid celerybeat replicas, must be exactly 0 or 1: %v", *replicas.CeleryBeat)
-	}
-
-	return nil
+// Scale every component to zero while hibernating. Only applied in memory by the hibernation
+// component, never from SetDefaults, since those values get persisted.
+func sleepReplicas(instance *summonv1beta1.SummonPlatform) {
+	replicas := &instance.Spec.Replicas
+	intp := func(i int32) *int32 { return &i }
+	replicas.Web = intp(0)
+	replicas.Celeryd = intp(0)
+	replicas.Daphne = intp(0)
+	replicas.ChannelWorker = intp(0)
+	replicas.Static = intp(0)
+	replicas.CeleryBeat = intp(0)
+	replicas.Dispatch = intp(0)
+	replicas.BusinessPortal = intp(0)
+	replicas.TripShare = intp(0)
+	replicas.HwAux = intp(0)
+	// The HPA would scale celeryd straight back up.
+	autoscale := false
+	replicas.CelerydAuto = &autoscale
 }
 
 func defConfig(key string, value interface{}) {
-	boolVal, ok := value.(bool)
-	if ok {
-		configDefaults[key] = summonv1beta1.ConfigValue{Bool: &boolVal}
-		return
-	}
-	floatVal, ok := value.(float64)
-	if ok {
-		configDefaults[key] = summonv1beta1.ConfigValue{Float: &floatVal}
-		return
-	}
-	stringVal, ok := value.(string)
-	if ok {
-		configDefaults[key] = summonv1beta1.ConfigValue{String: &stringVal}
-		return
-	}
-	panic("Unknown type")
+	configValue, err := summonv1beta1.NewConfigValue(value)
+	if err != nil {
+		panic(err)
+	}
+	configDefaults[key] = configValue
 }
 
 func init() {
@@ -377,7 +431,7 @@ ZSo/8E5P29isb34ZQedtc1kCAwEAAQ==
 -----END PUBLIC KEY-----`)
 	defConfig("CARSHARING_V1_API_DISABLED", false)
 	defConfig("CLOUDFRONT_DISTRIBUTION", "")
-	defConfig("CONN_MAX_AGE", float64(60))
+	defConfig("CONN_MAX_AGE", 60)
 	defConfig("COMPRESS_ENABLED", false)
 	defConfig("CSBE_CONNECTION_USED", false)
 	defConfig("ENABLE_NEW_RELIC", false)
@@ -406,8 +460,8 @@ ZSo/8E5P29isb34ZQedtc1kCAwEAAQ==
 	defConfig("SAML_PUBLIC_KEY_FILENAME", "sp.crt")
 	defConfig("SAML_SERVICE_NAME", "")
 	defConfig("SAML_USE_LOCAL_METADATA", "")
-	defConfig("SAML_VALID_FOR_HOURS", float64(24))
-	defConfig("SESSION_COOKIE_AGE", float64(1209600))
+	defConfig("SAML_VALID_FOR_HOURS", 24)
+	defConfig("SESSION_COOKIE_AGE", 1209600)
 	defConfig("TIME_ZONE", "America/Los_Angeles")
 	defConfig("USE_FACEBOOK_AUTHENTICATION_FOR_RIDERS", false)
 	defConfig("USE_GOOGLE_AUTHENTICATION_FOR_RIDERS", false)
@@ -257,4 +257,35 @@ var _ = Describe("SummonPlatform Defaults Component", func() {
 		// NOTE: This assertion will be removed when #269 is put back so DISPATCH_BASE_URL is always set.
 		Expect(instance.Spec.Config).ToNot(ContainElement("DISPATCH_BASE_URL"))
 	})
+
+	Describe("SetDefaults", func() {
+		It("leaves values derived from other fields unset so they can be persisted", func() {
+			summoncomponents.SetDefaults(instance)
+			Expect(instance.Spec.Replicas.Dispatch).To(PointTo(BeEquivalentTo(1)))
+			Expect(instance.Spec.Config).ToNot(HaveKey("DISPATCH_ENABLED"))
+			Expect(instance.Spec.Config).ToNot(HaveKey("DISPATCH_BASE_URL"))
+			for _, key := range []string{"WEB_URL", "ASGI_URL", "CACHE_URL", "AWS_REGION", "DATA_PIPELINE_SQS_QUEUE_NAME", "GATEWAY_BASE_URL"} {
+				Expect(instance.Spec.Config).ToNot(HaveKey(key))
+			}
+			Expect(instance.Spec.Config["TENANT_ID"].String).To(PointTo(Equal("foo-dev")))
+		})
+
+		It("follows changes to the aliases and Redis after defaulting", func() {
+			summoncomponents.SetDefaults(instance)
+			instance.Spec.Aliases = []string{"xyz.ridecell.com"}
+			instance.Spec.MigrationOverrides.RedisHostname = "awsredis"
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(instance.Spec.Config["WEB_URL"].String).To(PointTo(Equal("https://xyz.ridecell.com")))
+			Expect(instance.Spec.Config["ASGI_URL"].String).To(PointTo(Equal("redis://awsredis/1")))
+			Expect(instance.Spec.Config["CACHE_URL"].String).To(PointTo(Equal("redis://awsredis/1")))
+		})
+
+		It("doesn't block enabling a component later", func() {
+			summoncomponents.SetDefaults(instance)
+			instance.Spec.Dispatch.Version = "foo"
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(instance.Spec.Replicas.Dispatch).To(PointTo(BeEquivalentTo(1)))
+			Expect(instance.Spec.Config["DISPATCH_ENABLED"].Bool).To(PointTo(BeTrue()))
+		})
+	})
 })
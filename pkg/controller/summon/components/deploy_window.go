@@ -0,0 +1,179 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	"github.com/Ridecell/ridecell-operator/pkg/schedule"
+)
+
+// Annotation with a version to deploy right away, regardless of the deploy window. Only applies
+// while it matches Spec.Version, so it doesn't need cleaning up after an emergency.
+const DeployOverrideAnnotation = "summon.ridecell.io/deploy-override"
+
+type deployWindowComponent struct{}
+
+func NewDeployWindow() *deployWindowComponent {
+	return &deployWindowComponent{}
+}
+
+func (_ *deployWindowComponent) WatchTypes() []runtime.Object {
+	return []runtime.Object{}
+}
+
+func (_ *deployWindowComponent) IsReconcilable(_ *components.ComponentContext) bool {
+	return true
+}
+
+// Holds a new Spec.Version at the backup and migration gate outside the deploy window by putting
+// the current version back in memory, like autodeploy does, so every later component keeps the
+// instance as it is. A deploy which already got past the gate is left to finish.
+func (comp *deployWindowComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	version := instance.Spec.Version
+	current := instance.Status.MigrateVersion
+
+	// Only a version change which hasn't been backed up yet can be held, never the first deploy.
+	starting := current != "" && version != current && version != instance.Status.BackupVersion
+	if instance.Spec.DeployWindow == nil || !starting {
+		return components.Result{StatusModifier: comp.statusModifier(summonv1beta1.DeployHoldStatus{})}, nil
+	}
+	if instance.Annotations[DeployOverrideAnnotation] == version {
+		if instance.Status.DeployHold.Version == version {
+			ctx.Eventf(corev1.EventTypeWarning, "DeployOverridden", "Deploying held version %s because of the %s annotation", version, DeployOverrideAnnotation)
+		}
+		return components.Result{StatusModifier: comp.statusModifier(summonv1beta1.DeployHoldStatus{})}, nil
+	}
+
+	reason, until, err := deployHold(instance.Spec.DeployWindow, time.Now())
+	if err != nil {
+		return components.Result{}, errors.Permanent(err)
+	}
+	if reason == "" {
+		return components.Result{StatusModifier: comp.statusModifier(summonv1beta1.DeployHoldStatus{})}, nil
+	}
+
+	hold := summonv1beta1.DeployHoldStatus{Version: version, Reason: reason}
+	if !until.IsZero() {
+		hold.Until = until.Format(time.RFC3339)
+	}
+	if hold != instance.Status.DeployHold {
+		ctx.Eventf(corev1.EventTypeNormal, "DeployHeld", "Holding version %s on %s: %s", version, current, reason)
+	}
+	instance.Spec.Version = current
+
+	result := components.Result{StatusModifier: comp.statusModifier(hold)}
+	if !until.IsZero() {
+		result.RequeueAfter = time.Until(until)
+	}
+	return result, nil
+}
+
+func (comp *deployWindowComponent) statusModifier(hold summonv1beta1.DeployHoldStatus) components.StatusModifier {
+	return func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.DeployHold = hold
+		return nil
+	}
+}
+
+// Check if a deploy can start at t. If not, returns why and when it can, or a zero time if the
+// windows don't open again within the schedule's horizon.
+func deployHold(spec *summonv1beta1.DeployWindowSpec, t time.Time) (string, time.Time, error) {
+	windows, freezes, err := deployWindowSchedule(spec)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	reason := ""
+	// Skip past freezes and closed windows until neither applies. Bounded in case they never line up.
+	for i := 0; i < 10; i++ {
+		moved := false
+		for _, freeze := range freezes {
+			if !t.Before(freeze.start) && t.Before(freeze.end) {
+				if reason == "" {
+					reason = fmt.Sprintf("deploy freeze %s", freeze.name)
+				}
+				t = freeze.end
+				moved = true
+			}
+		}
+		if windows != nil {
+			open, next := windows.Awake(t)
+			if !open {
+				if reason == "" {
+					reason = "outside the deploy window"
+				}
+				if next.IsZero() {
+					return reason, next, nil
+				}
+				t = next
+				moved = true
+			}
+		}
+		if !moved {
+			return reason, t, nil
+		}
+	}
+	return reason, time.Time{}, nil
+}
+
+type deployFreeze struct {
+	name       string
+	start, end time.Time
+}
+
+// Parse a DeployWindowSpec. The schedule is nil if deploys are allowed at any time outside the
+// freezes. Also used by Validate.
+func deployWindowSchedule(spec *summonv1beta1.DeployWindowSpec) (schedule.Schedule, []deployFreeze, error) {
+	var windows schedule.Schedule
+	if len(spec.AllowedWindows) != 0 {
+		loc, err := timeZone(spec.TimeZone)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "deploy window")
+		}
+		windows, err = schedule.ParseWindows(spec.AllowedWindows, loc)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "deploy window")
+		}
+	}
+
+	freezes := []deployFreeze{}
+	for _, freeze := range spec.Freezes {
+		start, err := time.Parse(time.RFC3339, freeze.Start)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "deploy window: invalid start for freeze %#v", freeze.Name)
+		}
+		end, err := time.Parse(time.RFC3339, freeze.End)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "deploy window: invalid end for freeze %#v", freeze.Name)
+		}
+		if !end.After(start) {
+			return nil, nil, errors.Errorf("deploy window: freeze %#v ends before it starts", freeze.Name)
+		}
+		freezes = append(freezes, deployFreeze{name: freeze.Name, start: start, end: end})
+	}
+	return windows, freezes, nil
+}
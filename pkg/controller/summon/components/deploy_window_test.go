@@ -0,0 +1,124 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
+)
+
+var _ = Describe("SummonPlatform DeployWindow Component", func() {
+	comp := summoncomponents.NewDeployWindow()
+
+	// A freeze around the current time, whenever the tests run.
+	at := func(d time.Duration) string {
+		return time.Now().Add(d).UTC().Format(time.RFC3339)
+	}
+	var freeze summonv1beta1.DeployFreeze
+
+	BeforeEach(func() {
+		comp = summoncomponents.NewDeployWindow()
+		freeze = summonv1beta1.DeployFreeze{Name: "Holidays", Start: at(-time.Hour), End: at(time.Hour)}
+		instance.Status.MigrateVersion = "1.2.2"
+		instance.Status.BackupVersion = "1.2.2"
+	})
+
+	It("does nothing without a deploy window", func() {
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Spec.Version).To(Equal("1.2.3"))
+		Expect(instance.Status.DeployHold).To(Equal(summonv1beta1.DeployHoldStatus{}))
+	})
+
+	It("lets a deploy through inside the window", func() {
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{AllowedWindows: []string{"00:00-24:00"}}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Spec.Version).To(Equal("1.2.3"))
+		Expect(instance.Status.DeployHold).To(Equal(summonv1beta1.DeployHoldStatus{}))
+	})
+
+	It("holds a new version during a freeze", func() {
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{
+			AllowedWindows: []string{"00:00-24:00"},
+			Freezes:        []summonv1beta1.DeployFreeze{freeze},
+		}
+		res, err := comp.Reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		Expect(instance.Spec.Version).To(Equal("1.2.2"))
+		Expect(instance.Status.DeployHold).To(Equal(summonv1beta1.DeployHoldStatus{Version: "1.2.3", Reason: "deploy freeze Holidays", Until: freeze.End}))
+		Expect(res.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	})
+
+	It("holds through back to back freezes", func() {
+		next := summonv1beta1.DeployFreeze{Name: "New Year", Start: freeze.End, End: at(2 * time.Hour)}
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{Freezes: []summonv1beta1.DeployFreeze{next, freeze}}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.DeployHold).To(Equal(summonv1beta1.DeployHoldStatus{Version: "1.2.3", Reason: "deploy freeze Holidays", Until: next.End}))
+	})
+
+	It("holds a new version outside the window", func() {
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{AllowedWindows: []string{"Mon 00:00-00:01"}}
+		now := time.Now().UTC()
+		if now.Weekday() == time.Monday && now.Hour() == 0 && now.Minute() == 0 {
+			Skip("inside the window")
+		}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Spec.Version).To(Equal("1.2.2"))
+		Expect(instance.Status.DeployHold.Reason).To(Equal("outside the deploy window"))
+		until, err := time.Parse(time.RFC3339, instance.Status.DeployHold.Until)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(until.Weekday()).To(Equal(time.Monday))
+	})
+
+	It("never holds the first deploy", func() {
+		instance.Status.MigrateVersion = ""
+		instance.Status.BackupVersion = ""
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{Freezes: []summonv1beta1.DeployFreeze{freeze}}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Spec.Version).To(Equal("1.2.3"))
+		Expect(instance.Status.DeployHold).To(Equal(summonv1beta1.DeployHoldStatus{}))
+	})
+
+	It("lets a deploy which already started finish", func() {
+		instance.Status.BackupVersion = "1.2.3"
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{Freezes: []summonv1beta1.DeployFreeze{freeze}}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Spec.Version).To(Equal("1.2.3"))
+	})
+
+	It("deploys right away with the override annotation", func() {
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{Freezes: []summonv1beta1.DeployFreeze{freeze}}
+		instance.Status.DeployHold = summonv1beta1.DeployHoldStatus{Version: "1.2.3", Reason: "deploy freeze Holidays", Until: freeze.End}
+		instance.Annotations = map[string]string{summoncomponents.DeployOverrideAnnotation: "1.2.3"}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Spec.Version).To(Equal("1.2.3"))
+		Expect(instance.Status.DeployHold).To(Equal(summonv1beta1.DeployHoldStatus{}))
+	})
+
+	It("ignores an override for a different version", func() {
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{Freezes: []summonv1beta1.DeployFreeze{freeze}}
+		instance.Annotations = map[string]string{summoncomponents.DeployOverrideAnnotation: "1.2.1"}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Spec.Version).To(Equal("1.2.2"))
+	})
+})
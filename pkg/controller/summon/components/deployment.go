@@ -22,9 +22,10 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -32,6 +33,7 @@ import (
 	secretsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/secrets/v1beta1"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
 )
 
 type deploymentComponent struct {
@@ -52,6 +54,10 @@ func (comp *deploymentComponent) WatchTypes() []runtime.Object {
 
 func (comp *deploymentComponent) IsReconcilable(ctx *components.ComponentContext) bool {
 	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	// Maintenance mode scales down the workers whatever else is going on.
+	if instance.Spec.Maintenance.Enabled {
+		return true
+	}
 	// Check on the pull secret. Not technically needed in some cases, but just wait.
 	if instance.Status.PullSecretStatus != secretsv1beta1.StatusReady {
 		return false
@@ -72,47 +78,27 @@ func (comp *deploymentComponent) IsReconcilable(ctx *components.ComponentContext
 func (comp *deploymentComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
 
-	// If we're not in deploying state do nothing and exit early.
+	// If we're not in deploying state do nothing and exit early, other than the maintenance scale down.
 	if instance.Status.Status != summonv1beta1.StatusDeploying {
+		if instance.Spec.Maintenance.Enabled {
+			return components.Result{}, comp.scaleDown(ctx)
+		}
 		return components.Result{}, nil
 	}
 
-	// TODO 2020-01-06 After cm+secret merges to just secret, support varying the input names in the component config so comp-dispatch and comp-trip-share can get just the hash of their config.
-	rawAppSecret := &corev1.Secret{}
-	err := ctx.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s.app-secrets", instance.Name), Namespace: instance.Namespace}, rawAppSecret)
-	if err != nil {
-		return components.Result{Requeue: true}, errors.Wrapf(err, "deployment: Failed to get appsecrets")
-	}
-
-	config := &corev1.ConfigMap{}
-	err = ctx.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s-config", instance.Name), Namespace: instance.Namespace}, config)
-	if err != nil {
-		return components.Result{Requeue: true}, errors.Wrapf(err, "deployment: unable to get configmap")
-	}
-
-	appSecretsBytes, err := json.Marshal(rawAppSecret.Data)
-	if err != nil {
-		return components.Result{}, errors.Wrapf(err, "deployment: unable to serialize appsecrets")
-	}
-	configBytes, err := json.Marshal(config.Data)
+	// Data to be copied over to template
+	extra, err := deploymentData(ctx)
 	if err != nil {
-		return components.Result{}, errors.Wrapf(err, "deployment: unable to serialize config")
+		return components.Result{}, err
 	}
 
-	appSecretsHash := comp.hashItem(appSecretsBytes)
-	configMapHash := comp.hashItem(configBytes)
-
-	// Data to be copied over to template
-	extra := map[string]interface{}{}
-	extra["configHash"] = string(configMapHash)
-	extra["appSecretsHash"] = string(appSecretsHash)
-
 	res, _, err := ctx.CreateOrUpdate(comp.templatePath, extra, func(goalObj, existingObj runtime.Object) error {
 		goalDeployment, ok := goalObj.(*appsv1.Deployment)
 		if ok {
 			existing := existingObj.(*appsv1.Deployment)
-			// Check if autoscaling was enabled and keep existing deployment replicas setting set by HPA
-			if comp.isAutoscaled != nil && comp.isAutoscaled(instance) {
+			// Check if autoscaling was enabled and keep existing deployment replicas setting set by HPA.
+			// The HPA won't scale up from zero, like after maintenance mode, so use the template then.
+			if comp.isAutoscaled != nil && comp.isAutoscaled(instance) && existing.Spec.Replicas != nil && *existing.Spec.Replicas > 0 {
 				goalDeployment.Spec.Replicas = existing.Spec.Replicas
 			}
 			existing.Spec = goalDeployment.Spec
@@ -130,7 +116,112 @@ func (comp *deploymentComponent) Reconcile(ctx *components.ComponentContext) (co
 	return components.Result{}, nil
 }
 
-func (_ *deploymentComponent) hashItem(data []byte) string {
+// Scale the existing object to zero if the template does, without touching the rest of its spec.
+// Used for maintenance mode outside of a deploy, where applying the whole template could start
+// rolling out a version whose migrations haven't run yet. Only the replicas are read from the
+// template, so it's rendered without the hashes and the secrets don't have to exist yet.
+func (comp *deploymentComponent) scaleDown(ctx *components.ComponentContext) error {
+	goalObj, err := ctx.GetTemplate(comp.templatePath, nil)
+	if err != nil {
+		return errors.Wrapf(err, "deployment: failed to render template %s", comp.templatePath)
+	}
+
+	var goalReplicas *int32
+	var existingObj runtime.Object
+	switch goal := goalObj.(type) {
+	case *appsv1.Deployment:
+		goalReplicas = goal.Spec.Replicas
+		existingObj = &appsv1.Deployment{}
+	case *appsv1.StatefulSet:
+		goalReplicas = goal.Spec.Replicas
+		existingObj = &appsv1.StatefulSet{}
+	default:
+		return nil
+	}
+	if goalReplicas == nil || *goalReplicas != 0 {
+		return nil
+	}
+
+	goalMeta := goalObj.(metav1.Object)
+	err = ctx.Get(ctx.Context, types.NamespacedName{Name: goalMeta.GetName(), Namespace: goalMeta.GetNamespace()}, existingObj)
+	if kerrors.IsNotFound(err) {
+		// Nothing running to scale down.
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "deployment: failed to get %s", goalMeta.GetName())
+	}
+
+	var replicas **int32
+	switch existing := existingObj.(type) {
+	case *appsv1.Deployment:
+		replicas = &existing.Spec.Replicas
+	case *appsv1.StatefulSet:
+		replicas = &existing.Spec.Replicas
+	}
+	if *replicas != nil && **replicas == 0 {
+		return nil
+	}
+	*replicas = goalReplicas
+	err = ctx.Update(ctx.Context, existingObj)
+	if err != nil {
+		return errors.Wrapf(err, "deployment: failed to scale down %s", goalMeta.GetName())
+	}
+	return nil
+}
+
+// Template data for the Deployments, the hashes plus the version to run if this one was rolled back.
+func deploymentData(ctx *components.ComponentContext) (map[string]interface{}, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	extra, err := deploymentHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rolledBack(instance) {
+		extra["version"] = instance.Status.Rollback.Version
+	}
+	return extra, nil
+}
+
+// Hash the app secrets and config into template data, so pods restart when either changes.
+// Shared with the canary component so canary pods get the same annotations.
+func deploymentHashes(ctx *components.ComponentContext) (map[string]interface{}, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+
+	// TODO 2020-01-06 After cm+secret merges to just secret, support varying the input names in the component config so comp-dispatch and comp-trip-share can get just the hash of their config.
+	rawAppSecret := &corev1.Secret{}
+	err := ctx.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s.app-secrets", instance.Name), Namespace: instance.Namespace}, rawAppSecret)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			err = errors.Waiting(err)
+		}
+		return nil, errors.Wrapf(err, "deployment: Failed to get appsecrets")
+	}
+
+	config := &corev1.ConfigMap{}
+	err = ctx.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s-config", instance.Name), Namespace: instance.Namespace}, config)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			err = errors.Waiting(err)
+		}
+		return nil, errors.Wrapf(err, "deployment: unable to get configmap")
+	}
+
+	appSecretsBytes, err := json.Marshal(rawAppSecret.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "deployment: unable to serialize appsecrets")
+	}
+	configBytes, err := json.Marshal(config.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "deployment: unable to serialize config")
+	}
+
+	extra := map[string]interface{}{}
+	extra["configHash"] = hashItem(configBytes)
+	extra["appSecretsHash"] = hashItem(appSecretsBytes)
+	return extra, nil
+}
+
+func hashItem(data []byte) string {
 	hash := sha1.Sum(data)
 	encodedHash := hex.EncodeToString(hash[:])
 	return encodedHash
@@ -93,6 +93,29 @@ var _ = Describe("deployment Component", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("deploys the old version after a rollback", func() {
+		comp := summoncomponents.NewDeployment("web/deployment.yml.tpl", nil)
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{FailedVersion: "1.2.3", Version: "1.2.2"}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-config", instance.Name), Namespace: instance.Namespace},
+			Data:       map[string]string{"summon-platform.yml": "{}\n"},
+		}
+		appSecrets := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s.app-secrets", instance.Name), Namespace: instance.Namespace},
+			Data:       map[string][]byte{"filler": []byte("test")},
+		}
+
+		ctx.Client = fake.NewFakeClient(appSecrets, configMap)
+		Expect(comp).To(ReconcileContext(ctx))
+
+		deployment := &appsv1.Deployment{}
+		err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-web", Namespace: instance.Namespace}, deployment)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Labels["app.kubernetes.io/version"]).To(Equal("1.2.2"))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("us.gcr.io/ridecell-1/summon:1.2.2"))
+	})
+
 	It("makes sure keys are sorted before hash", func() {
 		comp := summoncomponents.NewDeployment("static/deployment.yml.tpl", nil)
 
@@ -334,6 +357,73 @@ var _ = Describe("deployment Component", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(target.Spec.Replicas).Should(PointTo(BeEquivalentTo(int32(2))))
 		})
+
+		It("scales to zero in maintenance mode and back up afterwards", func() {
+			comp := summoncomponents.NewDeployment("celeryd/deployment.yml.tpl", func(s *summonv1beta1.SummonPlatform) bool {
+				return *s.Spec.Replicas.CelerydAuto && !s.Spec.Maintenance.Enabled
+			})
+			bValue := true
+			instance.Spec.Replicas.CelerydAuto = &bValue
+			instance.Spec.Replicas.Celeryd = intp(1)
+			instance.Spec.Maintenance.Enabled = true
+			ctx.Client = fake.NewFakeClient(appSecrets, configMap)
+			Expect(comp).To(ReconcileContext(ctx))
+
+			target := &appsv1.Deployment{}
+			err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-celeryd", Namespace: instance.Namespace}, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Spec.Replicas).To(PointTo(BeEquivalentTo(0)))
+
+			// The HPA can't scale up from zero, so the template's count is used.
+			instance.Spec.Maintenance.Enabled = false
+			Expect(comp).To(ReconcileContext(ctx))
+			err = ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-celeryd", Namespace: instance.Namespace}, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Spec.Replicas).To(PointTo(BeEquivalentTo(1)))
+		})
+
+		It("scales to zero in maintenance mode outside of a deploy", func() {
+			instance.Status.Status = summonv1beta1.StatusMigrating
+			instance.Spec.Maintenance.Enabled = true
+			replicas := int32(3)
+			existing := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-dev-celeryd", Namespace: instance.Namespace},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "default", Image: "old"}},
+						},
+					},
+				},
+			}
+			ctx.Client = fake.NewFakeClient(appSecrets, configMap, existing)
+			Expect(comp.IsReconcilable(ctx)).To(BeTrue())
+			Expect(comp).To(ReconcileContext(ctx))
+
+			target := &appsv1.Deployment{}
+			err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-celeryd", Namespace: instance.Namespace}, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Spec.Replicas).To(PointTo(BeEquivalentTo(0)))
+			// Only the replicas change, the new version waits for the deploy.
+			Expect(target.Spec.Template.Spec.Containers[0].Image).To(Equal("old"))
+		})
+
+		It("leaves the workers alone outside of a deploy without maintenance mode", func() {
+			instance.Status.Status = summonv1beta1.StatusMigrating
+			replicas := int32(3)
+			existing := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-dev-celeryd", Namespace: instance.Namespace},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			}
+			ctx.Client = fake.NewFakeClient(appSecrets, configMap, existing)
+			Expect(comp).To(ReconcileContext(ctx))
+
+			target := &appsv1.Deployment{}
+			err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-celeryd", Namespace: instance.Namespace}, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Spec.Replicas).To(PointTo(BeEquivalentTo(3)))
+		})
 	})
 
 	Context("Tests the metric flags", func() {
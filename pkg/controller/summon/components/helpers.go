@@ -17,12 +17,34 @@ limitations under the License.
 package components
 
 import (
+	"time"
+
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 )
 
+// Check if the current Spec.Version was rolled back, in which case the Deployments run
+// Status.Rollback.Version instead.
+func rolledBack(instance *summonv1beta.SummonPlatform) bool {
+	return instance.Status.Rollback.FailedVersion != "" && instance.Status.Rollback.FailedVersion == instance.Spec.Version
+}
+
+// Load a time zone by name for the schedules, defaulting to UTC.
+func timeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid time zone %#v", name)
+	}
+	return loc, nil
+}
+
 // Helper function for use as a StatusModifier which just sets the main status.
 func setStatus(status string) components.StatusModifier {
 	return func(obj runtime.Object) error {
@@ -31,3 +53,13 @@ func setStatus(status string) components.StatusModifier {
 		return nil
 	}
 }
+
+// Helper function for use as a StatusModifier which sets the main status and one condition together.
+func setStatusAndCondition(status string, conditionType string, conditionStatus conditions.ConditionStatus, reason, message string) components.StatusModifier {
+	return func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta.SummonPlatform)
+		instance.Status.Status = status
+		components.SetCondition(instance, conditionType, conditionStatus, reason, message)
+		return nil
+	}
+}
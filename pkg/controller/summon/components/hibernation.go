@@ -0,0 +1,160 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	"github.com/Ridecell/ridecell-operator/pkg/schedule"
+)
+
+// Namespace annotation with a JSON HibernationSpec for every instance in it without its own.
+const HibernationAnnotation = "summon.ridecell.io/hibernation"
+
+type hibernationComponent struct{}
+
+func NewHibernation() *hibernationComponent {
+	return &hibernationComponent{}
+}
+
+func (_ *hibernationComponent) WatchTypes() []runtime.Object {
+	return []runtime.Object{}
+}
+
+func (_ *hibernationComponent) IsReconcilable(_ *components.ComponentContext) bool {
+	return true
+}
+
+// Scales the instance down outside its schedule by zeroing Spec.Replicas in memory, so every
+// later component renders with no pods. Requeues for the next wake or sleep.
+func (comp *hibernationComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+
+	spec, err := comp.hibernationSpec(ctx)
+	if err != nil {
+		return components.Result{}, err
+	}
+	if spec == nil {
+		return components.Result{StatusModifier: comp.statusModifier(summonv1beta1.HibernationStatus{})}, nil
+	}
+	sched, err := hibernationSchedule(spec)
+	if err != nil {
+		return components.Result{}, errors.Permanent(err)
+	}
+
+	now := time.Now()
+	awake, next := sched.Awake(now)
+	wakeUntil, err := time.Parse(time.RFC3339, instance.Annotations[summonv1beta1.WakeUntilAnnotation])
+	if err == nil && now.Before(wakeUntil) {
+		// Woken on demand.
+		if !awake || next.IsZero() || wakeUntil.Before(next) {
+			next = wakeUntil
+		}
+		awake = true
+	}
+
+	status := summonv1beta1.HibernationStatus{Asleep: !awake}
+	if !awake {
+		sleepReplicas(instance)
+		if !next.IsZero() {
+			status.NextWake = next.Format(time.RFC3339)
+		}
+	}
+	if status.Asleep != instance.Status.Hibernation.Asleep {
+		if status.Asleep {
+			ctx.Eventf(corev1.EventTypeNormal, "Hibernating", "Scaling down until %s", status.NextWake)
+		} else {
+			ctx.Eventf(corev1.EventTypeNormal, "WokeUp", "Scaling back up")
+		}
+	}
+
+	result := components.Result{StatusModifier: comp.statusModifier(status)}
+	if !next.IsZero() {
+		result.RequeueAfter = time.Until(next)
+	}
+	return result, nil
+}
+
+// Find the schedule for this instance, from its own spec or its namespace. Hibernation only ever
+// applies to dev and QA.
+func (comp *hibernationComponent) hibernationSpec(ctx *components.ComponentContext) (*summonv1beta1.HibernationSpec, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	if instance.Spec.Environment != "dev" && instance.Spec.Environment != "qa" {
+		return nil, nil
+	}
+	if instance.Spec.Hibernation != nil {
+		return instance.Spec.Hibernation, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	err := ctx.Get(ctx.Context, types.NamespacedName{Name: instance.Namespace}, namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "hibernation: failed to get namespace %s", instance.Namespace)
+	}
+	raw, ok := namespace.Annotations[HibernationAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	spec := &summonv1beta1.HibernationSpec{}
+	err = json.Unmarshal([]byte(raw), spec)
+	if err != nil {
+		return nil, errors.Permanent(errors.Wrapf(err, "hibernation: invalid %s annotation on namespace %s", HibernationAnnotation, instance.Namespace))
+	}
+	return spec, nil
+}
+
+func (comp *hibernationComponent) statusModifier(status summonv1beta1.HibernationStatus) components.StatusModifier {
+	return func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Hibernation = status
+		return nil
+	}
+}
+
+// Build the schedule for a HibernationSpec. Also used by Validate.
+func hibernationSchedule(spec *summonv1beta1.HibernationSpec) (schedule.Schedule, error) {
+	loc, err := timeZone(spec.TimeZone)
+	if err != nil {
+		return nil, errors.Wrap(err, "hibernation")
+	}
+	cron := spec.WakeSchedule != "" || spec.SleepSchedule != ""
+	if cron && len(spec.AwakeWindows) != 0 {
+		return nil, errors.New("hibernation: use either awakeWindows or wakeSchedule and sleepSchedule, not both")
+	}
+	if cron {
+		if spec.WakeSchedule == "" || spec.SleepSchedule == "" {
+			return nil, errors.New("hibernation: wakeSchedule and sleepSchedule must be set together")
+		}
+		return schedule.ParseCron(spec.WakeSchedule, spec.SleepSchedule, loc)
+	}
+	if len(spec.AwakeWindows) == 0 {
+		return nil, errors.New("hibernation: no awakeWindows or wakeSchedule and sleepSchedule set")
+	}
+	return schedule.ParseWindows(spec.AwakeWindows, loc)
+}
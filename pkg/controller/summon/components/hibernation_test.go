@@ -0,0 +1,128 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
+)
+
+var _ = Describe("SummonPlatform Hibernation Component", func() {
+	comp := summoncomponents.NewHibernation()
+
+	// Schedules which are always asleep or awake right now, whatever the time the tests run.
+	asleep := &summonv1beta1.HibernationSpec{WakeSchedule: "0 0 1 1 *", SleepSchedule: "* * * * *"}
+	awake := &summonv1beta1.HibernationSpec{AwakeWindows: []string{"00:00-24:00"}}
+
+	BeforeEach(func() {
+		comp = summoncomponents.NewHibernation()
+		summoncomponents.SetDefaults(instance)
+		ctx.Client = fake.NewFakeClient()
+	})
+
+	It("does nothing without a schedule", func() {
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeFalse())
+		Expect(instance.Spec.Replicas.Web).To(PointTo(BeEquivalentTo(1)))
+	})
+
+	It("stays awake inside the schedule", func() {
+		instance.Spec.Hibernation = awake
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeFalse())
+		Expect(instance.Spec.Replicas.Web).To(PointTo(BeEquivalentTo(1)))
+	})
+
+	It("scales everything to zero outside the schedule", func() {
+		instance.Spec.Hibernation = asleep
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeTrue())
+		Expect(instance.Spec.Replicas.Web).To(PointTo(BeEquivalentTo(0)))
+		Expect(instance.Spec.Replicas.CeleryBeat).To(PointTo(BeEquivalentTo(0)))
+		Expect(instance.Spec.Replicas.CelerydAuto).To(PointTo(BeFalse()))
+	})
+
+	It("reports when it will next wake", func() {
+		instance.Spec.Hibernation = &summonv1beta1.HibernationSpec{AwakeWindows: []string{"Mon 00:00-00:01"}}
+		now := time.Now().UTC()
+		if now.Weekday() == time.Monday && now.Hour() == 0 && now.Minute() == 0 {
+			Skip("inside the window")
+		}
+		res, err := comp.Reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		nextWake, err := time.Parse(time.RFC3339, instance.Status.Hibernation.NextWake)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextWake.Weekday()).To(Equal(time.Monday))
+		Expect(res.RequeueAfter).To(BeNumerically("~", time.Until(nextWake), time.Minute))
+	})
+
+	It("wakes up on demand", func() {
+		instance.Spec.Hibernation = asleep
+		instance.Annotations = map[string]string{summonv1beta1.WakeUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeFalse())
+		Expect(instance.Spec.Replicas.Web).To(PointTo(BeEquivalentTo(1)))
+	})
+
+	It("goes back to sleep after a wake up expires", func() {
+		instance.Spec.Hibernation = asleep
+		instance.Annotations = map[string]string{summonv1beta1.WakeUntilAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339)}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeTrue())
+	})
+
+	It("uses the namespace schedule", func() {
+		ctx.Client = fake.NewFakeClient(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "summon-dev",
+				Annotations: map[string]string{summoncomponents.HibernationAnnotation: `{"wakeSchedule": "0 0 1 1 *", "sleepSchedule": "* * * * *"}`},
+			},
+		})
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeTrue())
+	})
+
+	It("lets the instance schedule override the namespace", func() {
+		instance.Spec.Hibernation = awake
+		ctx.Client = fake.NewFakeClient(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "summon-dev",
+				Annotations: map[string]string{summoncomponents.HibernationAnnotation: `{"wakeSchedule": "0 0 1 1 *", "sleepSchedule": "* * * * *"}`},
+			},
+		})
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeFalse())
+	})
+
+	It("never hibernates prod", func() {
+		instance.Spec.Environment = "prod"
+		instance.Spec.Hibernation = asleep
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Hibernation.Asleep).To(BeFalse())
+	})
+})
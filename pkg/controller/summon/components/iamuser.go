@@ -18,7 +18,6 @@ package components
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 
 	"github.com/pkg/errors"
@@ -51,7 +50,7 @@ func (_ *iamUserComponent) IsReconcilable(_ *components.ComponentContext) bool {
 func (comp *iamUserComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
 
-	permissionsBoundaryArn := os.Getenv("PERMISSIONS_BOUNDARY_ARN")
+	permissionsBoundaryArn := ctx.Config().Get("PERMISSIONS_BOUNDARY_ARN")
 	if permissionsBoundaryArn == "" {
 		return components.Result{}, errors.Errorf("iamuser: permissions_boundary_arn is empty")
 	}
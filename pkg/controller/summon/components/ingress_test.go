@@ -75,4 +75,20 @@ var _ = Describe("SummonPlatform ingress Component", func() {
 			Expect(target.Spec.Rules).To(ContainElement(vanityRule))
 		}
 	})
+
+	It("points the web, static and daphne ingresses at the maintenance page in maintenance mode", func() {
+		instance.Spec.Maintenance.Enabled = true
+		instance.Spec.Aliases = []string{"foo-1.ridecell.us"}
+		for _, part := range []string{"web", "static", "daphne"} {
+			comp := summoncomponents.NewIngress(part + "/ingress.yml.tpl")
+			Expect(comp).To(ReconcileContext(ctx))
+			target := &k8sv1beta1.Ingress{}
+			err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-" + part, Namespace: "summon-dev"}, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Spec.Rules).To(HaveLen(2))
+			for _, rule := range target.Spec.Rules {
+				Expect(rule.HTTP.Paths[0].Backend.ServiceName).To(Equal("foo-dev-maintenance"))
+			}
+		}
+	})
 })
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// Annotation naming who turned on maintenance mode, copied into Status.Maintenance.RequestedBy.
+const MaintenanceRequestedByAnnotation = "summon.ridecell.io/maintenance-requested-by"
+
+type maintenanceComponent struct{}
+
+func NewMaintenance() *maintenanceComponent {
+	return &maintenanceComponent{}
+}
+
+func (_ *maintenanceComponent) WatchTypes() []runtime.Object {
+	return []runtime.Object{
+		&appsv1.Deployment{},
+		&corev1.ConfigMap{},
+	}
+}
+
+func (_ *maintenanceComponent) IsReconcilable(_ *components.ComponentContext) bool {
+	// Maintenance has to work even when the rest of the instance is broken.
+	return true
+}
+
+// Runs the maintenance page while Spec.Maintenance is enabled. The templates render empty
+// otherwise, so the page is pruned when maintenance ends. Pointing the ingresses at it and
+// scaling down the workers is done in their own templates, outside of a deploy too.
+func (comp *maintenanceComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+
+	res, _, err := ctx.CreateOrUpdate("maintenance/configmap.yml.tpl", nil, func(goalObj, existingObj runtime.Object) error {
+		goal := goalObj.(*corev1.ConfigMap)
+		existing := existingObj.(*corev1.ConfigMap)
+		existing.Data = goal.Data
+		return nil
+	})
+	if err != nil {
+		return res, errors.Wrap(err, "maintenance: failed to update configmap")
+	}
+	res, _, err = ctx.CreateOrUpdate("maintenance/deployment.yml.tpl", nil, func(goalObj, existingObj runtime.Object) error {
+		goal := goalObj.(*appsv1.Deployment)
+		existing := existingObj.(*appsv1.Deployment)
+		existing.Spec = goal.Spec
+		return nil
+	})
+	if err != nil {
+		return res, errors.Wrap(err, "maintenance: failed to update deployment")
+	}
+
+	maintenance := instance.Status.Maintenance
+	if !instance.Spec.Maintenance.Enabled {
+		if maintenance.Started == "" {
+			return components.Result{}, nil
+		}
+		ctx.Eventf(corev1.EventTypeNormal, "MaintenanceEnded", "Maintenance mode ended")
+		return components.Result{StatusModifier: func(obj runtime.Object) error {
+			instance := obj.(*summonv1beta1.SummonPlatform)
+			instance.Status.Maintenance = summonv1beta1.MaintenanceStatus{}
+			return nil
+		}}, nil
+	}
+
+	requestedBy := instance.Annotations[MaintenanceRequestedByAnnotation]
+	if maintenance.Started == "" {
+		maintenance.Started = time.Now().Format(time.RFC3339)
+		ctx.Eventf(corev1.EventTypeNormal, "MaintenanceStarted", "Maintenance mode started%s", requestedBySuffix(requestedBy))
+	}
+	maintenance.RequestedBy = requestedBy
+	return components.Result{StatusModifier: func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Maintenance = maintenance
+		return nil
+	}}, nil
+}
+
+// Format the requester for messages, or nothing if the annotation wasn't set.
+func requestedBySuffix(requestedBy string) string {
+	if requestedBy == "" {
+		return ""
+	}
+	return fmt.Sprintf(" by %s", requestedBy)
+}
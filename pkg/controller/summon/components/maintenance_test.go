@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
+)
+
+var _ = Describe("SummonPlatform Maintenance Component", func() {
+	comp := summoncomponents.NewMaintenance()
+
+	BeforeEach(func() {
+		comp = summoncomponents.NewMaintenance()
+		summoncomponents.SetDefaults(instance)
+	})
+
+	It("does nothing when maintenance is off", func() {
+		Expect(comp).To(ReconcileContext(ctx))
+		deployment := &appsv1.Deployment{}
+		err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-maintenance", Namespace: "summon-dev"}, deployment)
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		Expect(instance.Status.Maintenance).To(Equal(summonv1beta1.MaintenanceStatus{}))
+	})
+
+	It("runs the maintenance page", func() {
+		instance.Spec.Maintenance.Enabled = true
+		instance.Spec.Maintenance.Message = "Back at <5pm>"
+		Expect(comp).To(ReconcileContext(ctx))
+
+		deployment := &appsv1.Deployment{}
+		err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-maintenance", Namespace: "summon-dev"}, deployment)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("foo-dev-maintenance"))
+		// subPath mounts never see ConfigMap updates.
+		for _, mount := range deployment.Spec.Template.Spec.Containers[0].VolumeMounts {
+			Expect(mount.SubPath).To(BeEmpty(), mount.Name)
+		}
+
+		configMap := &corev1.ConfigMap{}
+		err = ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-maintenance", Namespace: "summon-dev"}, configMap)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Contains(configMap.Data["index.html"], "Back at &lt;5pm&gt;")).To(BeTrue())
+	})
+
+	It("records when maintenance started and who asked for it", func() {
+		instance.Spec.Maintenance.Enabled = true
+		instance.Annotations = map[string]string{summoncomponents.MaintenanceRequestedByAnnotation: "jane"}
+		Expect(comp).To(ReconcileContext(ctx))
+		started := instance.Status.Maintenance.Started
+		Expect(started).ToNot(BeEmpty())
+		Expect(instance.Status.Maintenance.RequestedBy).To(Equal("jane"))
+
+		// The start time doesn't move on later reconciles.
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Maintenance.Started).To(Equal(started))
+	})
+
+	It("clears the status when maintenance ends", func() {
+		instance.Status.Maintenance = summonv1beta1.MaintenanceStatus{Started: "2019-01-01T00:00:00Z", RequestedBy: "jane"}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Maintenance).To(Equal(summonv1beta1.MaintenanceStatus{}))
+	})
+})
@@ -23,9 +23,9 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	secretsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/secrets/v1beta1"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
@@ -77,9 +78,14 @@ func (comp *migrationComponent) Reconcile(ctx *components.ComponentContext) (com
 		return components.Result{}, nil
 	}
 
+	if rolledBack(instance) {
+		// Don't retry migrations for a version which was rolled back.
+		return components.Result{}, nil
+	}
+
 	if instance.Spec.Version == instance.Status.MigrateVersion {
 		// Already migrated, update status and move on.
-		return components.Result{StatusModifier: setStatus(summonv1beta1.StatusDeploying)}, nil
+		return components.Result{StatusModifier: setStatusAndCondition(summonv1beta1.StatusDeploying, summonv1beta1.ConditionMigrationsComplete, conditions.ConditionTrue, "MigrationsSucceeded", fmt.Sprintf("Migrations complete for version %s", instance.Spec.Version))}, nil
 	}
 
 	var urlStr string
@@ -111,7 +117,7 @@ func (comp *migrationComponent) Reconcile(ctx *components.ComponentContext) (com
 	existing := &batchv1.Job{}
 	err = ctx.Get(ctx.Context, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, existing)
 	if err != nil && kerrors.IsNotFound(err) {
-		glog.Infof("Creating migration Job %s/%s\n", job.Namespace, job.Name)
+		ctx.Logger().Info("Creating migration Job", "jobNamespace", job.Namespace, "jobName", job.Name)
 		err = controllerutil.SetControllerReference(instance, job, ctx.Scheme)
 		if err != nil {
 			return components.Result{}, err
@@ -123,7 +129,8 @@ func (comp *migrationComponent) Reconcile(ctx *components.ComponentContext) (com
 			return components.Result{Requeue: true}, errors.Wrapf(err, "migrations: error creation migration job %s/%s, might have lost the race condition", job.Namespace, job.Name)
 		}
 		// Job is started, so we're done for now.
-		return components.Result{StatusModifier: setStatus(summonv1beta1.StatusMigrating)}, nil
+		ctx.Eventf(corev1.EventTypeNormal, "MigrationStarted", "Started migration job %s for version %s", job.Name, instance.Spec.Version)
+		return components.Result{StatusModifier: setStatusAndCondition(summonv1beta1.StatusMigrating, summonv1beta1.ConditionMigrationsComplete, conditions.ConditionFalse, "MigrationsRunning", fmt.Sprintf("Migration job %s started for version %s", job.Name, instance.Spec.Version))}, nil
 	} else if err != nil {
 		// Some other real error, bail.
 		return components.Result{}, err
@@ -133,7 +140,7 @@ func (comp *migrationComponent) Reconcile(ctx *components.ComponentContext) (com
 	// First make sure we even care about this job, it only counts if it's for the version we want.
 	existingVersion, ok := existing.Labels["app.kubernetes.io/version"]
 	if !ok || existingVersion != instance.Spec.Version {
-		glog.Infof("[%s/%s] migrations: Found existing migration job with bad version %#v\n", instance.Namespace, instance.Name, existingVersion)
+		ctx.Logger().Info("Found existing migration Job with bad version", "jobNamespace", existing.Namespace, "jobName", existing.Name, "jobVersion", existingVersion)
 		// This is from a bad (or broken if !ok) version, try to delete it and then run again.
 		err = ctx.Delete(ctx.Context, existing, client.PropagationPolicy(metav1.DeletePropagationBackground))
 		return components.Result{Requeue: true}, errors.Wrapf(err, "migrations: found existing migration job %s/%s with bad version %#v", instance.Namespace, instance.Name, existingVersion)
@@ -142,13 +149,14 @@ func (comp *migrationComponent) Reconcile(ctx *components.ComponentContext) (com
 	// Check if the job succeeded.
 	if existing.Status.Succeeded > 0 {
 		// Success! Update the MigrateVersion (this will trigger a reconcile) and delete the job.
-		glog.V(2).Infof("[%s/%s] Deleting migration Job %s/%s\n", instance.Namespace, instance.Name, existing.Namespace, existing.Name)
+		ctx.Logger().V(2).Info("Deleting migration Job", "jobNamespace", existing.Namespace, "jobName", existing.Name)
 		err = ctx.Delete(ctx.Context, existing, client.PropagationPolicy(metav1.DeletePropagationBackground))
 		if err != nil {
 			return components.Result{Requeue: true}, errors.Wrapf(err, "migrations: error deleting successful migration job %s/%s", existing.Namespace, existing.Name)
 		}
 
-		glog.Infof("[%s/%s] migrations: Migration job succeeded, updating MigrateVersion from %s to %s\n", instance.Namespace, instance.Name, instance.Status.MigrateVersion, instance.Spec.Version)
+		ctx.Logger().Info("Migration Job succeeded, updating MigrateVersion", "from", instance.Status.MigrateVersion, "to", instance.Spec.Version)
+		ctx.Eventf(corev1.EventTypeNormal, "MigrationSucceeded", "Migrations for version %s completed", instance.Spec.Version)
 		// Store migrate version in the closure to avoid concurrent edits to Spec.Version resulting in incorrectly advancing MigrateVersion.
 		migrateVersion := instance.Spec.Version
 		// Onward to deploying!
@@ -156,6 +164,7 @@ func (comp *migrationComponent) Reconcile(ctx *components.ComponentContext) (com
 			instance := obj.(*summonv1beta1.SummonPlatform)
 			instance.Status.Status = summonv1beta1.StatusPostMigrateWait
 			instance.Status.MigrateVersion = migrateVersion
+			components.SetCondition(instance, summonv1beta1.ConditionMigrationsComplete, conditions.ConditionTrue, "MigrationsSucceeded", fmt.Sprintf("Migrations complete for version %s", migrateVersion))
 			return nil
 		}}, nil
 	}
@@ -163,10 +172,13 @@ func (comp *migrationComponent) Reconcile(ctx *components.ComponentContext) (com
 	// ... Or if the job failed.
 	if existing.Status.Failed > 0 {
 		// If it was an outdated job, we would have already deleted it, so this means it's a failed migration for the current version.
-		glog.Errorf("[%s/%s] Migration job failed, leaving job %s/%s for debugging purposes\n", instance.Namespace, instance.Name, existing.Namespace, existing.Name)
-		return components.Result{}, errors.Errorf("migrations: migration job %s/%s failed", existing.Namespace, existing.Name)
+		ctx.Logger().Info("Migration Job failed, leaving it for debugging purposes", "jobNamespace", existing.Namespace, "jobName", existing.Name)
+		ctx.Eventf(corev1.EventTypeWarning, "MigrationFailed", "Migration job %s for version %s failed", existing.Name, instance.Spec.Version)
+		return components.Result{
+			StatusModifier: components.ConditionModifier(summonv1beta1.ConditionMigrationsComplete, conditions.ConditionFalse, "MigrationsFailed", fmt.Sprintf("Migration job %s failed for version %s", existing.Name, instance.Spec.Version)),
+		}, errors.Errorf("migrations: migration job %s/%s failed", existing.Namespace, existing.Name)
 	}
 
 	// Job is still running, will get reconciled when it finishes.
-	return components.Result{StatusModifier: setStatus(summonv1beta1.StatusMigrating)}, nil
+	return components.Result{StatusModifier: setStatusAndCondition(summonv1beta1.StatusMigrating, summonv1beta1.ConditionMigrationsComplete, conditions.ConditionFalse, "MigrationsRunning", fmt.Sprintf("Migration job %s running for version %s", existing.Name, instance.Spec.Version))}, nil
 }
@@ -24,13 +24,16 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
 	secretsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/secrets/v1beta1"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
 	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
 )
@@ -109,6 +112,15 @@ var _ = Describe("SummonPlatform Migrations Component", func() {
 				err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-migrations", Namespace: "summon-dev"}, job)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(instance.Status.MigrateVersion).To(Equal(""))
+				Expect(ctx.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal("Normal MigrationStarted Started migration job foo-dev-migrations for version 1.2.3")))
+			})
+
+			It("does not migrate a rolled back version", func() {
+				instance.Status.Rollback = summonv1beta1.RollbackStatus{FailedVersion: "1.2.3", Version: "1.2.2"}
+				Expect(comp).To(ReconcileContext(ctx))
+				job := &batchv1.Job{}
+				err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-migrations", Namespace: "summon-dev"}, job)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
 			})
 
 			It("checks template info for a presigned url", func() {
@@ -173,6 +185,7 @@ var _ = Describe("SummonPlatform Migrations Component", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(jobs.Items).To(BeEmpty())
 				Expect(instance.Status.MigrateVersion).To(Equal("1.2.3"))
+				Expect(ctx.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal("Normal MigrationSucceeded Migrations for version 1.2.3 completed")))
 			})
 		})
 
@@ -197,6 +210,7 @@ var _ = Describe("SummonPlatform Migrations Component", func() {
 				err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-migrations", Namespace: "summon-dev"}, job)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(instance.Status.MigrateVersion).To(Equal(""))
+				Expect(ctx.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal("Warning MigrationFailed Migration job foo-dev-migrations for version 1.2.3 failed")))
 			})
 		})
 
@@ -18,11 +18,11 @@ package components
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -32,6 +32,7 @@ import (
 
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 	"github.com/Ridecell/ridecell-operator/pkg/errors"
 )
 
@@ -51,19 +52,19 @@ func init() {
 // Interface for a Slack client to allow for a mock implementation.
 //go:generate moq -out zz_generated.mock_slackclient_test.go . SlackClient
 type SlackClient interface {
-	PostMessage(string, slack.Attachment) (string, string, error)
+	PostMessage(context.Context, string, slack.Attachment) (string, string, error)
 }
 
 // Real implementation of SlackClient using nlopes/slack.
 // I can't match the interface to that directly because the MsgOptions API involves
 // private structs so I can't actually get the back out the other side when working with a mock.
-type realSlackClient struct {
-	client *slack.Client
-}
+// Uses the current SLACK_API_KEY for every message, so a rotated key is picked up without a restart.
+type realSlackClient struct{}
 
-func (c *realSlackClient) PostMessage(channel string, msg slack.Attachment) (string, string, error) {
-	if c.client != nil {
-		return c.client.PostMessage(channel, slack.MsgOptionAttachments(msg))
+func (c *realSlackClient) PostMessage(ctx context.Context, channel string, msg slack.Attachment) (string, string, error) {
+	slackApiKey := config.Get("SLACK_API_KEY")
+	if slackApiKey != "" {
+		return slack.New(slackApiKey).PostMessageContext(ctx, channel, slack.MsgOptionAttachments(msg))
 	} else {
 		return "", "", nil
 	}
@@ -72,13 +73,13 @@ func (c *realSlackClient) PostMessage(channel string, msg slack.Attachment) (str
 // Interface for Deployment status client
 //go:generate moq -out zz_generated.mock_deploystatusclient_test.go . DeployStatusClient
 type DeployStatusClient interface {
-	PostStatus(url string, name string, env string, tag string) error
+	PostStatus(ctx context.Context, url string, name string, env string, tag string) error
 }
 
 type realDeployStatusClient struct{}
 
 // Real implementation of PostStatus for deployStatusTool
-func (c *realDeployStatusClient) PostStatus(url string, name string, env string, tag string) error {
+func (c *realDeployStatusClient) PostStatus(ctx context.Context, url string, name string, env string, tag string) error {
 	if url == "" {
 		return nil
 	}
@@ -95,7 +96,12 @@ func (c *realDeployStatusClient) PostStatus(url string, name string, env string,
 		return err
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(postJson))
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(postJson))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -118,14 +124,8 @@ type notificationComponent struct {
 }
 
 func NewNotification() *notificationComponent {
-	var slackClient *slack.Client
-	slackApiKey := os.Getenv("SLACK_API_KEY")
-	if slackApiKey != "" {
-		slackClient = slack.New(slackApiKey)
-	}
-
 	return &notificationComponent{
-		slackClient:        &realSlackClient{client: slackClient},
+		slackClient:        &realSlackClient{},
 		deployStatusClient: &realDeployStatusClient{},
 	}
 }
@@ -149,10 +149,25 @@ func (_ *notificationComponent) IsReconcilable(_ *components.ComponentContext) b
 func (c *notificationComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
 	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
 
-	if instance.Status.Status == summonv1beta1.StatusReady {
-		return c.handleSuccess(instance)
+	canary := instance.Status.Canary
+	if canary.Phase != "" && (canary.Version != instance.Status.Notification.CanaryVersion || canary.Phase != instance.Status.Notification.CanaryPhase) {
+		return c.handleCanary(ctx, instance)
+	}
+
+	rollback := instance.Status.Rollback
+	if rollback.FailedVersion != "" && rollback.FailedVersion != instance.Status.Notification.RolledBackVersion {
+		return c.handleRollback(ctx, instance)
+	}
+
+	if instance.Status.Maintenance.Started != instance.Status.Notification.MaintenanceStarted {
+		return c.handleMaintenance(ctx, instance)
+	}
+
+	// A held deploy is still ready on its current version.
+	if instance.Status.Status == summonv1beta1.StatusReady || instance.Status.Status == summonv1beta1.StatusDeployHeld {
+		return c.handleSuccess(ctx, instance)
 	} else if instance.Status.Status == summonv1beta1.StatusError {
-		return c.handleError(instance, instance.Status.Message)
+		return c.handleError(ctx, instance, instance.Status.Message)
 	}
 
 	// No notifications needed.
@@ -165,41 +180,41 @@ func (c *notificationComponent) ReconcileError(ctx *components.ComponentContext,
 		return components.Result{}, nil
 	}
 	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
-	return c.handleError(instance, fmt.Sprintf("%s", err))
+	return c.handleError(ctx, instance, fmt.Sprintf("%s", err))
 }
 
 // Checks each summon component and send a deploy notification if needed.
-func (c *notificationComponent) handleSuccess(instance *summonv1beta1.SummonPlatform) (components.Result, error) {
+func (c *notificationComponent) handleSuccess(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform) (components.Result, error) {
 
 	// Accumulate errors to be dealt with at the end so no component notifications
 	// are blocked on another's error.
 	var errs error
 	if instance.Spec.Version != instance.Status.Notification.SummonVersion {
-		err := c.notifyAndPostStatus(instance, CompSummonStr, instance.Spec.Version)
+		err := c.notifyAndPostStatus(ctx, instance, CompSummonStr, instance.Spec.Version)
 		if err != nil {
 			errs = err
 		}
 	}
 	if instance.Spec.Dispatch.Version != instance.Status.Notification.DispatchVersion {
-		err := c.notifyAndPostStatus(instance, CompDispatchStr, instance.Spec.Dispatch.Version)
+		err := c.notifyAndPostStatus(ctx, instance, CompDispatchStr, instance.Spec.Dispatch.Version)
 		if err != nil {
 			errs = fmt.Errorf("%s; %s", errs, err)
 		}
 	}
 	if instance.Spec.BusinessPortal.Version != instance.Status.Notification.BusinessPortalVersion {
-		err := c.notifyAndPostStatus(instance, CompBusinessPortalStr, instance.Spec.BusinessPortal.Version)
+		err := c.notifyAndPostStatus(ctx, instance, CompBusinessPortalStr, instance.Spec.BusinessPortal.Version)
 		if err != nil {
 			errs = fmt.Errorf("%s; %s", errs, err)
 		}
 	}
 	if instance.Spec.HwAux.Version != instance.Status.Notification.HwAuxVersion {
-		err := c.notifyAndPostStatus(instance, CompHwAuxStr, instance.Spec.HwAux.Version)
+		err := c.notifyAndPostStatus(ctx, instance, CompHwAuxStr, instance.Spec.HwAux.Version)
 		if err != nil {
 			errs = fmt.Errorf("%s; %s", errs, err)
 		}
 	}
 	if instance.Spec.TripShare.Version != instance.Status.Notification.TripShareVersion {
-		err := c.notifyAndPostStatus(instance, CompTripShareStr, instance.Spec.TripShare.Version)
+		err := c.notifyAndPostStatus(ctx, instance, CompTripShareStr, instance.Spec.TripShare.Version)
 		if err != nil {
 			errs = fmt.Errorf("%s; %s", errs, err)
 		}
@@ -221,7 +236,7 @@ func (c *notificationComponent) handleSuccess(instance *summonv1beta1.SummonPlat
 		}}, nil
 }
 
-func (c *notificationComponent) notifyAndPostStatus(instance *summonv1beta1.SummonPlatform, component string, version string) error {
+func (c *notificationComponent) notifyAndPostStatus(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform, component string, version string) error {
 
 	// Check if this is a duplicate slipping through due to concurrency.
 	dupCacheKey := fmt.Sprintf("%s/%s-%s", instance.Namespace, instance.Name, component)
@@ -234,7 +249,7 @@ func (c *notificationComponent) notifyAndPostStatus(instance *summonv1beta1.Summ
 	// Send to Slack.
 	if instance.Spec.Notifications.SlackChannel != "" {
 		attachment := c.formatSuccessNotification(instance, component, version)
-		_, _, err := c.slackClient.PostMessage(instance.Spec.Notifications.SlackChannel, attachment)
+		_, _, err := c.slackClient.PostMessage(ctx.Context, instance.Spec.Notifications.SlackChannel, attachment)
 		if err != nil {
 			return err
 		}
@@ -243,7 +258,7 @@ func (c *notificationComponent) notifyAndPostStatus(instance *summonv1beta1.Summ
 	// Send to additional slack channels.
 	for _, channel := range instance.Spec.Notifications.SlackChannels {
 		attachment := c.formatSuccessNotification(instance, component, version)
-		_, _, err := c.slackClient.PostMessage(channel, attachment)
+		_, _, err := c.slackClient.PostMessage(ctx.Context, channel, attachment)
 		if err != nil {
 			return err
 		}
@@ -256,11 +271,11 @@ func (c *notificationComponent) notifyAndPostStatus(instance *summonv1beta1.Summ
 		instanceName = instanceName + " " + component
 	}
 
-	deploymentStatusUrl := os.Getenv("DEPLOY_STAT_URL")
+	deploymentStatusUrl := ctx.Config().Get("DEPLOY_STAT_URL")
 	if instance.Spec.Notifications.DeploymentStatusUrl != "" {
 		deploymentStatusUrl = instance.Spec.Notifications.DeploymentStatusUrl
 	}
-	err := c.deployStatusClient.PostStatus(deploymentStatusUrl, instanceName, instance.Spec.Environment, version)
+	err := c.deployStatusClient.PostStatus(ctx.Context, deploymentStatusUrl, instanceName, instance.Spec.Environment, version)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("notifications: error posting to deployment-status for %s", instanceName))
 	}
@@ -268,8 +283,63 @@ func (c *notificationComponent) notifyAndPostStatus(instance *summonv1beta1.Summ
 	return nil
 }
 
+// Send a notification for each phase of a canary rollout.
+func (c *notificationComponent) handleCanary(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform) (components.Result, error) {
+	canary := instance.Status.Canary
+	return c.notifyStatusChange(ctx, instance, "canary", fmt.Sprintf("%s %s", canary.Version, canary.Phase), c.formatCanaryNotification, func(status *summonv1beta1.NotificationStatus) {
+		status.CanaryVersion = canary.Version
+		status.CanaryPhase = canary.Phase
+	})
+}
+
+// Send a notification when a version is rolled back.
+func (c *notificationComponent) handleRollback(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform) (components.Result, error) {
+	rollback := instance.Status.Rollback
+	return c.notifyStatusChange(ctx, instance, "rollback", fmt.Sprintf("ROLLBACK %s", rollback.FailedVersion), c.formatRollbackNotification, func(status *summonv1beta1.NotificationStatus) {
+		status.RolledBackVersion = rollback.FailedVersion
+	})
+}
+
+// Send a notification when maintenance mode starts or ends.
+func (c *notificationComponent) handleMaintenance(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform) (components.Result, error) {
+	started := instance.Status.Maintenance.Started
+	return c.notifyStatusChange(ctx, instance, "maintenance", fmt.Sprintf("MAINTENANCE %s", started), c.formatMaintenanceNotification, func(status *summonv1beta1.NotificationStatus) {
+		status.MaintenanceStarted = started
+	})
+}
+
+// Send a notification about a status change to every Slack channel, then record it in
+// Status.Notification with the given func so it isn't sent again. The kind and value identify
+// the change for the duplicate check.
+func (c *notificationComponent) notifyStatusChange(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform, kind string, value string, format func(*summonv1beta1.SummonPlatform) slack.Attachment, record func(*summonv1beta1.NotificationStatus)) (components.Result, error) {
+	// Check if this is a duplicate slipping through due to concurrency.
+	dupCacheKey := fmt.Sprintf("%s/%s-%s", instance.Namespace, instance.Name, kind)
+	lastdupCacheValue, ok := c.dupCache.Load(dupCacheKey)
+	if !ok || lastdupCacheValue != value {
+		// Send to Slack.
+		channels := instance.Spec.Notifications.SlackChannels
+		if instance.Spec.Notifications.SlackChannel != "" {
+			channels = append([]string{instance.Spec.Notifications.SlackChannel}, channels...)
+		}
+		for _, channel := range channels {
+			_, _, err := c.slackClient.PostMessage(ctx.Context, channel, format(instance))
+			if err != nil {
+				return components.Result{}, err
+			}
+		}
+		c.dupCache.Store(dupCacheKey, value)
+	}
+
+	return components.Result{
+		StatusModifier: func(obj runtime.Object) error {
+			instance := obj.(*summonv1beta1.SummonPlatform)
+			record(&instance.Status.Notification)
+			return nil
+		}}, nil
+}
+
 // Send an error notification if needed.
-func (c *notificationComponent) handleError(instance *summonv1beta1.SummonPlatform, errorMessage string) (components.Result, error) {
+func (c *notificationComponent) handleError(ctx *components.ComponentContext, instance *summonv1beta1.SummonPlatform, errorMessage string) (components.Result, error) {
 	// Check if this is a duplicate message.
 	dupCacheKey := fmt.Sprintf("%s/%s/%s", instance.Namespace, instance.Name, instance.Spec.Version)
 	lastdupCacheValue, ok := c.dupCache.Load(dupCacheKey)
@@ -287,7 +357,7 @@ func (c *notificationComponent) handleError(instance *summonv1beta1.SummonPlatfo
 	// Send to Slack.
 	if instance.Spec.Notifications.SlackChannel != "" {
 		attachment := c.formatErrorNotification(instance, errorMessage)
-		_, _, err := c.slackClient.PostMessage(instance.Spec.Notifications.SlackChannel, attachment)
+		_, _, err := c.slackClient.PostMessage(ctx.Context, instance.Spec.Notifications.SlackChannel, attachment)
 		if err != nil {
 			return components.Result{}, err
 		}
@@ -296,7 +366,7 @@ func (c *notificationComponent) handleError(instance *summonv1beta1.SummonPlatfo
 	// Send to additonal slack channels
 	for _, channel := range instance.Spec.Notifications.SlackChannels {
 		attachment := c.formatErrorNotification(instance, errorMessage)
-		_, _, err := c.slackClient.PostMessage(channel, attachment)
+		_, _, err := c.slackClient.PostMessage(ctx.Context, channel, attachment)
 		if err != nil {
 			return components.Result{}, err
 		}
@@ -342,6 +412,64 @@ func (comp *notificationComponent) formatSuccessNotification(instance *summonv1b
 	}
 }
 
+// Render the notification attachment for a canary phase change.
+func (comp *notificationComponent) formatCanaryNotification(instance *summonv1beta1.SummonPlatform) slack.Attachment {
+	canary := instance.Status.Canary
+	color := "#439FE0"
+	var text string
+	switch canary.Phase {
+	case summonv1beta1.CanaryPhaseProgressing:
+		text = fmt.Sprintf("started a canary of %s version %s alongside %s", CompSummonStr, canary.Version, canary.StableVersion)
+	case summonv1beta1.CanaryPhaseBaking:
+		text = fmt.Sprintf("canary of %s version %s is ready, baking for %s", CompSummonStr, canary.Version, instance.Spec.Canary.BakeTime.Duration)
+	case summonv1beta1.CanaryPhasePromoted:
+		color = "good"
+		text = fmt.Sprintf("promoted the canary of %s version %s, rolling out to all pods", CompSummonStr, canary.Version)
+	case summonv1beta1.CanaryPhaseAborted:
+		color = "danger"
+		text = fmt.Sprintf("aborted the canary of %s version %s and stayed on %s: %s", CompSummonStr, canary.Version, canary.StableVersion, canary.Message)
+	}
+
+	return slack.Attachment{
+		Title:     fmt.Sprintf("%s %s Canary", instance.Spec.Hostname, CompSummonStr),
+		TitleLink: fmt.Sprintf("https://%s/", instance.Spec.Hostname),
+		Color:     color,
+		Text:      fmt.Sprintf("<https://%s/|%s> %s", instance.Spec.Hostname, instance.Spec.Hostname, text),
+		Fallback:  fmt.Sprintf("%s %s", instance.Spec.Hostname, text),
+	}
+}
+
+// Render the notification attachment for a rollback.
+func (comp *notificationComponent) formatRollbackNotification(instance *summonv1beta1.SummonPlatform) slack.Attachment {
+	rollback := instance.Status.Rollback
+	text := fmt.Sprintf("rolled back %s version %s to %s: %s", CompSummonStr, rollback.FailedVersion, rollback.Version, rollback.Reason)
+	return slack.Attachment{
+		Title:     fmt.Sprintf("%s %s Rollback", instance.Spec.Hostname, CompSummonStr),
+		TitleLink: fmt.Sprintf("https://%s/", instance.Spec.Hostname),
+		Color:     "warning",
+		Text:      fmt.Sprintf("<https://%s/|%s> %s", instance.Spec.Hostname, instance.Spec.Hostname, text),
+		Fallback:  fmt.Sprintf("%s %s", instance.Spec.Hostname, text),
+	}
+}
+
+// Render the notification attachment for the start or end of maintenance mode.
+func (comp *notificationComponent) formatMaintenanceNotification(instance *summonv1beta1.SummonPlatform) slack.Attachment {
+	maintenance := instance.Status.Maintenance
+	color := "good"
+	text := "is out of maintenance mode"
+	if maintenance.Started != "" {
+		color = "warning"
+		text = fmt.Sprintf("is in maintenance mode%s", requestedBySuffix(maintenance.RequestedBy))
+	}
+	return slack.Attachment{
+		Title:     fmt.Sprintf("%s Maintenance", instance.Spec.Hostname),
+		TitleLink: fmt.Sprintf("https://%s/", instance.Spec.Hostname),
+		Color:     color,
+		Text:      fmt.Sprintf("<https://%s/|%s> %s", instance.Spec.Hostname, instance.Spec.Hostname, text),
+		Fallback:  fmt.Sprintf("%s %s", instance.Spec.Hostname, text),
+	}
+}
+
 // Render the nofiication attachement for an error notification.
 func (comp *notificationComponent) formatErrorNotification(instance *summonv1beta1.SummonPlatform, errorMessage string) slack.Attachment {
 	return slack.Attachment{
@@ -17,6 +17,7 @@ limitations under the License.
 package components_test
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -37,7 +38,7 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 	BeforeEach(func() {
 		comp = summoncomponents.NewNotification()
 		mockedSlackClient = &summoncomponents.SlackClientMock{
-			PostMessageFunc: func(_ string, _ slack.Attachment) (string, string, error) {
+			PostMessageFunc: func(_ context.Context, _ string, _ slack.Attachment) (string, string, error) {
 				return "", "", nil
 			},
 		}
@@ -48,7 +49,7 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 		instance.Spec.Environment = "dev"
 
 		mockedDeployStatusClient = &summoncomponents.DeployStatusClientMock{
-			PostStatusFunc: func(_, _, _, _ string) error {
+			PostStatusFunc: func(_ context.Context, _, _, _, _ string) error {
 				return nil
 			},
 		}
@@ -91,10 +92,10 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(comp).To(ReconcileContext(ctx))
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(1))
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
-			Expect(post.In2.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
+			Expect(post.In3.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
 			Expect(instance.Status.Notification.SummonVersion).To(Equal("1234-eb6b515-master"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(1))
 			deployPost := mockedDeployStatusClient.PostStatusCalls()[0]
@@ -112,10 +113,10 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(2))
 			// Notifies for summon platform.
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
-			Expect(post.In2.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
+			Expect(post.In3.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
 			Expect(instance.Status.Notification.SummonVersion).To(Equal("1234-eb6b515-master"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(2))
 			deployPost := mockedDeployStatusClient.PostStatusCalls()[0]
@@ -124,10 +125,10 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(deployPost.Tag).To(Equal("1234-eb6b515-master"))
 			//Notifies for TripShare.
 			post = mockedSlackClient.PostMessageCalls()[1]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us comp-trip-share Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us deployed comp-trip-share version 123-ababcdc-tripshare-master successfully"))
-			Expect(post.In2.Fields[0].Value).To(Equal("<https://github.com/Ridecell/comp-trip-share/tree/ababcdc|ababcdc>"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us comp-trip-share Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us deployed comp-trip-share version 123-ababcdc-tripshare-master successfully"))
+			Expect(post.In3.Fields[0].Value).To(Equal("<https://github.com/Ridecell/comp-trip-share/tree/ababcdc|ababcdc>"))
 			Expect(instance.Status.Notification.TripShareVersion).To(Equal("123-ababcdc-tripshare-master"))
 			deployPost = mockedDeployStatusClient.PostStatusCalls()[1]
 			Expect(deployPost.Name).To(Equal("foo comp-trip-share"))
@@ -144,10 +145,10 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(1))
 			// Notifies for summon platform.
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us comp-hw-aux Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us deployed comp-hw-aux version 123-cdcdababa-hwaux-master successfully"))
-			Expect(post.In2.Fields[0].Value).To(Equal("<https://github.com/Ridecell/comp-hw-aux/tree/cdcdababa|cdcdababa>"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us comp-hw-aux Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us deployed comp-hw-aux version 123-cdcdababa-hwaux-master successfully"))
+			Expect(post.In3.Fields[0].Value).To(Equal("<https://github.com/Ridecell/comp-hw-aux/tree/cdcdababa|cdcdababa>"))
 			Expect(instance.Status.Notification.HwAuxVersion).To(Equal("123-cdcdababa-hwaux-master"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(1))
 			deployPost := mockedDeployStatusClient.PostStatusCalls()[0]
@@ -164,15 +165,15 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(comp).To(ReconcileContext(ctx))
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(2))
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
-			Expect(post.In2.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
+			Expect(post.In3.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
 			post2 := mockedSlackClient.PostMessageCalls()[1]
-			Expect(post2.In1).To(Equal("#test-channel-2"))
-			Expect(post2.In2.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
-			Expect(post2.In2.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
-			Expect(post2.In2.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
+			Expect(post2.In2).To(Equal("#test-channel-2"))
+			Expect(post2.In3.Title).To(Equal("foo.ridecell.us summon-platform Deployment"))
+			Expect(post2.In3.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234-eb6b515-master successfully"))
+			Expect(post2.In3.Fields[0].Value).To(Equal("<https://github.com/Ridecell/summon-platform/tree/eb6b515|eb6b515>"))
 			Expect(instance.Status.Notification.SummonVersion).To(Equal("1234-eb6b515-master"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(1))
 		})
@@ -202,8 +203,8 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(comp).To(ReconcileContext(ctx))
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(1))
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234 successfully"))
-			Expect(post.In2.Fields).To(HaveLen(0))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us deployed summon-platform version 1234 successfully"))
+			Expect(post.In3.Fields).To(HaveLen(0))
 			Expect(instance.Status.Notification.SummonVersion).To(Equal("1234"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(1))
 			deployPost := mockedDeployStatusClient.PostStatusCalls()[0]
@@ -220,12 +221,12 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(3))
 			for index, post := range mockedSlackClient.PostMessageCalls() {
 				if index == 0 {
-					Expect(post.In1).To(Equal("#test-channel"))
+					Expect(post.In2).To(Equal("#test-channel"))
 				} else {
-					Expect(post.In1).To(Equal("#test-channel-" + strconv.Itoa(index+1)))
+					Expect(post.In2).To(Equal("#test-channel-" + strconv.Itoa(index+1)))
 				}
-				Expect(post.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-				Expect(post.In2.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
+				Expect(post.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+				Expect(post.In3.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
 			}
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(0))
 		})
@@ -247,13 +248,13 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(comp).To(ReconcileContext(ctx))
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(2))
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
 			post2 := mockedSlackClient.PostMessageCalls()[1]
-			Expect(post2.In1).To(Equal("#test-channel"))
-			Expect(post2.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post2.In2.Fallback).To(Equal("foo.ridecell.us has error: You have no chance to survive"))
+			Expect(post2.In2).To(Equal("#test-channel"))
+			Expect(post2.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post2.In3.Fallback).To(Equal("foo.ridecell.us has error: You have no chance to survive"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(0))
 		})
 
@@ -267,13 +268,13 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(comp).To(ReconcileContext(ctx))
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(2))
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us has error: I thought what i'd do was i'd pretend"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us has error: I thought what i'd do was i'd pretend"))
 			post2 := mockedSlackClient.PostMessageCalls()[1]
-			Expect(post2.In1).To(Equal("#test-channel"))
-			Expect(post2.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post2.In2.Fallback).To(Equal("foo.ridecell.us has error: I thought what i'd do was i'd pretend"))
+			Expect(post2.In2).To(Equal("#test-channel"))
+			Expect(post2.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post2.In3.Fallback).To(Equal("foo.ridecell.us has error: I thought what i'd do was i'd pretend"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(0))
 		})
 	})
@@ -283,9 +284,9 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			Expect(comp).To(ReconcileErrorContext(ctx, fmt.Errorf("Someone set us up the bomb")))
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(1))
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(0))
 		})
 
@@ -304,22 +305,77 @@ var _ = Describe("SummonPlatform Notification Component", func() {
 			// 2 errors to primary channel, 2 errors to additional channel
 			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(4))
 			post := mockedSlackClient.PostMessageCalls()[0]
-			Expect(post.In1).To(Equal("#test-channel"))
-			Expect(post.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post.In2.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
+			Expect(post.In2).To(Equal("#test-channel"))
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
 			post2 := mockedSlackClient.PostMessageCalls()[1]
-			Expect(post2.In1).To(Equal("#otherchannel"))
-			Expect(post2.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post2.In2.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
+			Expect(post2.In2).To(Equal("#otherchannel"))
+			Expect(post2.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post2.In3.Fallback).To(Equal("foo.ridecell.us has error: Someone set us up the bomb"))
 			post3 := mockedSlackClient.PostMessageCalls()[2]
-			Expect(post3.In1).To(Equal("#test-channel"))
-			Expect(post3.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post3.In2.Fallback).To(Equal("foo.ridecell.us has error: You have no chance to survive"))
+			Expect(post3.In2).To(Equal("#test-channel"))
+			Expect(post3.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post3.In3.Fallback).To(Equal("foo.ridecell.us has error: You have no chance to survive"))
 			post4 := mockedSlackClient.PostMessageCalls()[3]
-			Expect(post4.In1).To(Equal("#otherchannel"))
-			Expect(post4.In2.Title).To(Equal("foo.ridecell.us Deployment"))
-			Expect(post4.In2.Fallback).To(Equal("foo.ridecell.us has error: You have no chance to survive"))
+			Expect(post4.In2).To(Equal("#otherchannel"))
+			Expect(post4.In3.Title).To(Equal("foo.ridecell.us Deployment"))
+			Expect(post4.In3.Fallback).To(Equal("foo.ridecell.us has error: You have no chance to survive"))
 			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(0))
 		})
+
+		It("sends a notification for each canary phase", func() {
+			instance.Status.Status = summonv1beta1.StatusCanary
+			instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseProgressing, Version: "1.2.3", StableVersion: "1.2.2"}
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(1))
+			post := mockedSlackClient.PostMessageCalls()[0]
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us summon-platform Canary"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us started a canary of summon-platform version 1.2.3 alongside 1.2.2"))
+			Expect(instance.Status.Notification.CanaryPhase).To(Equal(summonv1beta1.CanaryPhaseProgressing))
+
+			instance.Status.Status = summonv1beta1.StatusCanaryAborted
+			instance.Status.Canary.Phase = summonv1beta1.CanaryPhaseAborted
+			instance.Status.Canary.Message = "canary pods stopped being ready"
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(2))
+			post = mockedSlackClient.PostMessageCalls()[1]
+			Expect(post.In3.Color).To(Equal("danger"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us aborted the canary of summon-platform version 1.2.3 and stayed on 1.2.2: canary pods stopped being ready"))
+			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(0))
+		})
+
+		It("sends one notification for a rollback", func() {
+			instance.Status.Status = summonv1beta1.StatusRolledBack
+			instance.Status.Rollback = summonv1beta1.RollbackStatus{FailedVersion: "1.2.3", Version: "1.2.2", Reason: "migrations failed for version 1.2.3"}
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(1))
+			post := mockedSlackClient.PostMessageCalls()[0]
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us summon-platform Rollback"))
+			Expect(post.In3.Color).To(Equal("warning"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us rolled back summon-platform version 1.2.3 to 1.2.2: migrations failed for version 1.2.3"))
+			Expect(instance.Status.Notification.RolledBackVersion).To(Equal("1.2.3"))
+			Expect(mockedDeployStatusClient.PostStatusCalls()).To(HaveLen(0))
+		})
+
+		It("sends a notification when maintenance starts and ends", func() {
+			instance.Status.Status = summonv1beta1.StatusReady
+			instance.Status.Maintenance = summonv1beta1.MaintenanceStatus{Started: "2019-01-01T00:00:00Z", RequestedBy: "jane"}
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(1))
+			post := mockedSlackClient.PostMessageCalls()[0]
+			Expect(post.In3.Title).To(Equal("foo.ridecell.us Maintenance"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us is in maintenance mode by jane"))
+
+			instance.Status.Maintenance = summonv1beta1.MaintenanceStatus{}
+			Expect(comp).To(ReconcileContext(ctx))
+			Expect(mockedSlackClient.PostMessageCalls()).To(HaveLen(2))
+			post = mockedSlackClient.PostMessageCalls()[1]
+			Expect(post.In3.Color).To(Equal("good"))
+			Expect(post.In3.Fallback).To(Equal("foo.ridecell.us is out of maintenance mode"))
+			Expect(instance.Status.Notification.MaintenanceStarted).To(Equal(""))
+		})
 	})
 })
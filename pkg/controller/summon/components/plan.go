@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"path"
+	"strings"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// Templates which need data only a real reconcile can get, like AWS account IDs or presigned URLs.
+var unplannableTemplates = map[string]string{
+	"aws/iamuser.yml.tpl":         "needs the AWS account ID",
+	"db/rdssnapshot.yml.tpl":      "only rendered during a backup",
+	"mockcarservertenant.yml.tpl": "needs the mock car server callback URL",
+}
+
+// Extra template data for planning a template, the same as its component passes during a
+// reconcile. Templates which can't be rendered outside a reconcile return an error wrapping
+// components.ErrPlanSkipped.
+func PlanData(ctx *components.ComponentContext, templatePath string) (map[string]interface{}, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+
+	reason, ok := unplannableTemplates[templatePath]
+	if ok {
+		return nil, errors.Wrap(components.ErrPlanSkipped, reason)
+	}
+
+	dir, file := path.Split(templatePath)
+	part := strings.TrimSuffix(dir, "/")
+	switch {
+	case templatePath == "configmap.yml.tpl":
+		return configMapData(instance)
+	case templatePath == "migrations.yml.tpl":
+		if instance.Spec.Flavor != "" {
+			return nil, errors.Wrap(components.ErrPlanSkipped, "needs a presigned URL for the flavor")
+		}
+		return map[string]interface{}{"presignedUrl": ""}, nil
+	case file == "canary.yml.tpl":
+		canary := instance.Status.Canary
+		if canary.Phase != summonv1beta1.CanaryPhaseProgressing && canary.Phase != summonv1beta1.CanaryPhaseBaking {
+			// No canary running, the template renders nothing.
+			return map[string]interface{}{}, nil
+		}
+		extra, err := planWaiting(deploymentHashes(ctx))
+		if err != nil {
+			return nil, err
+		}
+		extra["canary"] = true
+		extra["version"] = canary.Version
+		extra["canaryReplicas"] = canaryReplicas(instance, part)
+		return extra, nil
+	case (file == "deployment.yml.tpl" || file == "statefulset.yml.tpl") && part != "redis" && part != "maintenance":
+		// Redis and the maintenance page don't use the hashes, so they get no data like in their components.
+		return planWaiting(deploymentData(ctx))
+	}
+	return nil, nil
+}
+
+// Skip templates whose data depends on objects which don't exist yet, like the app secrets
+// in an empty cluster, instead of failing them.
+func planWaiting(extra map[string]interface{}, err error) (map[string]interface{}, error) {
+	if err != nil && errors.ClassOf(err) == errors.ClassWaiting {
+		return nil, errors.Wrap(components.ErrPlanSkipped, "needs the app secrets and config to exist")
+	}
+	return extra, err
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+)
+
+var _ = Describe("PlanData", func() {
+	BeforeEach(func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-config", instance.Name), Namespace: instance.Namespace},
+			Data:       map[string]string{"summon-platform.yml": "{}\n"},
+		}
+		appSecrets := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s.app-secrets", instance.Name), Namespace: instance.Namespace},
+			Data:       map[string][]byte{"test": []byte("test")},
+		}
+		ctx.Client = fake.NewFakeClient(instance, appSecrets, configMap)
+	})
+
+	It("renders the deployment hashes", func() {
+		extra, err := summoncomponents.PlanData(ctx, "web/deployment.yml.tpl")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extra["configHash"]).To(HaveLen(40))
+		Expect(extra["appSecretsHash"]).To(HaveLen(40))
+
+		change := ctx.Plan("web/deployment.yml.tpl", extra)
+		Expect(change.Err).ToNot(HaveOccurred())
+		Expect(change.Diff).ToNot(ContainSubstring("<no value>"))
+	})
+
+	It("renders the rolled back version", func() {
+		instance.Status.Rollback.FailedVersion = "1.2.3"
+		instance.Status.Rollback.Version = "1.2.2"
+		extra, err := summoncomponents.PlanData(ctx, "celeryd/deployment.yml.tpl")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extra["version"]).To(Equal("1.2.2"))
+	})
+
+	It("renders a running canary", func() {
+		instance.Spec.Replicas.Web = intp(4)
+		instance.Spec.Canary.Weight = 25
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Phase: summonv1beta1.CanaryPhaseBaking, Version: "1.2.4"}
+		extra, err := summoncomponents.PlanData(ctx, "web/canary.yml.tpl")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extra["canary"]).To(BeTrue())
+		Expect(extra["version"]).To(Equal("1.2.4"))
+		Expect(extra["canaryReplicas"]).To(BeEquivalentTo(1))
+
+		change := ctx.Plan("web/canary.yml.tpl", extra)
+		Expect(change.Err).ToNot(HaveOccurred())
+		Expect(change.Kind).To(Equal("Deployment"))
+		Expect(change.Name).To(Equal("foo-dev-web-canary"))
+	})
+
+	It("renders nothing for the canary without one running", func() {
+		extra, err := summoncomponents.PlanData(ctx, "web/canary.yml.tpl")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ctx.Plan("web/canary.yml.tpl", extra).Action).To(Equal(components.PlanEmpty))
+	})
+
+	It("renders the config", func() {
+		v := true
+		instance.Spec.Config = map[string]summonv1beta1.ConfigValue{"DEBUG": summonv1beta1.ConfigValue{Bool: &v}}
+		extra, err := summoncomponents.PlanData(ctx, "configmap.yml.tpl")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extra["SummonYaml"]).To(Equal(`{"DEBUG":true}`))
+	})
+
+	It("renders migrations without a flavor", func() {
+		extra, err := summoncomponents.PlanData(ctx, "migrations.yml.tpl")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extra).To(HaveKeyWithValue("presignedUrl", ""))
+	})
+
+	It("skips migrations with a flavor", func() {
+		instance.Spec.Flavor = "demo"
+		_, err := summoncomponents.PlanData(ctx, "migrations.yml.tpl")
+		Expect(errors.Cause(err)).To(Equal(components.ErrPlanSkipped))
+	})
+
+	It("skips templates which need AWS", func() {
+		_, err := summoncomponents.PlanData(ctx, "aws/iamuser.yml.tpl")
+		Expect(errors.Cause(err)).To(Equal(components.ErrPlanSkipped))
+	})
+
+	It("skips deployments until the app secrets exist", func() {
+		ctx.Client = fake.NewFakeClient(instance)
+		_, err := summoncomponents.PlanData(ctx, "web/deployment.yml.tpl")
+		Expect(errors.Cause(err)).To(Equal(components.ErrPlanSkipped))
+	})
+
+	It("passes nothing to templates which don't need it", func() {
+		extra, err := summoncomponents.PlanData(ctx, "redis/deployment.yml.tpl")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extra).To(BeNil())
+	})
+})
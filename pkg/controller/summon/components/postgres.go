@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
 	"github.com/pkg/errors"
@@ -53,6 +54,7 @@ func (comp *postgresComponent) Reconcile(ctx *components.ComponentContext) (comp
 	if existing != nil {
 		// If the database is in an error state, mark this summon as error'd too.
 		if existing.Status.Status == dbv1beta1.StatusError {
+			res.StatusModifier = components.ConditionModifier(summonv1beta1.ConditionDatabaseReady, conditions.ConditionFalse, existing.Status.Status, existing.Status.Message)
 			return res, errors.Errorf("postgres: %s", existing.Status.Message)
 		}
 		res.StatusModifier = func(obj runtime.Object) error {
@@ -62,6 +64,14 @@ func (comp *postgresComponent) Reconcile(ctx *components.ComponentContext) (comp
 			if existing.Status.Status != "" {
 				instance.Status.Status = summonv1beta1.StatusInitializing
 			}
+			switch existing.Status.Status {
+			case dbv1beta1.StatusReady:
+				components.SetCondition(instance, summonv1beta1.ConditionDatabaseReady, conditions.ConditionTrue, existing.Status.Status, "")
+			case "":
+				components.SetCondition(instance, summonv1beta1.ConditionDatabaseReady, conditions.ConditionUnknown, "Pending", "")
+			default:
+				components.SetCondition(instance, summonv1beta1.ConditionDatabaseReady, conditions.ConditionFalse, existing.Status.Status, existing.Status.Message)
+			}
 			return nil
 		}
 	}
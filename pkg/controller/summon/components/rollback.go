@@ -0,0 +1,173 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	secretsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/secrets/v1beta1"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+type rollbackComponent struct{}
+
+func NewRollback() *rollbackComponent {
+	return &rollbackComponent{}
+}
+
+func (_ *rollbackComponent) WatchTypes() []runtime.Object {
+	return []runtime.Object{
+		&batchv1.Job{},
+	}
+}
+
+func (_ *rollbackComponent) IsReconcilable(ctx *components.ComponentContext) bool {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	// Same requirements as migrations, since we look at the migration Job.
+	if instance.Status.PostgresStatus != dbv1beta1.StatusReady {
+		return false
+	}
+	if instance.Status.PullSecretStatus != secretsv1beta1.StatusReady {
+		return false
+	}
+	return true
+}
+
+// Watches each new version from the start of its deploy. If its migrations fail or it isn't ready
+// by the deadline, the Deployments go back to Status.LastReadyVersion until Spec.Version changes.
+func (comp *rollbackComponent) Reconcile(ctx *components.ComponentContext) (components.Result, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+	rollback := instance.Status.Rollback
+
+	if !instance.Spec.Rollback.Enabled {
+		if rollback == (summonv1beta1.RollbackStatus{}) {
+			return components.Result{}, nil
+		}
+		// Turning rollback off lets a rolled back version try again.
+		return components.Result{StatusModifier: func(obj runtime.Object) error {
+			instance := obj.(*summonv1beta1.SummonPlatform)
+			instance.Status.Rollback = summonv1beta1.RollbackStatus{}
+			return nil
+		}}, nil
+	}
+
+	// Stored outside the closures in case autodeploy changes Spec.Version, like in migrations.
+	version := instance.Spec.Version
+	if rolledBack(instance) {
+		// Already rolled back, skip migrations and keep deploying the old version.
+		return components.Result{StatusModifier: setStatus(summonv1beta1.StatusDeploying)}, nil
+	}
+
+	lastReadyVersion := instance.Status.LastReadyVersion
+	if lastReadyVersion == "" || lastReadyVersion == version {
+		// Nothing to go back to, or this version already made it.
+		return components.Result{}, nil
+	}
+
+	canary := instance.Status.Canary
+	if canary.Version == version && canary.Phase == summonv1beta1.CanaryPhaseAborted {
+		// Already back on the stable version, nothing to roll back.
+		return components.Result{}, nil
+	}
+
+	migrated := instance.Status.MigrateVersion == version
+	if rollback.DeployVersion != version || (rollback.DeployStarted == "" && migrated) {
+		// A new version, or its migrations just finished. The clock only starts once the Deployments
+		// start rolling, so a slow backup or migration can't run out the deadline.
+		started := ""
+		var requeue time.Duration
+		if migrated {
+			started = time.Now().Format(time.RFC3339)
+			requeue = instance.Spec.Rollback.DeployDeadline.Duration
+		}
+		return components.Result{
+			StatusModifier: func(obj runtime.Object) error {
+				instance := obj.(*summonv1beta1.SummonPlatform)
+				instance.Status.Rollback = summonv1beta1.RollbackStatus{DeployVersion: version, DeployStarted: started}
+				return nil
+			},
+			RequeueAfter: requeue,
+		}, nil
+	}
+
+	reason, migrationsFailed, requeue, err := comp.failure(ctx, rollback)
+	if err != nil || reason == "" {
+		return components.Result{RequeueAfter: requeue}, err
+	}
+
+	// A failed migration Job may have applied some of its migrations before failing.
+	if (migrated || migrationsFailed) && instance.Spec.Rollback.BackwardCompatibleMigrations != version {
+		// The old code might not work against the new schema, so this needs a human.
+		return components.Result{}, errors.Permanent(errors.Errorf("rollback: %s, not rolling back to %s because migrations for %s may have already run and are not marked backward compatible", reason, lastReadyVersion, version))
+	}
+
+	ctx.Eventf(corev1.EventTypeWarning, "RolledBack", "Rolled back from version %s to %s: %s", version, lastReadyVersion, reason)
+	return components.Result{StatusModifier: func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Rollback.FailedVersion = version
+		instance.Status.Rollback.Version = lastReadyVersion
+		instance.Status.Rollback.Reason = reason
+		instance.Status.Status = summonv1beta1.StatusDeploying
+		instance.Status.Message = fmt.Sprintf("Rolling back to version %s: %s", lastReadyVersion, reason)
+		return nil
+	}}, nil
+}
+
+// Work out if the version being deployed has failed. Returns the reason and whether it was the
+// migrations if so, otherwise how long until the deadline is worth checking again.
+func (comp *rollbackComponent) failure(ctx *components.ComponentContext, rollback summonv1beta1.RollbackStatus) (string, bool, time.Duration, error) {
+	instance := ctx.Top.(*summonv1beta1.SummonPlatform)
+
+	job := &batchv1.Job{}
+	err := ctx.Get(ctx.Context, types.NamespacedName{Name: fmt.Sprintf("%s-migrations", instance.Name), Namespace: instance.Namespace}, job)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return "", false, 0, errors.Wrap(err, "rollback: failed to get migration job")
+		}
+		job = nil
+	} else if job.Labels["app.kubernetes.io/version"] != rollback.DeployVersion {
+		// Left over from an older version, the migrations component will clean it up.
+		job = nil
+	}
+	if job != nil && job.Status.Failed > 0 {
+		return fmt.Sprintf("migrations failed for version %s", rollback.DeployVersion), true, 0, nil
+	}
+
+	if rollback.DeployStarted == "" {
+		// Still backing up or migrating. The Job watch will trigger a reconcile when that is done.
+		return "", false, 0, nil
+	}
+	started, err := time.Parse(time.RFC3339, rollback.DeployStarted)
+	if err != nil {
+		return "", false, 0, errors.Wrap(err, "rollback: failed to parse deploy start time")
+	}
+	remaining := time.Until(started.Add(instance.Spec.Rollback.DeployDeadline.Duration))
+	if remaining > 0 {
+		return "", false, remaining, nil
+	}
+	return fmt.Sprintf("version %s was not ready within %s", rollback.DeployVersion, instance.Spec.Rollback.DeployDeadline.Duration), false, 0, nil
+}
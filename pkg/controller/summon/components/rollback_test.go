@@ -0,0 +1,186 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	secretsv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/secrets/v1beta1"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
+)
+
+var _ = Describe("SummonPlatform Rollback Component", func() {
+	comp := summoncomponents.NewRollback()
+
+	// A migration Job for the given version.
+	migrationJob := func(version string, status batchv1.JobStatus) runtime.Object {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-dev-migrations",
+				Namespace: instance.Namespace,
+				Labels:    map[string]string{"app.kubernetes.io/version": version},
+			},
+			Status: status,
+		}
+	}
+
+	// Start the deploy of 1.2.3 the given time ago, after its migrations.
+	deployStarted := func(ago time.Duration) {
+		instance.Status.MigrateVersion = "1.2.3"
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{
+			DeployVersion: "1.2.3",
+			DeployStarted: time.Now().Add(-ago).Format(time.RFC3339),
+		}
+	}
+
+	BeforeEach(func() {
+		comp = summoncomponents.NewRollback()
+		instance.Spec.Rollback.Enabled = true
+		summoncomponents.SetDefaults(instance)
+		instance.Status.Status = summonv1beta1.StatusDeploying
+		instance.Status.PullSecretStatus = secretsv1beta1.StatusReady
+		instance.Status.PostgresStatus = dbv1beta1.StatusReady
+		instance.Status.LastReadyVersion = "1.2.2"
+		ctx.Client = fake.NewFakeClient()
+	})
+
+	It("does nothing when rollback is disabled", func() {
+		instance.Spec.Rollback.Enabled = false
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Rollback).To(Equal(summonv1beta1.RollbackStatus{}))
+	})
+
+	It("does nothing without a ready version to go back to", func() {
+		instance.Status.LastReadyVersion = ""
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Rollback).To(Equal(summonv1beta1.RollbackStatus{}))
+	})
+
+	It("watches a new version without starting the clock", func() {
+		res, err := comp.Reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeZero())
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		Expect(instance.Status.Rollback.DeployVersion).To(Equal("1.2.3"))
+		Expect(instance.Status.Rollback.DeployStarted).To(BeEmpty())
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal(""))
+	})
+
+	It("starts the clock once migrations are done", func() {
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{DeployVersion: "1.2.3"}
+		instance.Status.MigrateVersion = "1.2.3"
+		res, err := comp.Reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(30 * time.Minute))
+		Expect(res.StatusModifier(instance)).To(Succeed())
+		Expect(instance.Status.Rollback.DeployVersion).To(Equal("1.2.3"))
+		Expect(instance.Status.Rollback.DeployStarted).ToNot(BeEmpty())
+	})
+
+	It("doesn't count a slow backup against the deadline", func() {
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{DeployVersion: "1.2.3"}
+		instance.Status.MigrateVersion = "1.2.2"
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal(""))
+		Expect(instance.Status.Rollback.DeployStarted).To(BeEmpty())
+	})
+
+	It("waits until the deadline", func() {
+		deployStarted(10 * time.Minute)
+		res, err := comp.Reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically("~", 20*time.Minute, time.Minute))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal(""))
+	})
+
+	It("rolls back when migrations marked backward compatible fail", func() {
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{DeployVersion: "1.2.3"}
+		instance.Spec.Rollback.BackwardCompatibleMigrations = "1.2.3"
+		ctx.Client = fake.NewFakeClient(migrationJob("1.2.3", batchv1.JobStatus{Failed: 1}))
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeploying))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal("1.2.3"))
+		Expect(instance.Status.Rollback.Version).To(Equal("1.2.2"))
+		Expect(instance.Status.Rollback.Reason).To(Equal("migrations failed for version 1.2.3"))
+	})
+
+	It("refuses to roll back over failed migrations", func() {
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{DeployVersion: "1.2.3"}
+		ctx.Client = fake.NewFakeClient(migrationJob("1.2.3", batchv1.JobStatus{Failed: 1}))
+		_, err := comp.Reconcile(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.ClassOf(err)).To(Equal(errors.ClassPermanent))
+		Expect(err.Error()).To(ContainSubstring("migrations failed for version 1.2.3"))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal(""))
+	})
+
+	It("ignores a failed migration job from another version", func() {
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{DeployVersion: "1.2.3"}
+		ctx.Client = fake.NewFakeClient(migrationJob("1.2.1", batchv1.JobStatus{Failed: 1}))
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal(""))
+	})
+
+	It("rolls back after the deadline", func() {
+		deployStarted(time.Hour)
+		instance.Spec.Rollback.BackwardCompatibleMigrations = "1.2.3"
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal("1.2.3"))
+		Expect(instance.Status.Rollback.Reason).To(Equal("version 1.2.3 was not ready within 30m0s"))
+	})
+
+	It("refuses to roll back over applied migrations", func() {
+		deployStarted(time.Hour)
+		_, err := comp.Reconcile(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.ClassOf(err)).To(Equal(errors.ClassPermanent))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal(""))
+	})
+
+	It("leaves an aborted canary alone after the deadline", func() {
+		deployStarted(time.Hour)
+		instance.Status.Canary = summonv1beta1.CanaryStatus{Version: "1.2.3", StableVersion: "1.2.2", Phase: summonv1beta1.CanaryPhaseAborted}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal(""))
+	})
+
+	It("keeps deploying the old version after a rollback", func() {
+		instance.Status.Status = summonv1beta1.StatusMigrating
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{FailedVersion: "1.2.3", Version: "1.2.2"}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeploying))
+		Expect(instance.Status.Rollback.FailedVersion).To(Equal("1.2.3"))
+	})
+
+	It("tries the new version again when rollback is disabled", func() {
+		instance.Spec.Rollback.Enabled = false
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{FailedVersion: "1.2.3", Version: "1.2.2"}
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Rollback).To(Equal(summonv1beta1.RollbackStatus{}))
+	})
+})
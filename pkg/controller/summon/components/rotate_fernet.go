@@ -125,6 +125,7 @@ func (comp *fernetRotateComponent) Reconcile(ctx *components.ComponentContext) (
 	if err != nil {
 		return components.Result{}, errors.Wrap(err, "rotate_fernet: Failed to update secret")
 	}
+	ctx.Eventf(corev1.EventTypeNormal, "FernetKeyRotated", "Added new fernet key %s", timeStamp)
 
 	return components.Result{}, nil
 }
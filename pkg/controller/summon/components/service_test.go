@@ -25,6 +25,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
 	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -70,4 +71,39 @@ var _ = Describe("SummonPlatform service Component", func() {
 		err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-daphne", Namespace: "summon-dev"}, target)
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	It("applies a strategic merge overlay to the matching service", func() {
+		instance.Spec.Overlays = []overlays.Patch{
+			{Kind: "Service", Name: "foo-dev-web", Patch: "metadata: {annotations: {example.com/extra: \"true\"}}"},
+			{Kind: "Service", Name: "foo-dev-daphne", Patch: "metadata: {annotations: {example.com/other: \"true\"}}"},
+		}
+		comp := summoncomponents.NewService("web/service.yml.tpl")
+		Expect(comp).To(ReconcileContext(ctx))
+		target := &corev1.Service{}
+		err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-web", Namespace: "summon-dev"}, target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target.Annotations).To(HaveKeyWithValue("example.com/extra", "true"))
+		Expect(target.Annotations).ToNot(HaveKey("example.com/other"))
+	})
+
+	It("applies a JSON patch overlay", func() {
+		instance.Spec.Overlays = []overlays.Patch{
+			{Kind: "Service", Name: "foo-dev-web", Type: overlays.JSONPatch, Patch: `[{"op": "replace", "path": "/spec/ports/0/port", "value": 8080}]`},
+		}
+		comp := summoncomponents.NewService("web/service.yml.tpl")
+		Expect(comp).To(ReconcileContext(ctx))
+		target := &corev1.Service{}
+		err := ctx.Client.Get(context.TODO(), types.NamespacedName{Name: "foo-dev-web", Namespace: "summon-dev"}, target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target.Spec.Ports[0].Port).To(BeEquivalentTo(8080))
+	})
+
+	It("fails on an overlay which can't be applied", func() {
+		instance.Spec.Overlays = []overlays.Patch{
+			{Kind: "Service", Name: "foo-dev-web", Type: overlays.JSONPatch, Patch: `[{"op": "remove", "path": "/spec/nope"}]`},
+		}
+		comp := summoncomponents.NewService("web/service.yml.tpl")
+		_, err := comp.Reconcile(ctx)
+		Expect(err).To(HaveOccurred())
+	})
 })
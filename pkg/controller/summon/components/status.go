@@ -18,7 +18,6 @@ package components
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -26,8 +25,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 )
 
 type statusComponent struct{}
@@ -89,7 +90,7 @@ func (comp *statusComponent) Reconcile(ctx *components.ComponentContext) (compon
 		return components.Result{}, err
 	}
 
-	if os.Getenv("ENABLE_NEW_STATUS_CHECK") == "true" {
+	if ctx.Config().Enabled(config.FeatureNewStatusCheck) {
 		dispatch := &appsv1.Deployment{}
 		businessPortal := &appsv1.Deployment{}
 		tripShare := &appsv1.Deployment{}
@@ -118,14 +119,9 @@ func (comp *statusComponent) Reconcile(ctx *components.ComponentContext) (compon
 			comp.isReady(static) && comp.isReady(celerybeat) &&
 			comp.isReady(dispatch) && comp.isReady(businessPortal) &&
 			comp.isReady(tripShare) && comp.isReady(hwAux) {
-			return components.Result{StatusModifier: func(obj runtime.Object) error {
-				instance := obj.(*summonv1beta1.SummonPlatform)
-				instance.Status.Status = summonv1beta1.StatusReady
-				instance.Status.Message = fmt.Sprintf("Cluster %s ready", instance.Name)
-				return nil
-			}}, nil
+			return components.Result{StatusModifier: comp.ready(instance)}, nil
 		}
-		return components.Result{}, nil
+		return components.Result{StatusModifier: comp.notAvailable()}, nil
 	}
 
 	// The big check!
@@ -137,16 +133,47 @@ func (comp *statusComponent) Reconcile(ctx *components.ComponentContext) (compon
 		// Note this one is different, available vs ready.
 		celerybeat.Spec.Replicas != nil && celerybeat.Status.ReadyReplicas == *celerybeat.Spec.Replicas {
 		// TODO: Add an actual HTTP self check in here.
-		return components.Result{StatusModifier: func(obj runtime.Object) error {
+		return components.Result{StatusModifier: comp.ready(instance)}, nil
+	}
+
+	// Not ready, alas.
+	return components.Result{StatusModifier: comp.notAvailable()}, nil
+}
+
+// Status modifier for when every Deployment is ready. A rolled back instance is running the old
+// version, so it gets RolledBack rather than Ready and LastReadyVersion stays put. An instance
+// with a held deploy is ready on its current version but gets DeployHeld.
+func (comp *statusComponent) ready(instance *summonv1beta1.SummonPlatform) components.StatusModifier {
+	// Store the version in the closure in case autodeploy changed Spec.Version in memory.
+	version := instance.Spec.Version
+	if rolledBack(instance) {
+		rollbackVersion := instance.Status.Rollback.Version
+		return func(obj runtime.Object) error {
 			instance := obj.(*summonv1beta1.SummonPlatform)
-			instance.Status.Status = summonv1beta1.StatusReady
-			instance.Status.Message = fmt.Sprintf("Cluster %s ready", instance.Name)
+			instance.Status.Status = summonv1beta1.StatusRolledBack
+			instance.Status.Message = fmt.Sprintf("Cluster %s rolled back from version %s to %s: %s", instance.Name, version, rollbackVersion, instance.Status.Rollback.Reason)
+			components.SetCondition(instance, summonv1beta1.ConditionDeploymentsAvailable, conditions.ConditionTrue, "DeploymentsRolledBack", "")
 			return nil
-		}}, nil
+		}
+	}
+	return func(obj runtime.Object) error {
+		instance := obj.(*summonv1beta1.SummonPlatform)
+		instance.Status.Status = summonv1beta1.StatusReady
+		instance.Status.Message = fmt.Sprintf("Cluster %s ready", instance.Name)
+		hold := instance.Status.DeployHold
+		if hold.Version != "" {
+			instance.Status.Status = summonv1beta1.StatusDeployHeld
+			instance.Status.Message = fmt.Sprintf("Cluster %s ready on version %s, holding version %s: %s", instance.Name, version, hold.Version, hold.Reason)
+		}
+		instance.Status.LastReadyVersion = version
+		components.SetCondition(instance, summonv1beta1.ConditionDeploymentsAvailable, conditions.ConditionTrue, "DeploymentsReady", "")
+		return nil
 	}
+}
 
-	// Not ready, alas.
-	return components.Result{}, nil
+// Status modifier for when some Deployments are still rolling out.
+func (comp *statusComponent) notAvailable() components.StatusModifier {
+	return components.ConditionModifier(summonv1beta1.ConditionDeploymentsAvailable, conditions.ConditionFalse, "DeploymentsRollingOut", "Waiting for all Deployments and StatefulSets to become ready")
 }
 
 // Short helper because we need to do this 6 times.
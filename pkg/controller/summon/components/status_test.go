@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/conditions"
 	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
 	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
 	. "github.com/Ridecell/ridecell-operator/pkg/test_helpers/matchers"
@@ -112,6 +113,56 @@ var _ = Describe("SummonPlatform Status Component", func() {
 		comp := summoncomponents.NewStatus()
 		Expect(comp).To(ReconcileContext(ctx))
 		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusReady))
+		Expect(instance.Status.LastReadyVersion).To(Equal("1.2.3"))
+		Expect(conditions.IsTrue(instance.Status.Conditions, summonv1beta1.ConditionDeploymentsAvailable)).To(BeTrue())
+	})
+
+	It("sets the status to rolled back", func() {
+		webDeployment.Status.AvailableReplicas = 2
+		daphneDeployment.Status.AvailableReplicas = 2
+		celerydDeployment.Status.AvailableReplicas = 2
+		channelworkersDeployment.Status.AvailableReplicas = 2
+		staticDeployment.Status.AvailableReplicas = 2
+		celerybeatStatefulSet.Status.ReadyReplicas = 2
+		instance.Status.Status = summonv1beta1.StatusDeploying
+		instance.Status.LastReadyVersion = "1.2.2"
+		instance.Status.Rollback = summonv1beta1.RollbackStatus{FailedVersion: "1.2.3", Version: "1.2.2", Reason: "migrations failed for version 1.2.3"}
+		ctx.Client = makeClient()
+
+		comp := summoncomponents.NewStatus()
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusRolledBack))
+		Expect(instance.Status.Message).To(Equal("Cluster foo-dev rolled back from version 1.2.3 to 1.2.2: migrations failed for version 1.2.3"))
+		Expect(instance.Status.LastReadyVersion).To(Equal("1.2.2"))
+	})
+
+	It("sets the status to deploy held", func() {
+		webDeployment.Status.AvailableReplicas = 2
+		daphneDeployment.Status.AvailableReplicas = 2
+		celerydDeployment.Status.AvailableReplicas = 2
+		channelworkersDeployment.Status.AvailableReplicas = 2
+		staticDeployment.Status.AvailableReplicas = 2
+		celerybeatStatefulSet.Status.ReadyReplicas = 2
+		instance.Status.Status = summonv1beta1.StatusDeploying
+		instance.Status.DeployHold = summonv1beta1.DeployHoldStatus{Version: "1.2.4", Reason: "deploy freeze Holidays"}
+		ctx.Client = makeClient()
+
+		comp := summoncomponents.NewStatus()
+		Expect(comp).To(ReconcileContext(ctx))
+		Expect(instance.Status.Status).To(Equal(summonv1beta1.StatusDeployHeld))
+		Expect(instance.Status.Message).To(Equal("Cluster foo-dev ready on version 1.2.3, holding version 1.2.4: deploy freeze Holidays"))
+		Expect(instance.Status.LastReadyVersion).To(Equal("1.2.3"))
+	})
+
+	It("sets the DeploymentsAvailable condition to false while rolling out", func() {
+		instance.Status.Status = summonv1beta1.StatusDeploying
+
+		comp := summoncomponents.NewStatus()
+		Expect(comp).To(ReconcileContext(ctx))
+		cond := conditions.Find(instance.Status.Conditions, summonv1beta1.ConditionDeploymentsAvailable)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(conditions.ConditionFalse))
+		Expect(cond.LastTransitionTime).ToNot(BeEmpty())
 	})
 
 	It("doesn't update if still migrating", func() {
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+)
+
+// Check a SummonPlatform spec for mistakes that don't depend on anything else in the cluster.
+// This is run by the defaults component and by the admission webhook, so it must only look at
+// the object itself and must accept specs before defaults are filled in.
+func Validate(instance *summonv1beta1.SummonPlatform) error {
+	errs := []error{}
+
+	if instance.Spec.Version == "" && instance.Spec.AutoDeploy == "" {
+		errs = append(errs, errors.New("Spec.Version OR Spec.AutoDeploy must be set. No Version set for deployment."))
+	}
+	if instance.Spec.Version != "" && instance.Spec.AutoDeploy != "" {
+		errs = append(errs, errors.New("Spec.Version and Spec.AutoDeploy are both set. Must specify only one."))
+	}
+
+	// If the persistentVolumeClaim for redis changes this integer should as well.
+	if instance.Spec.Redis.RAM > 10*1024 {
+		errs = append(errs, errors.New("redis memory limit cannot surpass available disk space"))
+	}
+
+	celeryBeat := instance.Spec.Replicas.CeleryBeat
+	if celeryBeat != nil && !(*celeryBeat == 0 || *celeryBeat == 1) {
+		errs = append(errs, errors.Errorf("Invalid celerybeat replicas, must be exactly 0 or 1: %v", *celeryBeat))
+	}
+
+	// A weight of 0 is unset and gets the default.
+	canary := instance.Spec.Canary
+	if canary.Weight != 0 && (canary.Weight < 1 || canary.Weight > 100) {
+		errs = append(errs, errors.Errorf("Invalid canary weight, must be unset or between 1 and 100: %v", canary.Weight))
+	}
+	if canary.MaxErrorRate != nil && (*canary.MaxErrorRate < 0 || *canary.MaxErrorRate > 1) {
+		errs = append(errs, errors.Errorf("Invalid canary maxErrorRate, must be between 0 and 1: %v", *canary.MaxErrorRate))
+	}
+	if instance.Spec.Rollback.DeployDeadline.Duration < 0 {
+		errs = append(errs, errors.Errorf("Invalid rollback deployDeadline, must be positive: %v", instance.Spec.Rollback.DeployDeadline.Duration))
+	}
+
+	if instance.Spec.Hibernation != nil {
+		_, err := hibernationSchedule(instance.Spec.Hibernation)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if instance.Spec.DeployWindow != nil {
+		_, _, err := deployWindowSchedule(instance.Spec.DeployWindow)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, overlay := range instance.Spec.Overlays {
+		err := overlay.Validate()
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid overlay %d", i))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// Check the input secrets from Spec.Secrets, in the same order as SpecSecrets, for values that
+// conflict with the spec. Missing secrets should be passed as nil.
+func ValidateSecrets(instance *summonv1beta1.SummonPlatform, secrets []*corev1.Secret) error {
+	if !instance.Spec.EnableMockCarServer {
+		return nil
+	}
+	// Later secrets override earlier ones, so only the last value counts.
+	var otakeysAPIKey []byte
+	for _, secret := range secrets {
+		if secret == nil {
+			continue
+		}
+		val, ok := secret.Data["OTAKEYS_API_KEY"]
+		if ok {
+			otakeysAPIKey = val
+		}
+	}
+	if len(otakeysAPIKey) > 0 {
+		return errors.New("Conflict in OTA Keys configuration, cannot provide OTAKEYS_API_KEY and enableMockCarServer")
+	}
+	return nil
+}
+
+// The names of the input secrets for an instance, in override order.
+func SpecSecrets(instance *summonv1beta1.SummonPlatform) []string {
+	if len(instance.Spec.Secrets) == 0 {
+		return []string{instance.Namespace, instance.Name}
+	}
+	return instance.Spec.Secrets
+}
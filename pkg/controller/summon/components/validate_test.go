@@ -0,0 +1,122 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Ridecell/ridecell-operator/pkg/apis/helpers/overlays"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+)
+
+var _ = Describe("SummonPlatform Validate", func() {
+	It("accepts the default test instance", func() {
+		Expect(summoncomponents.Validate(instance)).To(Succeed())
+	})
+
+	It("reports every problem at once", func() {
+		instance.Spec.AutoDeploy = "test-branch"
+		instance.Spec.Redis.RAM = 20 * 1024
+		instance.Spec.Replicas.CeleryBeat = intp(2)
+		err := summoncomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Spec.Version and Spec.AutoDeploy are both set"))
+		Expect(err.Error()).To(ContainSubstring("redis memory limit cannot surpass available disk space"))
+		Expect(err.Error()).To(ContainSubstring("Invalid celerybeat replicas"))
+	})
+
+	It("allows an unset celerybeat replica count", func() {
+		instance.Spec.Replicas.CeleryBeat = nil
+		Expect(summoncomponents.Validate(instance)).To(Succeed())
+	})
+
+	It("checks the canary weight", func() {
+		instance.Spec.Canary.Weight = 0
+		Expect(summoncomponents.Validate(instance)).To(Succeed())
+		instance.Spec.Canary.Weight = 100
+		Expect(summoncomponents.Validate(instance)).To(Succeed())
+
+		instance.Spec.Canary.Weight = -1
+		err := summoncomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid canary weight, must be unset or between 1 and 100: -1"))
+
+		instance.Spec.Canary.Weight = 101
+		err = summoncomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid canary weight, must be unset or between 1 and 100: 101"))
+	})
+
+	It("rejects a malformed overlay", func() {
+		instance.Spec.Overlays = []overlays.Patch{
+			{Kind: "Deployment", Name: "foo-dev-web", Type: overlays.JSONPatch, Patch: `{"not": "a list"}`},
+		}
+		err := summoncomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid overlay 0"))
+	})
+
+	It("rejects a malformed hibernation schedule", func() {
+		instance.Spec.Hibernation = &summonv1beta1.HibernationSpec{WakeSchedule: "0 8 * * 1-5"}
+		err := summoncomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("wakeSchedule and sleepSchedule must be set together"))
+
+		instance.Spec.Hibernation = &summonv1beta1.HibernationSpec{AwakeWindows: []string{"Mon-Fri 8am-8pm"}}
+		Expect(summoncomponents.Validate(instance)).NotTo(Succeed())
+	})
+
+	It("rejects a malformed deploy window", func() {
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{AllowedWindows: []string{"Mon-Thu 09:00-16:00"}, TimeZone: "America/Nowhere"}
+		err := summoncomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid time zone"))
+
+		instance.Spec.DeployWindow = &summonv1beta1.DeployWindowSpec{Freezes: []summonv1beta1.DeployFreeze{
+			{Name: "Holidays", Start: "2019-12-31T00:00:00Z", End: "2019-12-20T00:00:00Z"},
+		}}
+		err = summoncomponents.Validate(instance)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`freeze "Holidays" ends before it starts`))
+	})
+
+	Describe("ValidateSecrets", func() {
+		var secret *corev1.Secret
+
+		BeforeEach(func() {
+			secret = &corev1.Secret{Data: map[string][]byte{"OTAKEYS_API_KEY": []byte("key")}}
+		})
+
+		It("rejects OTAKEYS_API_KEY with the mock car server", func() {
+			instance.Spec.EnableMockCarServer = true
+			Expect(summoncomponents.ValidateSecrets(instance, []*corev1.Secret{secret, nil})).NotTo(Succeed())
+		})
+
+		It("allows OTAKEYS_API_KEY without the mock car server", func() {
+			Expect(summoncomponents.ValidateSecrets(instance, []*corev1.Secret{secret, nil})).To(Succeed())
+		})
+
+		It("allows a later secret to blank out OTAKEYS_API_KEY", func() {
+			instance.Spec.EnableMockCarServer = true
+			blank := &corev1.Secret{Data: map[string][]byte{"OTAKEYS_API_KEY": []byte("")}}
+			Expect(summoncomponents.ValidateSecrets(instance, []*corev1.Secret{secret, blank})).To(Succeed())
+		})
+	})
+})
@@ -35,27 +35,45 @@ import (
 // Add creates a new Summon Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
+	// Everything else needs the defaults and the final Spec.Version filled in first.
+	autoDeploy := summoncomponents.NewAutoDeploy()
+	deployWindow := summoncomponents.NewDeployWindow()
+	// The celeryd HPA is removed during maintenance so it can't scale the workers back up.
+	celerydAutoscaled := func(s *summonv1beta1.SummonPlatform) bool {
+		return *s.Spec.Replicas.CelerydAuto && !s.Spec.Maintenance.Enabled
+	}
+
 	c, err := components.NewReconciler("summon-platform-controller", mgr, &summonv1beta1.SummonPlatform{}, Templates, []components.Component{
 		// Set default values.
 		summoncomponents.NewDefaults(),
 
+		// Scale everything to zero outside the sleep schedule.
+		summoncomponents.NewHibernation(),
+
 		// Possibly have Spec.Version value replaced by autodeploy logic.
-		summoncomponents.NewAutoDeploy(),
+		autoDeploy,
+
+		// Keep the current Spec.Version outside the deploy window.
+		deployWindow,
 
-		// Top-level components.
-		summoncomponents.NewPullSecret("pullsecret/pullsecret.yml.tpl"),
-		summoncomponents.NewPostgres(),
+		// Top-level components. These only depend on the spec, so they all run in parallel.
+		components.DependsOn(summoncomponents.NewPullSecret("pullsecret/pullsecret.yml.tpl"), deployWindow),
+		components.DependsOn(summoncomponents.NewPostgres(), deployWindow),
 
 		// aws stuff
-		summoncomponents.NewIAMUser("aws/iamuser.yml.tpl"),
-		summoncomponents.NewS3Bucket("aws/staticbucket.yml.tpl"),
-		summoncomponents.NewMIVS3Bucket("aws/mivbucket.yml.tpl"),
+		components.DependsOn(summoncomponents.NewIAMUser("aws/iamuser.yml.tpl"), deployWindow),
+		components.DependsOn(summoncomponents.NewS3Bucket("aws/staticbucket.yml.tpl"), deployWindow),
+		components.DependsOn(summoncomponents.NewMIVS3Bucket("aws/mivbucket.yml.tpl"), deployWindow),
 
 		// GCP stuff.
-		summoncomponents.NewServiceAccount(),
+		components.DependsOn(summoncomponents.NewServiceAccount(), deployWindow),
 
 		//Rabbitmq components
-		summoncomponents.NewRabbitmqVhost("rabbitmq/vhost.yml.tpl"),
+		components.DependsOn(summoncomponents.NewRabbitmqVhost("rabbitmq/vhost.yml.tpl"), deployWindow),
+
+		// Redis storage and service, the Deployment itself waits for migrations below.
+		components.DependsOn(summoncomponents.NewPVC("redis/volumeclaim.yml.tpl"), deployWindow),
+		components.DependsOn(summoncomponents.NewService("redis/service.yml.tpl"), deployWindow),
 
 		// Secrets components
 		summoncomponents.NewSecretKey(),
@@ -66,14 +84,20 @@ func Add(mgr manager.Manager) error {
 
 		summoncomponents.NewConfigMap("configmap.yml.tpl"),
 		summoncomponents.NewBackup(),
+		summoncomponents.NewRollback(),
 		summoncomponents.NewMigrations("migrations.yml.tpl"),
 		summoncomponents.NewMigrateWait(),
 		summoncomponents.NewSuperuser(),
 
+		// Canary pods for a new version. Holds the rest of the deploy until they are promoted.
+		summoncomponents.NewCanary(),
+
 		// Redis components.
-		summoncomponents.NewPVC("redis/volumeclaim.yml.tpl"),
 		summoncomponents.NewRedisDeployment("redis/deployment.yml.tpl"),
-		summoncomponents.NewService("redis/service.yml.tpl"),
+
+		// Maintenance page, only while maintenance mode is on.
+		summoncomponents.NewMaintenance(),
+		summoncomponents.NewService("maintenance/service.yml.tpl"),
 
 		// Web components.
 		summoncomponents.NewDeployment("web/deployment.yml.tpl", nil),
@@ -94,9 +118,9 @@ func Add(mgr manager.Manager) error {
 		summoncomponents.NewIngress("static/ingress.yml.tpl"),
 
 		// Celery components.
-		summoncomponents.NewDeployment("celeryd/deployment.yml.tpl", func(s *summonv1beta1.SummonPlatform) bool { return *s.Spec.Replicas.CelerydAuto }),
+		summoncomponents.NewDeployment("celeryd/deployment.yml.tpl", celerydAutoscaled),
 		summoncomponents.NewPodDisruptionBudget("celeryd/podDisruptionBudget.yml.tpl"),
-		summoncomponents.NewHPA("celeryd/hpa.yml.tpl", func(s *summonv1beta1.SummonPlatform) bool { return *s.Spec.Replicas.CelerydAuto }),
+		summoncomponents.NewHPA("celeryd/hpa.yml.tpl", celerydAutoscaled),
 
 		// Celerybeat components.
 		summoncomponents.NewDeployment("celerybeat/statefulset.yml.tpl", nil),
@@ -94,6 +94,32 @@ var _ = Describe("Summon controller", func() {
 		Eventually(func() error { return c.Get(context.TODO(), depKey, service) }, timeout).Should(Succeed())
 	})
 
+	It("prunes the metrics service when metrics are disabled", func() {
+		c := helpers.TestClient
+		instance := &summonv1beta1.SummonPlatform{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: helpers.Namespace},
+			Spec: summonv1beta1.SummonPlatformSpec{
+				Version: "1.2.3",
+			},
+		}
+		c.Create(instance)
+
+		service := &corev1.Service{}
+		c.EventuallyGet(helpers.Name("foo-metrics"), service)
+		Expect(service.Labels).To(HaveKeyWithValue("ridecell.io/prune-owner", string(instance.UID)))
+		Expect(service.Annotations).To(HaveKeyWithValue("ridecell.io/template", "metrics/service.yml.tpl"))
+
+		// Turn off metrics and the service should go away.
+		c.Get(helpers.Name("foo"), instance)
+		disabled := false
+		instance.Spec.Metrics.Web = &disabled
+		c.Update(instance)
+
+		Eventually(func() error {
+			return helpers.Client.Get(context.TODO(), helpers.Name("foo-metrics"), service)
+		}, timeout).ShouldNot(Succeed())
+	})
+
 	It("runs a basic reconcile", func() {
 		c := helpers.TestClient
 		instance := &summonv1beta1.SummonPlatform{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: helpers.Namespace}, Spec: summonv1beta1.SummonPlatformSpec{
@@ -537,6 +563,18 @@ var _ = Describe("Summon controller", func() {
 
 		// Check the status again. Should be Deploying.
 		assertStatus(summonv1beta1.StatusReady)
+
+		// The way there should be in the history.
+		Expect(instance.Status.History).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"From":    Equal(summonv1beta1.StatusMigrating),
+			"To":      Equal(summonv1beta1.StatusDeploying),
+			"Version": Equal("1-abcdef1-master"),
+		})))
+		Expect(instance.Status.History).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"From":      Equal(summonv1beta1.StatusDeploying),
+			"To":        Equal(summonv1beta1.StatusReady),
+			"Component": Not(BeEmpty()),
+		})))
 	})
 
 	It("block all action with skip-reconcile annotation", func() {
@@ -566,6 +604,41 @@ var _ = Describe("Summon controller", func() {
 		}, time.Second*10).ShouldNot(Succeed())
 	})
 
+	It("skips paused components and steps one on request", func() {
+		c := helpers.TestClient
+		instance := &summonv1beta1.SummonPlatform{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "skiptest",
+				Namespace: helpers.Namespace,
+				Annotations: map[string]string{
+					"ridecell.io/skip-components": "service(web/service.yml.tpl), backup",
+				},
+			},
+			Spec: summonv1beta1.SummonPlatformSpec{
+				Version: "1.2.3",
+			},
+		}
+		c.Create(instance)
+
+		// Everything else keeps converging.
+		service := &corev1.Service{}
+		c.EventuallyGet(helpers.Name("skiptest-static"), service)
+		Eventually(func() []string {
+			c.Get(helpers.Name("skiptest"), instance)
+			return instance.Status.SkippedComponents
+		}, timeout).Should(ContainElement("service(web/service.yml.tpl)"))
+		Expect(helpers.Client.Get(context.TODO(), helpers.Name("skiptest-web"), service)).ToNot(Succeed())
+
+		// Let the web service run once.
+		instance.Annotations["ridecell.io/step-component"] = "service"
+		c.Update(instance)
+		c.EventuallyGet(helpers.Name("skiptest-web"), service)
+		Eventually(func() map[string]string {
+			c.Get(helpers.Name("skiptest"), instance)
+			return instance.Annotations
+		}, timeout).ShouldNot(HaveKey("ridecell.io/step-component"))
+	})
+
 	It("manages the new status behavior correctly", func() {
 		c := helpers.Client
 
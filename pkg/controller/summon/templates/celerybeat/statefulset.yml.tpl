@@ -1,3 +1,4 @@
+{{ $version := .Extra.version | default .Instance.Spec.Version }}
 apiVersion: apps/v1
 kind: StatefulSet
 metadata:
@@ -6,12 +7,12 @@ metadata:
   labels:
     app.kubernetes.io/name: celerybeat
     app.kubernetes.io/instance: {{ .Instance.Name }}-celerybeat
-    app.kubernetes.io/version: {{ .Instance.Spec.Version }}
+    app.kubernetes.io/version: {{ $version }}
     app.kubernetes.io/component: worker
     app.kubernetes.io/part-of: {{ .Instance.Name }}
     app.kubernetes.io/managed-by: summon-operator
 spec:
-  replicas: {{ .Instance.Spec.Replicas.CeleryBeat | default 0 }}
+  replicas: {{ if .Instance.Spec.Maintenance.Enabled }}0{{ else }}{{ .Instance.Spec.Replicas.CeleryBeat | default 0 }}{{ end }}
   selector:
     matchLabels:
       app.kubernetes.io/instance: {{ .Instance.Name }}-celerybeat
@@ -23,7 +24,7 @@ spec:
       labels:
         app.kubernetes.io/name: celerybeat
         app.kubernetes.io/instance: {{ .Instance.Name }}-celerybeat
-        app.kubernetes.io/version: {{ .Instance.Spec.Version }}
+        app.kubernetes.io/version: {{ $version }}
         app.kubernetes.io/component: worker
         app.kubernetes.io/part-of: {{ .Instance.Name }}
         app.kubernetes.io/managed-by: summon-operator
@@ -46,7 +47,7 @@ spec:
           mountPath: /schedule
       containers:
       - name: default
-        image: us.gcr.io/ridecell-1/summon:{{ .Instance.Spec.Version }}
+        image: us.gcr.io/ridecell-1/summon:{{ $version }}
         imagePullPolicy: Always
         command:
         - /bin/sh
@@ -1,3 +1,4 @@
+{{ $version := .Extra.version | default .Instance.Spec.Version }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -6,13 +7,13 @@ metadata:
   labels:
     app.kubernetes.io/name: celeryd
     app.kubernetes.io/instance: {{ .Instance.Name }}-celeryd
-    app.kubernetes.io/version: {{ .Instance.Spec.Version }}
+    app.kubernetes.io/version: {{ $version }}
     app.kubernetes.io/component: worker
     app.kubernetes.io/part-of: {{ .Instance.Name }}
     app.kubernetes.io/managed-by: summon-operator
     metrics-enabled: "{{ .Instance.Spec.Metrics.Celeryd | default "false" }}"
 spec:
-  replicas: {{ .Instance.Spec.Replicas.Celeryd | default 0 }}
+  replicas: {{ if .Instance.Spec.Maintenance.Enabled }}0{{ else }}{{ .Instance.Spec.Replicas.Celeryd | default 0 }}{{ end }}
   selector:
     matchLabels:
       app.kubernetes.io/instance: {{ .Instance.Name }}-celeryd
@@ -21,7 +22,7 @@ spec:
       labels:
         app.kubernetes.io/name: celeryd
         app.kubernetes.io/instance: {{ .Instance.Name }}-celeryd
-        app.kubernetes.io/version: {{ .Instance.Spec.Version }}
+        app.kubernetes.io/version: {{ $version }}
         app.kubernetes.io/component: worker
         app.kubernetes.io/part-of: {{ .Instance.Name }}
         app.kubernetes.io/managed-by: summon-operator
@@ -49,7 +50,7 @@ spec:
       - name: pull-secret
       containers:
       - name: default
-        image: us.gcr.io/ridecell-1/summon:{{ .Instance.Spec.Version }}
+        image: us.gcr.io/ridecell-1/summon:{{ $version }}
         imagePullPolicy: Always
         command:
         - python
@@ -1,6 +1,6 @@
 {{ define "componentName" }}channelworker{{ end }}
 {{ define "componentType" }}worker{{ end }}
 {{ define "command" }}[python, manage.py, runworker, "-v2", "--threads", "2"]{{ end }}
-{{ define "replicas" }}{{ .Instance.Spec.Replicas.ChannelWorker | default 0 }}{{ end }}
+{{ define "replicas" }}{{ if .Instance.Spec.Maintenance.Enabled }}0{{ else }}{{ .Instance.Spec.Replicas.ChannelWorker | default 0 }}{{ end }}{{ end }}
 {{ define "resources" }}{requests: {memory: "250M", cpu: "5m"}, limits: {memory: "300M"}}{{ end }}
 {{ template "deployment" . }}
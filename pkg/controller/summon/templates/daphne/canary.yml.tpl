@@ -0,0 +1,5 @@
+{{ define "componentName" }}daphne{{ end }}
+{{ define "componentType" }}web{{ end }}
+{{ define "command" }}{{ template "daphneCommand" . }}{{ end }}
+{{ define "resources" }}{{ template "daphneResources" . }}{{ end }}
+{{ if .Extra.canary }}{{ template "deployment" . }}{{ end }}
@@ -1,4 +1,5 @@
 {{ define "componentName" }}daphne{{ end }}
 {{ define "componentType" }}web{{ end }}
+{{ define "backendName" }}{{ if .Instance.Spec.Maintenance.Enabled }}maintenance{{ else }}daphne{{ end }}{{ end }}
 {{ define "ingressPath" }}/websockets{{ end }}
 {{ template "ingress" . }}
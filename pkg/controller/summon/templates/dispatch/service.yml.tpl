@@ -1,3 +1,3 @@
 {{ define "componentName" }}dispatch{{ end }}
 {{ define "componentType" }}dispatch{{ end }}
-{{ template "service" . }}
+{{ if .Instance.Spec.Dispatch.Version }}{{ template "service" . }}{{ end }}
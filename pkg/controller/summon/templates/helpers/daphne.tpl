@@ -0,0 +1,3 @@
+{{/* Pod settings shared by the daphne Deployment and its canary. */}}
+{{ define "daphneCommand" }}[daphne, "-b", "0.0.0.0", "summon_platform.asgi:channel_layer"]{{ end }}
+{{ define "daphneResources" }}{requests: {memory: "270M", cpu: "20m"}, limits: {memory: "300M"}}{{ end }}
@@ -1,32 +1,46 @@
 {{ define "deployment" }}
+{{- /* Extra.version overrides the image version, for canaries and rollbacks. Canaries run next to
+       the existing pods. They keep the instance label so the component's Service and
+       PodDisruptionBudget include them, and add a track label so their own selector doesn't
+       match the stable pods. */ -}}
+{{ $version := .Extra.version | default .Instance.Spec.Version }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
-  name: {{ .Instance.Name }}-{{ block "componentName" . }}{{ end }}
+  name: {{ .Instance.Name }}-{{ block "componentName" . }}{{ end }}{{ if .Extra.canary }}-canary{{ end }}
   namespace: {{ .Instance.Namespace }}
   labels:
     app.kubernetes.io/name: {{ block "componentName" . }}{{ end }}
     app.kubernetes.io/instance: {{ .Instance.Name }}-{{ block "componentName" . }}{{ end }}
-    app.kubernetes.io/version: {{ .Instance.Spec.Version }}
+    app.kubernetes.io/version: {{ $version }}
     app.kubernetes.io/component: {{ block "componentType" . }}{{ end }}
     app.kubernetes.io/part-of: {{ .Instance.Name }}
     app.kubernetes.io/managed-by: summon-operator
     metrics-enabled: {{ block "metricsEnabled" . }}{{ end }}
+    {{- if .Extra.canary }}
+    summon.ridecell.io/track: canary
+    {{- end }}
 spec:
-  replicas: {{ block "replicas" . }}1{{ end }}
+  replicas: {{ if .Extra.canary }}{{ .Extra.canaryReplicas }}{{ else }}{{ block "replicas" . }}1{{ end }}{{ end }}
   selector:
     matchLabels:
       app.kubernetes.io/instance: {{ .Instance.Name }}-{{ block "componentName" . }}{{ end }}
+      {{- if .Extra.canary }}
+      summon.ridecell.io/track: canary
+      {{- end }}
   template:
     metadata:
       labels:
         app.kubernetes.io/name: {{ block "componentName" . }}{{ end }}
         app.kubernetes.io/instance: {{ .Instance.Name }}-{{ block "componentName" . }}{{ end }}
-        app.kubernetes.io/version: {{ .Instance.Spec.Version }}
+        app.kubernetes.io/version: {{ $version }}
         app.kubernetes.io/component: {{ block "componentType" . }}{{ end }}
         app.kubernetes.io/part-of: {{ .Instance.Name }}
         app.kubernetes.io/managed-by: summon-operator
         metrics-enabled: {{ block "metricsEnabled" . }}{{ end }}
+        {{- if .Extra.canary }}
+        summon.ridecell.io/track: canary
+        {{- end }}
       annotations:
         summon.ridecell.io/appSecretsHash: {{ .Extra.appSecretsHash }}
         summon.ridecell.io/configHash: {{ .Extra.configHash }}
@@ -50,7 +64,7 @@ spec:
       - name: pull-secret
       containers:
       - name: default
-        image: us.gcr.io/ridecell-1/summon:{{ .Instance.Spec.Version }}
+        image: us.gcr.io/ridecell-1/summon:{{ $version }}
         imagePullPolicy: Always
         command: {{ block "command" . }}[]{{ end }}
         ports: {{ block "deploymentPorts" . }}[{containerPort: 8000}]{{ end }}
@@ -23,7 +23,7 @@ spec:
       paths:
       - path: {{ block "ingressPath" . }}{{ end }}
         backend:
-          serviceName: {{ .Instance.Name }}-{{ block "componentName" . }}{{ end }}
+          serviceName: {{ .Instance.Name }}-{{ block "backendName" . }}{{ block "componentName" . }}{{ end }}{{ end }}
           servicePort: 8000
   {{- range .Instance.Spec.Aliases }}
   - host: {{.}}
@@ -31,7 +31,7 @@ spec:
       paths:
       - path: {{ block "ingressPath" $ }}{{ end }}
         backend:
-          serviceName: {{ $.Instance.Name }}-{{ block "componentName" $ }}{{ end }}
+          serviceName: {{ $.Instance.Name }}-{{ block "backendName" $ }}{{ block "componentName" $ }}{{ end }}{{ end }}
           servicePort: 8000
   {{- end }}
   tls:
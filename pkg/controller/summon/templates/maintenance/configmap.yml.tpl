@@ -0,0 +1,35 @@
+{{ if .Instance.Spec.Maintenance.Enabled }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Instance.Name }}-maintenance
+  namespace: {{ .Instance.Namespace }}
+  labels:
+    app.kubernetes.io/name: maintenance
+    app.kubernetes.io/instance: {{ .Instance.Name }}-maintenance
+    app.kubernetes.io/component: web
+    app.kubernetes.io/part-of: {{ .Instance.Name }}
+    app.kubernetes.io/managed-by: summon-operator
+data:
+  default.conf: |
+    server {
+      listen 8080;
+      root /usr/share/nginx/html;
+      error_page 503 /index.html;
+      location = /index.html {
+        internal;
+      }
+      location / {
+        return 503;
+      }
+    }
+  index.html: |
+    <!DOCTYPE html>
+    <html>
+    <head><title>{{ .Instance.Spec.Hostname }} is down for maintenance</title></head>
+    <body>
+    <h1>{{ .Instance.Spec.Hostname }} is down for maintenance</h1>
+    <p>{{ .Instance.Spec.Maintenance.Message | html }}</p>
+    </body>
+    </html>
+{{ end }}
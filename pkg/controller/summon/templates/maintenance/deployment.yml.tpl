@@ -0,0 +1,62 @@
+{{ if .Instance.Spec.Maintenance.Enabled }}
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Instance.Name }}-maintenance
+  namespace: {{ .Instance.Namespace }}
+  labels:
+    app.kubernetes.io/name: maintenance
+    app.kubernetes.io/instance: {{ .Instance.Name }}-maintenance
+    app.kubernetes.io/component: web
+    app.kubernetes.io/part-of: {{ .Instance.Name }}
+    app.kubernetes.io/managed-by: summon-operator
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/instance: {{ .Instance.Name }}-maintenance
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: maintenance
+        app.kubernetes.io/instance: {{ .Instance.Name }}-maintenance
+        app.kubernetes.io/component: web
+        app.kubernetes.io/part-of: {{ .Instance.Name }}
+        app.kubernetes.io/managed-by: summon-operator
+    spec:
+      containers:
+      - name: default
+        image: nginx:stable-alpine
+        ports:
+        - containerPort: 8080
+        # Whole directories rather than subPath mounts, so edits to the message reach the
+        # running pod.
+        volumeMounts:
+        - name: page
+          mountPath: /usr/share/nginx/html
+        - name: conf
+          mountPath: /etc/nginx/conf.d
+        resources:
+          requests:
+            memory: 10M
+            cpu: 5m
+          limits:
+            memory: 20M
+        readinessProbe:
+          tcpSocket:
+            port: 8080
+          periodSeconds: 5
+      volumes:
+      - name: page
+        configMap:
+          name: {{ .Instance.Name }}-maintenance
+          items:
+          - key: index.html
+            path: index.html
+      - name: conf
+        configMap:
+          name: {{ .Instance.Name }}-maintenance
+          items:
+          - key: default.conf
+            path: default.conf
+{{ end }}
@@ -0,0 +1,4 @@
+{{ define "componentName" }}maintenance{{ end }}
+{{ define "componentType" }}web{{ end }}
+{{ define "servicePorts" }}[{protocol: TCP, port: 8000, targetPort: 8080}]{{ end }}
+{{ if .Instance.Spec.Maintenance.Enabled }}{{ template "service" . }}{{ end }}
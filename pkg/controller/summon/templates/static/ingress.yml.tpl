@@ -1,4 +1,5 @@
 {{ define "componentName" }}static{{ end }}
 {{ define "componentType" }}web{{ end }}
+{{ define "backendName" }}{{ if .Instance.Spec.Maintenance.Enabled }}maintenance{{ else }}static{{ end }}{{ end }}
 {{ define "ingressPath" }}/static{{ end }}
 {{ template "ingress" . }}
@@ -0,0 +1,8 @@
+{{ define "componentName" }}web{{ end }}
+{{ define "componentType" }}web{{ end }}
+{{ define "command" }}{{ template "webCommand" . }}{{ end }}
+{{ define "deploymentPorts" }}{{ template "webPorts" . }}{{ end }}
+{{ define "metricsEnabled" }}{{ template "webMetricsEnabled" . }}{{ end }}
+{{ define "resources" }}{{ template "webResources" . }}{{ end }}
+{{ define "containerExtra" }}{{ template "webContainerExtra" . }}{{ end }}
+{{ if .Extra.canary }}{{ template "deployment" . }}{{ end }}
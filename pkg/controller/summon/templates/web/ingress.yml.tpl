@@ -1,8 +1,9 @@
 {{ define "componentName" }}web{{ end }}
 {{ define "componentType" }}web{{ end }}
+{{ define "backendName" }}{{ if .Instance.Spec.Maintenance.Enabled }}maintenance{{ else }}web{{ end }}{{ end }}
 {{ define "ingressPath" }}/{{ end }}
 {{ template "ingress" . }}
 {{ define "extraAnnotations" }}
     monitor.ridecell.io/healthz-url: "{{ .Instance.Spec.Config.WEB_URL.String }}/healthz"
-    monitor.ridecell.io/should-be-probed: "true"
+    monitor.ridecell.io/should-be-probed: "{{ not .Instance.Spec.Maintenance.Enabled }}"
 {{ end }}
\ No newline at end of file
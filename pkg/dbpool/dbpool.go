@@ -19,25 +19,25 @@ package dbpool
 import (
 	"database/sql"
 	"fmt"
-	"regexp"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 
 	// this is the database/sql postgres driver
 	_ "github.com/lib/pq"
+
+	"github.com/Ridecell/ridecell-operator/pkg/logging"
 )
 
 var Dbs sync.Map
 
-var passwordRe *regexp.Regexp
-
-func init() {
-	passwordRe = regexp.MustCompile(`(password='[^']*')|(password=\S+)`)
-}
+// When each key in Dbs was last asked for, see Recent.
+var lastUsed sync.Map
 
 func Open(driverName, dataSourceName string) (*sql.DB, error) {
 	key := fmt.Sprintf("%s %s", driverName, dataSourceName)
+	lastUsed.Store(key, time.Now())
 	// First pass, check if the key is available at all.
 	mapVal, ok := Dbs.Load(key)
 	if ok {
@@ -46,7 +46,7 @@ func Open(driverName, dataSourceName string) (*sql.DB, error) {
 	}
 
 	// Don't log passwords.
-	dataSourceNameForLogging := passwordRe.ReplaceAllString(dataSourceName, "password='[redacted]'")
+	dataSourceNameForLogging := logging.Redact(dataSourceName)
 	glog.V(3).Infof("dbpool: opening database connection: %s %s", driverName, dataSourceNameForLogging)
 
 	db, err := sql.Open(driverName, dataSourceName)
@@ -64,3 +64,16 @@ func Open(driverName, dataSourceName string) (*sql.DB, error) {
 		return db, nil
 	}
 }
+
+// Call f for each pool which has been opened within the last window. Pools are never closed, so
+// this skips ones nothing has asked for in a while, like those for deleted objects.
+func Recent(window time.Duration, f func(key string, db *sql.DB)) {
+	cutoff := time.Now().Add(-window)
+	Dbs.Range(func(key, value interface{}) bool {
+		used, ok := lastUsed.Load(key)
+		if ok && used.(time.Time).After(cutoff) {
+			f(key.(string), value.(*sql.DB))
+		}
+		return true
+	})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"time"
+)
+
+// The broad kind of an error, used to decide how the reconciler retries and whether anyone gets
+// notified about it.
+type Class string
+
+const (
+	// An error with no explicit class. Handled like a transient error.
+	ClassUnknown Class = "unknown"
+	// Something that will probably go away on its own, like a network blip or an API outage.
+	ClassTransient Class = "transient"
+	// A problem with the object itself that retrying won't fix, like an invalid spec.
+	ClassPermanent Class = "permanent"
+	// Waiting for another object or external resource to become ready.
+	ClassWaiting Class = "waiting"
+	// An external API asked us to slow down.
+	ClassRateLimited Class = "rate_limited"
+)
+
+// How the reconciler responds to each class of error.
+type Policy struct {
+	// Requeue through the workqueue's rate limiter so its exponential backoff applies. The
+	// error itself is never returned to the controller, only recorded in the status.
+	Backoff bool
+	// Try again after a fixed delay instead. Zero with Backoff unset means only retry when the
+	// object changes or the cache resyncs.
+	RequeueAfter time.Duration
+	// Send notifications about the error.
+	Notify bool
+}
+
+// The policy for each error class. Exposed as a var so tests can shorten the delays.
+var Policies = map[Class]Policy{
+	ClassUnknown:     {Backoff: true, Notify: true},
+	ClassTransient:   {Backoff: true, Notify: true},
+	ClassPermanent:   {Notify: true},
+	ClassWaiting:     {RequeueAfter: 15 * time.Second},
+	ClassRateLimited: {RequeueAfter: time.Minute},
+}
+
+type classified struct {
+	error
+	class        Class
+	requeueAfter time.Duration
+}
+
+func (c *classified) Cause() error {
+	cause, ok := c.error.(causer)
+	if ok {
+		return cause.Cause()
+	} else {
+		return c.error
+	}
+}
+
+// An error that will likely clear up by itself, retried with exponential backoff.
+func Transient(err error) error {
+	return &classified{error: err, class: ClassTransient}
+}
+
+// An error which needs a human to fix, only retried when the object changes.
+func Permanent(err error) error {
+	return &classified{error: err, class: ClassPermanent}
+}
+
+// A spec which failed validation. Retrying won't help until someone fixes the spec, so this is
+// permanent.
+func InvalidSpec(err error) error {
+	return Permanent(err)
+}
+
+// An error while waiting on a dependency, retried on a fixed delay without notifying.
+func Waiting(err error) error {
+	return &classified{error: err, class: ClassWaiting}
+}
+
+// An error from an API which is throttling us. The retry delay overrides the default policy if
+// non-zero, e.g. from a Retry-After header.
+func RateLimited(err error, retryAfter time.Duration) error {
+	return &classified{error: err, class: ClassRateLimited, requeueAfter: retryAfter}
+}
+
+// Find the class of an error. The outermost class wins, so wrapping a classified error in
+// another class overrides it.
+func ClassOf(err error) Class {
+	c := findClassified(err)
+	if c == nil {
+		return ClassUnknown
+	}
+	return c.class
+}
+
+// Get the retry and notification policy for an error.
+func PolicyFor(err error) Policy {
+	c := findClassified(err)
+	if c == nil {
+		return Policies[ClassUnknown]
+	}
+	policy := Policies[c.class]
+	if c.requeueAfter != 0 {
+		policy.RequeueAfter = c.requeueAfter
+	}
+	return policy
+}
+
+func findClassified(err error) *classified {
+	for err != nil {
+		c, ok := err.(*classified)
+		if ok {
+			return c
+		}
+		err = unwrap(err)
+	}
+	return nil
+}
+
+// Step one level into a chain of wrapped errors. Our own wrappers return the wrapped error
+// directly because their Cause jumps all the way to the root.
+func unwrap(err error) error {
+	switch e := err.(type) {
+	case *classified:
+		return e.error
+	case *noNotify:
+		return e.error
+	case causer:
+		return e.Cause()
+	}
+	return nil
+}
@@ -48,17 +48,14 @@ func (n *noNotify) Cause() error {
 	}
 }
 
+// Check if an error should send notifications. An explicit NoNotify anywhere in the chain
+// always wins, otherwise it depends on the error's class.
 func ShouldNotify(err error) bool {
-	for err != nil {
-		_, ok := err.(*noNotify)
+	for e := err; e != nil; e = unwrap(e) {
+		_, ok := e.(*noNotify)
 		if ok {
 			return false
 		}
-		cause, ok := err.(causer)
-		if !ok {
-			break
-		}
-		err = cause.Cause()
 	}
-	return true
+	return PolicyFor(err).Notify
 }
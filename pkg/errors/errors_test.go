@@ -18,6 +18,7 @@ package errors_test
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -88,4 +89,51 @@ var _ = Describe("Errors", func() {
 			Expect(errors.Cause(err2)).To(Equal(err))
 		})
 	})
+
+	Describe("classes", func() {
+		It("treats a plain error as unknown with backoff", func() {
+			err := errors.New("test error")
+			Expect(errors.ClassOf(err)).To(Equal(errors.ClassUnknown))
+			Expect(errors.PolicyFor(err).Backoff).To(BeTrue())
+			Expect(errors.ShouldNotify(err)).To(BeTrue())
+		})
+
+		It("finds a class through wrapping", func() {
+			err := errors.New("test error")
+			err2 := errors.Wrap(errors.Permanent(err), "outer error")
+			Expect(errors.ClassOf(err2)).To(Equal(errors.ClassPermanent))
+			Expect(errors.PolicyFor(err2)).To(Equal(errors.Policy{Notify: true}))
+			Expect(err2).To(MatchError("outer error: test error"))
+			Expect(errors.Cause(err2)).To(Equal(err))
+		})
+
+		It("uses the outermost class", func() {
+			err := errors.Transient(errors.Wrap(errors.Waiting(errors.New("test error")), "outer error"))
+			Expect(errors.ClassOf(err)).To(Equal(errors.ClassTransient))
+		})
+
+		It("does not notify for waiting errors", func() {
+			err := errors.Wrap(errors.Waiting(errors.New("test error")), "outer error")
+			Expect(errors.ShouldNotify(err)).To(BeFalse())
+			Expect(errors.PolicyFor(err).RequeueAfter).To(Equal(15 * time.Second))
+		})
+
+		It("uses the retry delay from a rate limited error", func() {
+			err := errors.RateLimited(errors.New("slow down"), 5*time.Minute)
+			Expect(errors.PolicyFor(err)).To(Equal(errors.Policy{RequeueAfter: 5 * time.Minute}))
+			Expect(errors.PolicyFor(errors.RateLimited(err, 0)).RequeueAfter).To(Equal(time.Minute))
+		})
+
+		It("treats an invalid spec as permanent", func() {
+			err := errors.InvalidSpec(errors.New("bad spec"))
+			Expect(err).To(MatchError("bad spec"))
+			Expect(errors.ClassOf(err)).To(Equal(errors.ClassPermanent))
+		})
+
+		It("lets NoNotify override the class", func() {
+			err := errors.Permanent(errors.NoNotify(errors.New("test error")))
+			Expect(errors.ClassOf(err)).To(Equal(errors.ClassPermanent))
+			Expect(errors.ShouldNotify(err)).To(BeFalse())
+		})
+	})
 })
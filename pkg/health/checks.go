@@ -0,0 +1,139 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"github.com/Ridecell/ridecell-operator/pkg/components"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
+	"github.com/Ridecell/ridecell-operator/pkg/dbpool"
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	"github.com/Ridecell/ridecell-operator/pkg/logging"
+	"github.com/Ridecell/ridecell-operator/pkg/utils"
+	"github.com/Ridecell/ridecell-operator/pkg/utils/gcr"
+)
+
+// How recently a Postgres pool must have been used for the postgres check to cover it. Pools
+// are never closed, so this is what keeps ones for deleted instances out of the report.
+var PostgresActiveWindow = time.Hour
+
+// Maximum time for one Postgres ping, well under CheckTimeout so one dead server doesn't hide
+// the results for the rest.
+var PostgresPingTimeout = 2 * time.Second
+
+// Add the checks the operator needs to be ready to a Checker.
+func AddReadinessChecks(checker *Checker, informers cache.Informers) {
+	checker.AddCheck("cache", CacheSynced(informers))
+}
+
+// Add the external dependency checks to a Checker. A dependency being down only breaks the
+// objects which use it, so these shouldn't be used for readiness.
+func AddDependencyChecks(checker *Checker) {
+	checker.AddCheck("rabbitmq", RabbitMQ)
+	checker.AddCheck("postgres", Postgres)
+	checker.AddCheck("aws", AWSCredentials)
+	checker.AddCheck("registry", Registry)
+}
+
+// Check that the manager's informer caches have synced with the API server.
+func CacheSynced(informers cache.Informers) Check {
+	return func(ctx context.Context) error {
+		if !informers.WaitForCacheSync(ctx.Done()) {
+			return errors.New("caches not synced")
+		}
+		return nil
+	}
+}
+
+// Check that the RabbitMQ management API from RABBITMQ_URI answers with our credentials.
+func RabbitMQ(ctx context.Context) error {
+	if config.Get("RABBITMQ_URI") == "" {
+		return ErrSkipped
+	}
+	client, err := utils.OpenRabbit(&components.ComponentContext{Context: ctx}, nil, utils.RabbitholeClientFactory)
+	if err != nil {
+		return err
+	}
+	_, err = client.ListVhosts()
+	if err != nil {
+		return errors.Wrap(err, "error listing vhosts")
+	}
+	return nil
+}
+
+// Check every recently used pooled Postgres connection still works.
+func Postgres(ctx context.Context) error {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	failures := []string{}
+	count := 0
+	dbpool.Recent(PostgresActiveWindow, func(key string, db *sql.DB) {
+		count++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, PostgresPingTimeout)
+			defer cancel()
+			err := db.PingContext(pingCtx)
+			if err != nil {
+				lock.Lock()
+				failures = append(failures, logging.Redact(key)+": "+err.Error())
+				lock.Unlock()
+			}
+		}()
+	})
+	wg.Wait()
+	if count == 0 {
+		return ErrSkipped
+	}
+	if len(failures) != 0 {
+		sort.Strings(failures)
+		return errors.Errorf("%d of %d connections failed: %s", len(failures), count, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Check the AWS credentials are valid by asking STS who they belong to.
+func AWSCredentials(ctx context.Context) error {
+	sess, err := session.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "error creating AWS session")
+	}
+	_, err = sts.New(sess).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Wrap(err, "error getting caller identity")
+	}
+	return nil
+}
+
+// Check the image registry used for autodeploys accepts our credentials.
+func Registry(ctx context.Context) error {
+	if config.Get("GOOGLE_SERVICE_ACCOUNT_KEY") == "" && config.Get("LOCAL_REGISTRY_URL") == "" {
+		return ErrSkipped
+	}
+	return gcr.Ping(ctx)
+}
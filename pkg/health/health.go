@@ -0,0 +1,194 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	"github.com/Ridecell/ridecell-operator/pkg/logging"
+)
+
+// Returned by a check when the dependency isn't configured for this operator, so there is
+// nothing to test. Skipped checks don't fail the probe.
+var ErrSkipped = errors.New("not configured")
+
+// Maximum time for a single check.
+var CheckTimeout = 10 * time.Second
+
+// How long readiness results are reused for, so frequent probes don't hammer the dependencies.
+var CacheFor = 30 * time.Second
+
+// A Check tests one dependency and returns nil if it is usable.
+type Check func(context.Context) error
+
+// Result of one check, as served in the JSON body.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Response body for both endpoints.
+type Report struct {
+	Status string                 `json:"status"`
+	Time   string                 `json:"time"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// A named set of checks served over HTTP, see Handler.
+type Checker struct {
+	mutex  sync.Mutex
+	checks map[string]Check
+	// Last report and when it expires, for CacheFor.
+	cached  *Report
+	expires time.Time
+	// Disable caching, for liveness checks which should be cheap anyway.
+	noCache bool
+	// Always serve a 200, for reports which are only there to be looked at and must never
+	// be used as a probe.
+	informational bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{checks: map[string]Check{}}
+}
+
+func (c *Checker) AddCheck(name string, check Check) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checks[name] = check
+	c.cached = nil
+}
+
+// Run all the checks concurrently, or return the cached report if it's still fresh.
+func (c *Checker) Run(ctx context.Context) *Report {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.cached != nil && !c.noCache && time.Now().Before(c.expires) {
+		return c.cached
+	}
+
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, c.checks[name])
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusOK, Time: time.Now().UTC().Format(time.RFC3339), Checks: map[string]CheckResult{}}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status == StatusFailed {
+			report.Status = StatusFailed
+		}
+	}
+	c.cached = report
+	c.expires = time.Now().Add(CacheFor)
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) CheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+	start := time.Now()
+	err := check(checkCtx)
+	result := CheckResult{Status: StatusOK, Duration: time.Since(start).Round(time.Millisecond).String()}
+	if err == ErrSkipped {
+		result.Status = StatusSkipped
+	} else if err != nil {
+		result.Status = StatusFailed
+		result.Error = logging.Redact(err.Error())
+	}
+	return result
+}
+
+// Serve the report as JSON, with a 503 if any check failed unless the checker is informational.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != StatusOK && !c.informational {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	err := json.NewEncoder(w).Encode(report)
+	if err != nil {
+		logging.Log.WithName("health").Error(err, "Error writing health report")
+	}
+}
+
+// The probe endpoints for the manager. Liveness only covers the operator process itself, so a
+// broken dependency can't restart it over and over. Readiness only covers what the operator
+// needs to serve webhooks, since the webhook goes down with the pod. Everything external goes
+// in Dependencies, which is informational.
+type Server struct {
+	Liveness     *Checker
+	Readiness    *Checker
+	Dependencies *Checker
+}
+
+func NewServer() *Server {
+	liveness := NewChecker()
+	liveness.noCache = true
+	dependencies := NewChecker()
+	dependencies.informational = true
+	return &Server{Liveness: liveness, Readiness: NewChecker(), Dependencies: dependencies}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", s.Liveness)
+	mux.Handle("/readyz", s.Readiness)
+	mux.Handle("/dependencies", s.Dependencies)
+	return mux
+}
+
+// Serve the probes on addr until stop is closed.
+func (s *Server) Start(addr string, stop <-chan struct{}) error {
+	server := &http.Server{Addr: addr, Handler: s.Handler()}
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx) //nolint
+	}()
+	err := server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		return errors.Wrapf(err, "error serving health checks on %s", addr)
+	}
+	return nil
+}
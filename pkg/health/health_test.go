@@ -0,0 +1,114 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+	"github.com/Ridecell/ridecell-operator/pkg/health"
+)
+
+var _ = Describe("Health checks", func() {
+	var server *health.Server
+
+	BeforeEach(func() {
+		server = health.NewServer()
+	})
+
+	get := func(path string) (int, *health.Report) {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		report := &health.Report{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), report)).To(Succeed())
+		return recorder.Code, report
+	}
+
+	It("is live with no checks", func() {
+		code, report := get("/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(report.Status).To(Equal(health.StatusOK))
+	})
+
+	It("reports each readiness check", func() {
+		server.Readiness.AddCheck("good", func(_ context.Context) error { return nil })
+		server.Readiness.AddCheck("unused", func(_ context.Context) error { return health.ErrSkipped })
+		code, report := get("/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(report.Status).To(Equal(health.StatusOK))
+		Expect(report.Checks["good"].Status).To(Equal(health.StatusOK))
+		Expect(report.Checks["unused"].Status).To(Equal(health.StatusSkipped))
+	})
+
+	It("is not ready when a check fails", func() {
+		server.Readiness.AddCheck("good", func(_ context.Context) error { return nil })
+		server.Readiness.AddCheck("bad", func(_ context.Context) error {
+			return errors.New("connecting to postgres://user:hunter2@db/app failed")
+		})
+		code, report := get("/readyz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(report.Status).To(Equal(health.StatusFailed))
+		Expect(report.Checks["bad"].Status).To(Equal(health.StatusFailed))
+		Expect(report.Checks["bad"].Error).ToNot(ContainSubstring("hunter2"))
+		Expect(report.Checks["good"].Status).To(Equal(health.StatusOK))
+	})
+
+	It("stops slow checks at the timeout", func() {
+		defer func(timeout time.Duration) { health.CheckTimeout = timeout }(health.CheckTimeout)
+		health.CheckTimeout = 10 * time.Millisecond
+		server.Readiness.AddCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		code, report := get("/readyz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(report.Checks["slow"].Error).To(ContainSubstring("deadline"))
+	})
+
+	It("reports failed dependencies without failing the request", func() {
+		server.Dependencies.AddCheck("bad", func(_ context.Context) error { return errors.New("rabbitmq is down") })
+		code, report := get("/dependencies")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(report.Status).To(Equal(health.StatusFailed))
+		Expect(report.Checks["bad"].Error).To(Equal("rabbitmq is down"))
+
+		code, _ = get("/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+	})
+
+	It("skips the postgres check with no recently used pools", func() {
+		Expect(health.Postgres(context.Background())).To(Equal(health.ErrSkipped))
+	})
+
+	It("reuses readiness results for a while", func() {
+		calls := 0
+		server.Readiness.AddCheck("counted", func(_ context.Context) error {
+			calls++
+			return nil
+		})
+		get("/readyz")
+		get("/readyz")
+		Expect(calls).To(Equal(1))
+	})
+})
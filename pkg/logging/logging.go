@@ -0,0 +1,53 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Structured logging for the operator. Loggers are logr interfaces backed by zap, with every
+// message, value and error passed through Redact before it is written.
+package logging
+
+import (
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The root logger. Replaced by SetLogger when the manager starts, the default writes human
+// readable logs to stderr.
+var Log logr.Logger = New(false, 0)
+
+// Build a new redacting logger. JSON output is meant for shipping to a log search system, the
+// console format is easier to read locally. Higher verbosity enables more V(n) messages.
+func New(json bool, verbosity int) logr.Logger {
+	var encoder zapcore.Encoder
+	if json {
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	}
+	level := zap.NewAtomicLevelAt(zapcore.Level(-verbosity))
+	core := zapcore.NewCore(encoder, zapcore.AddSync(os.Stderr), level)
+	return Redacting(zapr.NewLogger(zap.New(core)))
+}
+
+// Replace the root logger.
+func SetLogger(logger logr.Logger) {
+	Log = logger
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/go-logr/logr"
+)
+
+const redacted = "[redacted]"
+
+// Secrets embedded in strings, like a DSN's password='hunter2', an env-style TOKEN=abc or a
+// URL's user:pass@. Only passwords match with a colon too, "secret: ..." is far too common in
+// ordinary error messages.
+var passwordRe = regexp.MustCompile(`(?i)(passw(?:or)?d\w*)(=|:\s*)('[^']*'|"[^"]*"|[^\s,;&]+)`)
+var secretRe = regexp.MustCompile(`(?i)((?:secret|token|api_?key)\w*)(=)('[^']*'|"[^"]*"|[^\s,;&]+)`)
+var urlPasswordRe = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`)
+
+// Keys whose values are always hidden, whatever they look like.
+var secretKeyRe = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|api_?key)`)
+
+// Remove anything which looks like a secret from a string.
+func Redact(s string) string {
+	s = passwordRe.ReplaceAllString(s, "$1$2"+redacted)
+	s = secretRe.ReplaceAllString(s, "$1$2"+redacted)
+	s = urlPasswordRe.ReplaceAllString(s, "$1"+redacted+"@")
+	return s
+}
+
+type redactedError struct {
+	msg string
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Redact a list of alternating keys and values, returning a copy.
+func redactValues(keysAndValues []interface{}) []interface{} {
+	out := make([]interface{}, len(keysAndValues))
+	for i, val := range keysAndValues {
+		if i%2 == 1 {
+			key, ok := keysAndValues[i-1].(string)
+			if ok && secretKeyRe.MatchString(key) {
+				out[i] = redacted
+				continue
+			}
+		}
+		switch v := val.(type) {
+		case string:
+			out[i] = Redact(v)
+		case error:
+			out[i] = &redactedError{msg: Redact(v.Error())}
+		case fmt.Stringer:
+			out[i] = Redact(v.String())
+		default:
+			out[i] = val
+		}
+	}
+	return out
+}
+
+// Wrap a logger so everything sent through it is redacted.
+func Redacting(logger logr.Logger) logr.Logger {
+	return &redactingLogger{logger: logger}
+}
+
+type redactingLogger struct {
+	logger logr.Logger
+}
+
+func (l *redactingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Info(Redact(msg), redactValues(keysAndValues)...)
+}
+
+func (l *redactingLogger) Enabled() bool {
+	return l.logger.Enabled()
+}
+
+func (l *redactingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	if err != nil {
+		err = &redactedError{msg: Redact(err.Error())}
+	}
+	l.logger.Error(err, Redact(msg), redactValues(keysAndValues)...)
+}
+
+func (l *redactingLogger) V(level int) logr.InfoLogger {
+	return &redactingInfoLogger{logger: l.logger.V(level)}
+}
+
+func (l *redactingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &redactingLogger{logger: l.logger.WithValues(redactValues(keysAndValues)...)}
+}
+
+func (l *redactingLogger) WithName(name string) logr.Logger {
+	return &redactingLogger{logger: l.logger.WithName(name)}
+}
+
+type redactingInfoLogger struct {
+	logger logr.InfoLogger
+}
+
+func (l *redactingInfoLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Info(Redact(msg), redactValues(keysAndValues)...)
+}
+
+func (l *redactingInfoLogger) Enabled() bool {
+	return l.logger.Enabled()
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule decides whether something should be running at a given time, from either
+// weekly time windows or a pair of cron expressions.
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ridecell/ridecell-operator/pkg/errors"
+)
+
+// How far to look for the next change. Every schedule repeats weekly, so anything further out
+// means it never changes.
+const horizon = 8 * 24 * time.Hour
+
+// A Schedule of when something should be awake.
+type Schedule interface {
+	// Check if t is inside the schedule, and find the next time that changes. The zero time means
+	// it never changes.
+	Awake(t time.Time) (awake bool, next time.Time)
+}
+
+var dayNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+type window struct {
+	days       [7]bool
+	start, end int // Minutes since midnight.
+}
+
+type windowSchedule struct {
+	windows []window
+	loc     *time.Location
+}
+
+// Parse awake windows like "Mon-Fri 08:00-20:00", "Sat,Sun 10:00-14:00" or "22:00-06:00". Days
+// are optional and default to every day. A window ending before it starts runs past midnight.
+func ParseWindows(specs []string, loc *time.Location) (Schedule, error) {
+	sched := &windowSchedule{loc: loc}
+	for _, spec := range specs {
+		w, err := parseWindow(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "schedule: invalid window %#v", spec)
+		}
+		sched.windows = append(sched.windows, w)
+	}
+	return sched, nil
+}
+
+func parseWindow(spec string) (window, error) {
+	w := window{}
+	fields := strings.Fields(spec)
+	var times string
+	switch len(fields) {
+	case 1:
+		times = fields[0]
+		for i := range w.days {
+			w.days[i] = true
+		}
+	case 2:
+		times = fields[1]
+		for _, part := range strings.Split(fields[0], ",") {
+			bounds := strings.SplitN(part, "-", 2)
+			first, ok := dayNames[strings.ToLower(bounds[0])]
+			if !ok {
+				return w, errors.Errorf("unknown day %#v", bounds[0])
+			}
+			last := first
+			if len(bounds) == 2 {
+				last, ok = dayNames[strings.ToLower(bounds[1])]
+				if !ok {
+					return w, errors.Errorf("unknown day %#v", bounds[1])
+				}
+			}
+			// Ranges can wrap around the weekend, like Fri-Mon.
+			for day := first; ; day = (day + 1) % 7 {
+				w.days[day] = true
+				if day == last {
+					break
+				}
+			}
+		}
+	default:
+		return w, errors.New("expected [days] HH:MM-HH:MM")
+	}
+
+	bounds := strings.SplitN(times, "-", 2)
+	if len(bounds) != 2 {
+		return w, errors.New("expected HH:MM-HH:MM")
+	}
+	var err error
+	w.start, err = parseClock(bounds[0])
+	if err != nil {
+		return w, err
+	}
+	w.end, err = parseClock(bounds[1])
+	if err != nil {
+		return w, err
+	}
+	if w.start == w.end {
+		return w, errors.New("window is empty")
+	}
+	return w, nil
+}
+
+func parseClock(clock string) (int, error) {
+	parts := strings.SplitN(clock, ":", 2)
+	if len(parts) != 2 {
+		return 0, errors.Errorf("invalid time %#v", clock)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 24 {
+		return 0, errors.Errorf("invalid time %#v", clock)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, errors.Errorf("invalid time %#v", clock)
+	}
+	return hour*60 + minute, nil
+}
+
+func (s *windowSchedule) awakeAt(t time.Time) bool {
+	local := t.In(s.loc)
+	day := int(local.Weekday())
+	minute := local.Hour()*60 + local.Minute()
+	for _, w := range s.windows {
+		if w.start < w.end {
+			if w.days[day] && minute >= w.start && minute < w.end {
+				return true
+			}
+		} else {
+			// Overnight, so the early part belongs to the window which started the day before.
+			if w.days[day] && minute >= w.start {
+				return true
+			}
+			if w.days[(day+6)%7] && minute < w.end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *windowSchedule) Awake(t time.Time) (bool, time.Time) {
+	awake := s.awakeAt(t)
+	return awake, nextChange(t, awake, func(t time.Time) (bool, bool) { return s.awakeAt(t), true })
+}
+
+type cronSchedule struct {
+	wake, sleep *cronExpr
+	loc         *time.Location
+}
+
+// Parse a pair of standard five field cron expressions for when to wake up and when to go to
+// sleep, like "0 8 * * 1-5" and "0 20 * * 1-5".
+func ParseCron(wake, sleep string, loc *time.Location) (Schedule, error) {
+	wakeExpr, err := parseCron(wake)
+	if err != nil {
+		return nil, errors.Wrapf(err, "schedule: invalid wake schedule %#v", wake)
+	}
+	sleepExpr, err := parseCron(sleep)
+	if err != nil {
+		return nil, errors.Wrapf(err, "schedule: invalid sleep schedule %#v", sleep)
+	}
+	return &cronSchedule{wake: wakeExpr, sleep: sleepExpr, loc: loc}, nil
+}
+
+// Check if t is a wake or sleep event. Waking wins if both match.
+func (s *cronSchedule) eventAt(t time.Time) (awake bool, ok bool) {
+	local := t.In(s.loc)
+	if s.wake.matches(local) {
+		return true, true
+	}
+	if s.sleep.matches(local) {
+		return false, true
+	}
+	return false, false
+}
+
+func (s *cronSchedule) Awake(t time.Time) (bool, time.Time) {
+	// The state is set by the most recent event. Stay awake if there hasn't been one.
+	awake := true
+	minute := t.Truncate(time.Minute)
+	for check := minute; check.After(minute.Add(-horizon)); check = check.Add(-time.Minute) {
+		event, ok := s.eventAt(check)
+		if ok {
+			awake = event
+			break
+		}
+	}
+	return awake, nextChange(t, awake, s.eventAt)
+}
+
+// Walk forward a minute at a time to the first event which changes the state.
+func nextChange(t time.Time, awake bool, eventAt func(time.Time) (bool, bool)) time.Time {
+	start := t.Truncate(time.Minute).Add(time.Minute)
+	for check := start; check.Before(start.Add(horizon)); check = check.Add(time.Minute) {
+		event, ok := eventAt(check)
+		if ok && event != awake {
+			return check
+		}
+	}
+	return time.Time{}
+}
+
+type cronExpr struct {
+	minute, hour, dom, month, dow []bool
+	// Cron matches either day field when both are restricted, see isWildcard.
+	domAny, dowAny bool
+}
+
+func parseCron(expr string) (*cronExpr, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.New("expected 5 fields")
+	}
+	c := &cronExpr{}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	dowField := strings.ToLower(fields[4])
+	for name, day := range dayNames {
+		dowField = strings.Replace(dowField, name, strconv.Itoa(day), -1)
+	}
+	if c.dow, err = parseCronField(dowField, 0, 7); err != nil {
+		return nil, err
+	}
+	// 7 is another way to write Sunday.
+	c.dow[0] = c.dow[0] || c.dow[7]
+	c.domAny = isWildcard(fields[2])
+	c.dowAny = isWildcard(fields[4])
+	return c, nil
+}
+
+// Check if a day field leaves the day unrestricted. Only a bare * does, a stepped wildcard
+// like */2 picks out some days, so it's restricted like any list or range.
+func isWildcard(field string) bool {
+	return field == "*"
+}
+
+// Parse one field of a cron expression into a lookup table indexed by value.
+func parseCronField(field string, min, max int) ([]bool, error) {
+	values := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i != -1 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return nil, errors.Errorf("invalid step in %#v", part)
+			}
+			part = part[:i]
+		}
+		first, last := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			first, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, errors.Errorf("invalid value in %#v", part)
+			}
+			last = first
+			if len(bounds) == 2 {
+				last, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return nil, errors.Errorf("invalid value in %#v", part)
+				}
+			}
+		}
+		if first < min || last > max || first > last {
+			return nil, errors.Errorf("%#v is out of range %d-%d", part, min, max)
+		}
+		for value := first; value <= last; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+func (c *cronExpr) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+	domMatch := c.dom[t.Day()]
+	dowMatch := c.dow[int(t.Weekday())]
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
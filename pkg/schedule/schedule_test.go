@@ -0,0 +1,148 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Ridecell/ridecell-operator/pkg/schedule"
+)
+
+var _ = Describe("Schedule", func() {
+	// 2019-06-03 is a Monday.
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2019, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	Describe("ParseWindows", func() {
+		It("is awake inside a weekday window", func() {
+			sched, err := schedule.ParseWindows([]string{"Mon-Fri 08:00-20:00"}, time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, next := sched.Awake(at(3, 12, 30))
+			Expect(awake).To(BeTrue())
+			Expect(next).To(Equal(at(3, 20, 0)))
+		})
+
+		It("sleeps outside the window until the next one", func() {
+			sched, err := schedule.ParseWindows([]string{"Mon-Fri 08:00-20:00"}, time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			// Friday night sleeps until Monday morning.
+			awake, next := sched.Awake(at(7, 21, 0))
+			Expect(awake).To(BeFalse())
+			Expect(next).To(Equal(at(10, 8, 0)))
+		})
+
+		It("handles windows past midnight", func() {
+			sched, err := schedule.ParseWindows([]string{"Mon 22:00-06:00"}, time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, next := sched.Awake(at(4, 2, 0))
+			Expect(awake).To(BeTrue())
+			Expect(next).To(Equal(at(4, 6, 0)))
+			awake, _ = sched.Awake(at(3, 2, 0))
+			Expect(awake).To(BeFalse())
+		})
+
+		It("defaults to every day", func() {
+			sched, err := schedule.ParseWindows([]string{"09:00-17:00"}, time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, _ := sched.Awake(at(8, 10, 0))
+			Expect(awake).To(BeTrue())
+		})
+
+		It("uses the time zone", func() {
+			loc := time.FixedZone("PDT", -7*60*60)
+			sched, err := schedule.ParseWindows([]string{"Mon-Fri 08:00-20:00"}, loc)
+			Expect(err).ToNot(HaveOccurred())
+			// 02:00 UTC on Tuesday is 19:00 Monday in PDT.
+			awake, next := sched.Awake(at(4, 2, 0))
+			Expect(awake).To(BeTrue())
+			Expect(next).To(Equal(at(4, 3, 0)))
+		})
+
+		It("rejects bad windows", func() {
+			for _, spec := range []string{"Mon-Fri", "Funday 08:00-20:00", "08:00-25:00", "08:00-08:00", "Mon 08:00 20:00"} {
+				_, err := schedule.ParseWindows([]string{spec}, time.UTC)
+				Expect(err).To(HaveOccurred(), spec)
+			}
+		})
+	})
+
+	Describe("ParseCron", func() {
+		It("follows the most recent event", func() {
+			sched, err := schedule.ParseCron("0 8 * * 1-5", "0 20 * * mon-fri", time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, next := sched.Awake(at(3, 12, 0))
+			Expect(awake).To(BeTrue())
+			Expect(next).To(Equal(at(3, 20, 0)))
+
+			// Saturday sleeps until Monday.
+			awake, next = sched.Awake(at(8, 12, 0))
+			Expect(awake).To(BeFalse())
+			Expect(next).To(Equal(at(10, 8, 0)))
+		})
+
+		It("supports steps and lists", func() {
+			sched, err := schedule.ParseCron("0,30 */2 * * *", "15,45 */2 * * *", time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, next := sched.Awake(at(3, 4, 20))
+			Expect(awake).To(BeFalse())
+			Expect(next).To(Equal(at(3, 4, 30)))
+		})
+
+		It("matches either day field when the day of month is a stepped wildcard", func() {
+			// Odd days or Mondays.
+			sched, err := schedule.ParseCron("0 8 */2 * 1", "0 20 * * *", time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, next := sched.Awake(at(4, 12, 0))
+			Expect(awake).To(BeFalse())
+			Expect(next).To(Equal(at(5, 8, 0)))
+
+			awake, _ = sched.Awake(at(10, 12, 0))
+			Expect(awake).To(BeTrue())
+		})
+
+		It("matches either day field when the day of week is a stepped wildcard", func() {
+			// The 15th or Sundays, Tuesdays, Thursdays and Saturdays.
+			sched, err := schedule.ParseCron("0 8 15 * */2", "0 20 * * *", time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, next := sched.Awake(at(3, 12, 0))
+			Expect(awake).To(BeFalse())
+			Expect(next).To(Equal(at(4, 8, 0)))
+
+			awake, _ = sched.Awake(at(4, 12, 0))
+			Expect(awake).To(BeTrue())
+		})
+
+		It("stays awake when nothing has fired", func() {
+			sched, err := schedule.ParseCron("0 8 1 1 *", "0 20 1 1 *", time.UTC)
+			Expect(err).ToNot(HaveOccurred())
+			awake, next := sched.Awake(at(3, 12, 0))
+			Expect(awake).To(BeTrue())
+			Expect(next.IsZero()).To(BeTrue())
+		})
+
+		It("rejects bad expressions", func() {
+			for _, expr := range []string{"0 8 * *", "60 8 * * *", "0 8 * * 8", "0 8-2 * * *", "0 8/0 * * *"} {
+				_, err := schedule.ParseCron(expr, "0 20 * * *", time.UTC)
+				Expect(err).To(HaveOccurred(), expr)
+			}
+		})
+	})
+})
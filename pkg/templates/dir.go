@@ -0,0 +1,138 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// The on-disk directory to load templates from instead of the built-in assets, if set.
+var overrideDir = struct {
+	sync.RWMutex
+	dir string
+}{}
+
+// A filesystem which can be swapped for a subdirectory of the override directory at runtime.
+type namedFileSystem struct {
+	name string
+	fs   http.FileSystem
+}
+
+// Wrap a built-in template filesystem so it can be replaced by UseDir. The name is the path of
+// the owning package under pkg/, so pointing UseDir at a checkout's pkg/ folder loads the
+// templates from <dir>/<name>/templates.
+//
+//	var Templates = templates.Named("controller/summon", assets)
+func Named(name string, fs http.FileSystem) http.FileSystem {
+	return &namedFileSystem{name: name, fs: fs}
+}
+
+func (n *namedFileSystem) Open(name string) (http.File, error) {
+	overrideDir.RLock()
+	dir := overrideDir.dir
+	overrideDir.RUnlock()
+	if dir != "" {
+		return http.Dir(filepath.Join(dir, n.name, "templates")).Open(name)
+	}
+	return n.fs.Open(name)
+}
+
+// Load templates for all Named filesystems from an on-disk directory and watch it for changes,
+// clearing the template cache whenever anything under it is modified. When the stop channel is
+// closed, watching stops and the built-in templates are used again.
+func UseDir(dir string, stop <-chan struct{}) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.Wrapf(err, "templates: unable to resolve %s", dir)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "templates: unable to create watcher")
+	}
+	err = watchTree(watcher, dir)
+	if err != nil {
+		watcher.Close()
+		return err
+	}
+
+	overrideDir.Lock()
+	overrideDir.dir = dir
+	overrideDir.Unlock()
+	ClearCache()
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-stop:
+				resetDir(dir)
+				return
+			case event := <-watcher.Events:
+				if event.Op&fsnotify.Create != 0 {
+					// fsnotify isn't recursive, so pick up any new folders too.
+					info, err := os.Stat(event.Name)
+					if err == nil && info.IsDir() {
+						err = watchTree(watcher, event.Name)
+						if err != nil {
+							glog.Errorf("%s", err)
+						}
+					}
+				}
+				glog.V(2).Infof("templates: %s changed, clearing cache", event.Name)
+				ClearCache()
+			case err := <-watcher.Errors:
+				glog.Errorf("templates: error watching %s: %s", dir, err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Go back to the built-in templates, unless a later UseDir replaced the directory. Pass "" to
+// reset whatever is set.
+func resetDir(dir string) {
+	overrideDir.Lock()
+	if dir == "" || overrideDir.dir == dir {
+		overrideDir.dir = ""
+	}
+	overrideDir.Unlock()
+	ClearCache()
+}
+
+// Add a directory and all of its subdirectories to a watcher.
+func watchTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		err = watcher.Add(path)
+		if err != nil {
+			return errors.Wrapf(err, "templates: unable to watch %s", path)
+		}
+		return nil
+	})
+}
@@ -19,18 +19,27 @@ package templates
 import (
 	"bytes"
 	"net/http"
+	"os"
 	"path"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"text/template"
 
 	// "github.com/golang/glog"
 	"github.com/Masterminds/sprig"
+	"github.com/pkg/errors"
 	"github.com/shurcooL/httpfs/path/vfspath"
 	"github.com/shurcooL/httpfs/vfsutil"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
+// Returned by Get when a template renders to nothing but whitespace. Templates can use this to
+// say the object should not exist, for example when a feature is turned off.
+var ErrEmptyTemplate = errors.New("template rendered to an empty document")
+
 func parseTemplate(fs http.FileSystem, filename string) (*template.Template, error) {
 	// Wrote this because if statements with pointers don't work how you'd think they would
 	customFuncMap := template.FuncMap{
@@ -77,6 +86,71 @@ func parseTemplate(fs http.FileSystem, filename string) (*template.Template, err
 	return tmpl, nil
 }
 
+// Compiled templates keyed by filesystem and path. Parsing globs and re-reads every helper, so
+// doing it once per template instead of once per Get saves a lot of work on each resync.
+type cacheKey struct {
+	fs       interface{}
+	filename string
+}
+
+var cache = struct {
+	sync.RWMutex
+	templates map[cacheKey]*template.Template
+	// Bumped by every ClearCache, so a parse which started before a clear isn't stored after it.
+	generation uint64
+}{templates: map[cacheKey]*template.Template{}}
+
+// Work out a usable map key for a filesystem. http.Dir and pointers are comparable as is, the
+// vfsgen filesystems are maps so use their pointer instead. Anything else isn't cached.
+func fsKey(fs http.FileSystem) (interface{}, bool) {
+	val := reflect.ValueOf(fs)
+	if val.Type().Comparable() {
+		return fs, true
+	}
+	switch val.Kind() {
+	case reflect.Map, reflect.Ptr, reflect.Func, reflect.Slice, reflect.Chan:
+		return val.Pointer(), true
+	}
+	return nil, false
+}
+
+// Fetch a compiled template from the cache, parsing it if needed. Executing a template is safe
+// to do concurrently so the same object is shared between all callers.
+func getTemplate(fs http.FileSystem, filename string) (*template.Template, error) {
+	key, cacheable := fsKey(fs)
+	if !cacheable {
+		return parseTemplate(fs, filename)
+	}
+	k := cacheKey{fs: key, filename: filename}
+
+	cache.RLock()
+	tmpl, ok := cache.templates[k]
+	generation := cache.generation
+	cache.RUnlock()
+	if ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := parseTemplate(fs, filename)
+	if err != nil {
+		return nil, err
+	}
+	cache.Lock()
+	if cache.generation == generation {
+		cache.templates[k] = tmpl
+	}
+	cache.Unlock()
+	return tmpl, nil
+}
+
+// Drop all compiled templates so the next Get re-reads them from their filesystem.
+func ClearCache() {
+	cache.Lock()
+	cache.templates = map[cacheKey]*template.Template{}
+	cache.generation++
+	cache.Unlock()
+}
+
 func renderTemplate(tmpl *template.Template, data interface{}) (string, error) {
 	var buffer bytes.Buffer
 	err := tmpl.Execute(&buffer, data)
@@ -98,7 +172,7 @@ func parseObject(rawObject string) (runtime.Object, error) {
 }
 
 func Get(fs http.FileSystem, filename string, data interface{}) (runtime.Object, error) {
-	tmpl, err := parseTemplate(fs, filename)
+	tmpl, err := getTemplate(fs, filename)
 	if err != nil {
 		return nil, err
 	}
@@ -106,9 +180,43 @@ func Get(fs http.FileSystem, filename string, data interface{}) (runtime.Object,
 	if err != nil {
 		return nil, err
 	}
+	if strings.TrimSpace(out) == "" {
+		return nil, ErrEmptyTemplate
+	}
 	obj, err := parseObject(out)
 	if err != nil {
 		return nil, err
 	}
 	return obj, nil
 }
+
+// Check if a template exists in a filesystem.
+func Exists(fs http.FileSystem, filename string) bool {
+	_, err := vfsutil.Stat(fs, filename)
+	return err == nil
+}
+
+// List all the renderable templates in a filesystem, skipping helpers. Paths are relative to the
+// root of the filesystem, in the same format as passed to Get.
+func List(fs http.FileSystem) ([]string, error) {
+	paths := []string{}
+	err := vfsutil.Walk(fs, "/", func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "helpers" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(filename, ".tpl") {
+			paths = append(paths, strings.TrimPrefix(filename, "/"))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
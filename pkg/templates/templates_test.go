@@ -17,7 +17,12 @@ limitations under the License.
 package templates_test
 
 import (
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -62,4 +67,131 @@ var _ = Describe("Templates", func() {
 			Expect(deployment.Spec.Replicas).To(PointTo(BeEquivalentTo(1)))
 		})
 	})
+
+	Context("a conditional template", func() {
+		It("should render the Deployment when enabled", func() {
+			rawObject, err := templates.Get(testTemplates, "test4.yml.tpl", struct{ Enabled bool }{Enabled: true})
+			Expect(err).ToNot(HaveOccurred())
+			deployment, ok := rawObject.(*appsv1.Deployment)
+			Expect(ok).To(BeTrue())
+			Expect(deployment.Name).To(Equal("test-four"))
+		})
+
+		It("should return ErrEmptyTemplate when disabled", func() {
+			_, err := templates.Get(testTemplates, "test4.yml.tpl", struct{ Enabled bool }{Enabled: false})
+			Expect(err).To(Equal(templates.ErrEmptyTemplate))
+		})
+	})
+
+	Context("listing templates", func() {
+		It("should return all templates except helpers", func() {
+			paths, err := templates.List(testTemplates)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paths).To(ConsistOf("test1.yml.tpl", "test2.yml.tpl", "test3.yml.tpl", "test4.yml.tpl"))
+		})
+	})
+
+	Context("caching", func() {
+		var tmpDir string
+		var deployment = func(name string) string {
+			raw, err := ioutil.ReadFile("test_templates/test1.yml.tpl")
+			Expect(err).ToNot(HaveOccurred())
+			return strings.Replace(string(raw), "name: test", "name: "+name, 1)
+		}
+		var getName = func(fs http.FileSystem) string {
+			rawObject, err := templates.Get(fs, "test.yml.tpl", struct{}{})
+			Expect(err).ToNot(HaveOccurred())
+			return rawObject.(*appsv1.Deployment).Name
+		}
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "templates")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			templates.ResetDir()
+			os.RemoveAll(tmpDir)
+		})
+
+		It("should reuse the compiled template until the cache is cleared", func() {
+			fs := http.Dir(tmpDir)
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "test.yml.tpl"), []byte(deployment("one")), 0644)).To(Succeed())
+			Expect(getName(fs)).To(Equal("one"))
+
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "test.yml.tpl"), []byte(deployment("two")), 0644)).To(Succeed())
+			Expect(getName(fs)).To(Equal("one"))
+
+			templates.ClearCache()
+			Expect(getName(fs)).To(Equal("two"))
+		})
+
+		// Mostly useful under the race detector, see `make race`.
+		It("should handle Gets concurrent with clearing the cache", func() {
+			fs := http.Dir(tmpDir)
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "test.yml.tpl"), []byte(deployment("one")), 0644)).To(Succeed())
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(2)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for j := 0; j < 20; j++ {
+						Expect(getName(fs)).To(Equal("one"))
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 20; j++ {
+						templates.ClearCache()
+					}
+				}()
+			}
+			wg.Wait()
+
+			// Nothing stale was left behind by a Get which overlapped a clear.
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "test.yml.tpl"), []byte(deployment("two")), 0644)).To(Succeed())
+			templates.ClearCache()
+			Expect(getName(fs)).To(Equal("two"))
+		})
+
+		It("should load and reload templates from the override directory", func() {
+			builtin := http.Dir(filepath.Join(tmpDir, "builtin"))
+			Expect(os.MkdirAll(filepath.Join(tmpDir, "builtin"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "builtin", "test.yml.tpl"), []byte(deployment("builtin")), 0644)).To(Succeed())
+			fs := templates.Named("controller/test", builtin)
+			Expect(getName(fs)).To(Equal("builtin"))
+
+			overrideDir := filepath.Join(tmpDir, "override")
+			Expect(os.MkdirAll(filepath.Join(overrideDir, "controller", "test", "templates"), 0755)).To(Succeed())
+			overridePath := filepath.Join(overrideDir, "controller", "test", "templates", "test.yml.tpl")
+			Expect(ioutil.WriteFile(overridePath, []byte(deployment("override")), 0644)).To(Succeed())
+			stop := make(chan struct{})
+			defer close(stop)
+			Expect(templates.UseDir(overrideDir, stop)).To(Succeed())
+			Expect(getName(fs)).To(Equal("override"))
+
+			Expect(ioutil.WriteFile(overridePath, []byte(deployment("changed")), 0644)).To(Succeed())
+			Eventually(func() string { return getName(fs) }).Should(Equal("changed"))
+		})
+
+		It("should go back to the built-in templates when stopped", func() {
+			builtin := http.Dir(filepath.Join(tmpDir, "builtin"))
+			Expect(os.MkdirAll(filepath.Join(tmpDir, "builtin"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "builtin", "test.yml.tpl"), []byte(deployment("builtin")), 0644)).To(Succeed())
+			fs := templates.Named("controller/test", builtin)
+
+			overrideDir := filepath.Join(tmpDir, "override")
+			Expect(os.MkdirAll(filepath.Join(overrideDir, "controller", "test", "templates"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(overrideDir, "controller", "test", "templates", "test.yml.tpl"), []byte(deployment("override")), 0644)).To(Succeed())
+			stop := make(chan struct{})
+			Expect(templates.UseDir(overrideDir, stop)).To(Succeed())
+			Expect(getName(fs)).To(Equal("override"))
+
+			close(stop)
+			Eventually(func() string { return getName(fs) }).Should(Equal("builtin"))
+		})
+	})
 })
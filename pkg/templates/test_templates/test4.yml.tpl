@@ -0,0 +1,2 @@
+{{ define "componentName" }}four{{ end }}
+{{ if .Enabled }}{{ template "deployment" . }}{{ end }}
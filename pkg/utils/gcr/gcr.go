@@ -1,8 +1,8 @@
 package gcr
 
 import (
+	"context"
 	"net/http"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -11,6 +11,8 @@ import (
 	"github.com/golang/glog"
 	"github.com/heroku/docker-registry-client/registry"
 	"github.com/pkg/errors"
+
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 )
 
 const cacheExpiry time.Duration = time.Minute * 5
@@ -21,7 +23,7 @@ var LastCacheUpdate time.Time
 var CachedTags []string
 
 func GetCacheExpiry() time.Duration {
-	if os.Getenv("LOCAL_REGISTRY_URL") == "" {
+	if config.Get("LOCAL_REGISTRY_URL") == "" {
 		return cacheExpiry
 	} else {
 		return testCacheExpiry
@@ -51,6 +53,40 @@ func SanitizeBranchName(branch string) (string, error) {
 	return sanitized_branch_tag, nil
 }
 
+// Setup hub connection
+func newHub() *registry.Registry {
+	var key = config.Get("GOOGLE_SERVICE_ACCOUNT_KEY")
+	var registry_url = config.Get("LOCAL_REGISTRY_URL")
+	// If we don't have a test registry, use the real one.
+	if registry_url == "" {
+		registry_url = "https://us.gcr.io"
+	}
+
+	var transport = registry.WrapTransport(http.DefaultTransport, registry_url, "_json_key", key)
+	return &registry.Registry{
+		URL: registry_url,
+		Client: &http.Client{
+			Transport: transport,
+		},
+		Logf: registry.Quiet,
+	}
+}
+
+// Check the registry is reachable and accepts our credentials.
+func Ping(ctx context.Context) error {
+	hub := newHub()
+	// The registry client doesn't take a context, so at least respect its deadline.
+	deadline, ok := ctx.Deadline()
+	if ok {
+		hub.Client.Timeout = time.Until(deadline)
+	}
+	err := hub.Ping()
+	if err != nil {
+		return errors.Wrap(err, "Could not ping registry")
+	}
+	return nil
+}
+
 func GetLatestImageOfBranch(branchTag string) (string, error) {
 	var latestImage string
 	latestBuild := 0
@@ -58,24 +94,7 @@ func GetLatestImageOfBranch(branchTag string) (string, error) {
 
 	// Fetch tags if cache expired.
 	if elapsed >= GetCacheExpiry() {
-		// Setup hub connection
-		var key = os.Getenv("GOOGLE_SERVICE_ACCOUNT_KEY")
-		var registry_url = os.Getenv("LOCAL_REGISTRY_URL")
-		// If we don't have a test registry, use the real one.
-		if registry_url == "" {
-			registry_url = "https://us.gcr.io"
-		}
-
-		var transport = registry.WrapTransport(http.DefaultTransport, registry_url, "_json_key", key)
-		var summonHub = &registry.Registry{
-			URL: registry_url,
-			Client: &http.Client{
-				Transport: transport,
-			},
-			Logf: registry.Quiet,
-		}
-
-		tags, err := summonHub.Tags("ridecell-1/summon")
+		tags, err := newHub().Tags("ridecell-1/summon")
 		if err != nil {
 			return "", errors.Wrapf(err, "Could not retrieve tags from registry: ")
 		}
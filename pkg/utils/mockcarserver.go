@@ -18,11 +18,12 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 	"github.com/Ridecell/ridecell-operator/pkg/errors"
 	"net/http"
-	"os"
 	"time"
 )
 
@@ -37,9 +38,9 @@ func GetHttpClient() http.Client {
 	}
 }
 
-func httpRequest(method string, resourcePath string, data *bytes.Buffer) (*http.Response, error) {
-	URI := os.Getenv("MOCKCARSERVER_URI")
-	AUTH := os.Getenv("MOCKCARSERVER_AUTH")
+func httpRequest(ctx context.Context, method string, resourcePath string, data *bytes.Buffer) (*http.Response, error) {
+	URI := config.Get("MOCKCARSERVER_URI")
+	AUTH := config.Get("MOCKCARSERVER_AUTH")
 	AUTH_CLIENT := "ridecell-operator"
 	client := GetHttpClient()
 	request, err := func() (*http.Request, error) {
@@ -52,6 +53,7 @@ func httpRequest(method string, resourcePath string, data *bytes.Buffer) (*http.
 	if err != nil {
 		return nil, errors.Wrapf(err, "Unable to create request.")
 	}
+	request = request.WithContext(ctx)
 	request.Header.Set("API-KEY", AUTH)
 	request.Header.Set("API-CLIENT", AUTH_CLIENT)
 	request.Header.Set("Content-type", "application/json")
@@ -80,8 +82,8 @@ func checkResponseStatus(statusCode int) error {
 // GET request
 // query param: name
 // response code: 200 success (present), 404 (not found), 401 (invalid auth)
-func GetMockTenant(tenantName string) (bool, error) {
-	response, err := httpRequest("GET", "/common/tenant?name="+tenantName, nil)
+func GetMockTenant(ctx context.Context, tenantName string) (bool, error) {
+	response, err := httpRequest(ctx, "GET", "/common/tenant?name="+tenantName, nil)
 	if err != nil {
 		return false, errors.Wrapf(err, "mockcarserver error")
 	}
@@ -96,13 +98,13 @@ func GetMockTenant(tenantName string) (bool, error) {
 // POST request
 // param: name, callbackUrl, tenantHardwareType, apiKey, secretKey, apiToken, pushApiKey, pushSecretKey, pushToken
 // response code: 201 created, 400 (bad params), 401 (invalid auth)
-func CreateOrUpdateMockTenant(postData map[string]string) (bool, error) {
+func CreateOrUpdateMockTenant(ctx context.Context, postData map[string]string) (bool, error) {
 	jsonData, err := json.Marshal(postData)
 	if err != nil {
 		return false, errors.Wrapf(err, "Unable to convert data into json format")
 	}
 
-	response, err := httpRequest("POST", "/common/tenant", bytes.NewBuffer(jsonData))
+	response, err := httpRequest(ctx, "POST", "/common/tenant", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return false, errors.Wrapf(err, "mockcarserver error")
 	}
@@ -117,8 +119,8 @@ func CreateOrUpdateMockTenant(postData map[string]string) (bool, error) {
 // DELETE request
 // query param: name
 // response code: 200 success, 400 (bad params), 401 (invalid auth)
-func DeleteMockTenant(tenantName string) (bool, error) {
-	response, err := httpRequest("DELETE", "/common/tenant?name="+tenantName, nil)
+func DeleteMockTenant(ctx context.Context, tenantName string) (bool, error) {
+	response, err := httpRequest(ctx, "DELETE", "/common/tenant?name="+tenantName, nil)
 	if err != nil {
 		return false, errors.Wrapf(err, "mockcarserver error")
 	}
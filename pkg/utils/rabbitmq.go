@@ -17,11 +17,13 @@ limitations under the License.
 package utils
 
 import (
+	"context"
 	"crypto/tls"
+	"net"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
+	"time"
 
 	"github.com/michaelklishin/rabbit-hole"
 
@@ -51,14 +53,28 @@ func RabbitholeClientFactory(uri string, user string, pass string, t *http.Trans
 }
 
 // Open a connection to the RabbitMQ server as defined by a RabbitmqConnection object.
-func OpenRabbit(_ctx *components.ComponentContext, _dbInfo *dbv1beta1.RabbitmqConnection, clientFactory RabbitMQClientFactory) (RabbitMQManager, error) {
-	uri := os.Getenv("RABBITMQ_URI")
-	insecure := os.Getenv("RABBITMQ_INSECURE")
-
+func OpenRabbit(ctx *components.ComponentContext, _dbInfo *dbv1beta1.RabbitmqConnection, clientFactory RabbitMQClientFactory) (RabbitMQManager, error) {
+	uri := ctx.Config().Get("RABBITMQ_URI")
+	insecure := ctx.Config().Get("RABBITMQ_INSECURE")
+
+	// rabbit-hole doesn't take a context, so tie the transport to ours instead. Connections
+	// are cancelled along with the context and responses can't outlive its deadline.
+	reqCtx := context.Background()
+	if ctx != nil && ctx.Context != nil {
+		reqCtx = ctx.Context
+	}
+	dialer := &net.Dialer{}
 	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: insecure != "",
 		},
+		DialContext: func(_ context.Context, network, addr string) (net.Conn, error) {
+			return dialer.DialContext(reqCtx, network, addr)
+		},
+	}
+	deadline, ok := reqCtx.Deadline()
+	if ok {
+		transport.ResponseHeaderTimeout = time.Until(deadline)
 	}
 
 	parsedUri, err := url.Parse(uri)
@@ -78,6 +94,30 @@ func OpenRabbit(_ctx *components.ComponentContext, _dbInfo *dbv1beta1.RabbitmqCo
 	return clientFactory(rmqHost, rmqUser, rmqPass, transport)
 }
 
+// Classify a failed RabbitMQ management API call by its status code. 429s are retried after a
+// delay and other 4xx errors are problems with the spec, which retrying won't fix. Bad operator
+// credentials aren't the object's fault, so 401 and 403 are left alone.
+func RabbitStatusError(statusCode int, err error) error {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return errors.RateLimited(err, 0)
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return err
+	case statusCode >= 400 && statusCode < 500:
+		return errors.Permanent(err)
+	}
+	return err
+}
+
+// Classify an error returned by the rabbit-hole client, see RabbitStatusError.
+func RabbitError(err error) error {
+	rerr, ok := errors.Cause(err).(rabbithole.ErrorResponse)
+	if !ok {
+		return err
+	}
+	return RabbitStatusError(rerr.StatusCode, err)
+}
+
 func RabbitHostAndPort(client RabbitMQManager) (*dbv1beta1.RabbitmqStatusConnection, error) {
 	realClient, ok := client.(*rabbithole.Client)
 	if ok {
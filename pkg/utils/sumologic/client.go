@@ -18,6 +18,7 @@ package sumologic
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"io"
@@ -71,6 +72,7 @@ type Client struct {
 	BaseURL    string
 	Auth       string
 	httpClient *http.Client
+	ctx        context.Context
 }
 
 func NewClient(baseurl, id, key string) (*Client, error) {
@@ -81,6 +83,13 @@ func NewClient(baseurl, id, key string) (*Client, error) {
 	}, nil
 }
 
+// WithContext returns a shallow copy of the client whose requests are bound to ctx.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
 func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
 	var buf io.ReadWriter
 	if body != nil {
@@ -94,6 +103,9 @@ func (c *Client) newRequest(method, path string, body interface{}) (*http.Reques
 	if err != nil {
 		return nil, err
 	}
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+// A defaulting function for one object type, modifies the object in place.
+type DefaultFunc func(obj runtime.Object)
+
+// An admission handler which decodes the incoming object, runs a DefaultFunc on a copy, and
+// responds with a patch for the differences.
+type defaultingHandler struct {
+	newObject   func() runtime.Object
+	setDefaults DefaultFunc
+
+	decoder types.Decoder
+}
+
+var _ admission.Handler = &defaultingHandler{}
+var _ inject.Decoder = &defaultingHandler{}
+
+func (h *defaultingHandler) Handle(ctx context.Context, req types.Request) types.Response {
+	obj := h.newObject()
+	err := h.decoder.Decode(req, obj)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	// On create the namespace usually only comes from the request, and some defaults depend on it.
+	meta := obj.(metav1.Object)
+	if meta.GetNamespace() == "" {
+		meta.SetNamespace(req.AdmissionRequest.Namespace)
+	}
+
+	defaulted := obj.DeepCopyObject()
+	h.setDefaults(defaulted)
+	return admission.PatchResponse(obj, defaulted)
+}
+
+func (h *defaultingHandler) InjectDecoder(d types.Decoder) error {
+	h.decoder = d
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+// A validation function for one object type. It only gets the object, anything that depends on
+// other objects, like input secrets, is checked by the controller and reported as a condition.
+// Errors returned are shown to the user as the denial reason.
+type ValidateFunc func(obj runtime.Object) error
+
+// An admission handler which decodes the incoming object and runs a ValidateFunc on it.
+type validatingHandler struct {
+	newObject func() runtime.Object
+	validate  ValidateFunc
+
+	decoder types.Decoder
+}
+
+var _ admission.Handler = &validatingHandler{}
+var _ inject.Decoder = &validatingHandler{}
+
+func (h *validatingHandler) Handle(ctx context.Context, req types.Request) types.Response {
+	obj := h.newObject()
+	err := h.decoder.Decode(req, obj)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+
+	// Let deletes finish even if the object no longer passes, the finalizers have to come off.
+	if obj.(metav1.Object).GetDeletionTimestamp() != nil {
+		return admission.ValidationResponse(true, "")
+	}
+	// Only check updates that change the spec. Metadata and status writes, like the controllers
+	// adding annotations or removing finalizers, shouldn't fail on rules added after creation.
+	if req.AdmissionRequest.Operation == admissionv1beta1.Update {
+		changed, err := specChanged(req.AdmissionRequest.OldObject.Raw, req.AdmissionRequest.Object.Raw)
+		if err != nil {
+			return admission.ErrorResponse(http.StatusBadRequest, err)
+		}
+		if !changed {
+			return admission.ValidationResponse(true, "")
+		}
+	}
+
+	err = h.validate(obj)
+	if err != nil {
+		return admission.ValidationResponse(false, err.Error())
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+func (h *validatingHandler) InjectDecoder(d types.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Compare the spec of two serialized objects.
+func specChanged(oldRaw, newRaw []byte) (bool, error) {
+	var oldObj, newObj struct {
+		Spec interface{} `json:"spec"`
+	}
+	err := json.Unmarshal(oldRaw, &oldObj)
+	if err != nil {
+		return false, err
+	}
+	err = json.Unmarshal(newRaw, &newObj)
+	if err != nil {
+		return false, err
+	}
+	return !reflect.DeepEqual(oldObj.Spec, newObj.Spec), nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Ridecell/ridecell-operator/pkg/webhook"
+)
+
+var _ = Describe("validating webhook", func() {
+	Describe("SpecChanged", func() {
+		It("ignores metadata and status changes", func() {
+			oldRaw := []byte(`{"metadata":{"name":"foo","finalizers":["a"]},"spec":{"version":"1.2.3"},"status":{"status":"Ready"}}`)
+			newRaw := []byte(`{"metadata":{"name":"foo","annotations":{"a":"b"}},"spec":{"version":"1.2.3"},"status":{"status":"Deploying"}}`)
+			changed, err := webhook.SpecChanged(oldRaw, newRaw)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+		It("ignores key order", func() {
+			changed, err := webhook.SpecChanged([]byte(`{"spec":{"a":1,"b":2}}`), []byte(`{"spec":{"b":2,"a":1}}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+		It("finds spec changes", func() {
+			changed, err := webhook.SpecChanged([]byte(`{"spec":{"version":"1.2.3"}}`), []byte(`{"spec":{"version":"1.2.4"}}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+		})
+
+		It("fails on bad JSON", func() {
+			_, err := webhook.SpecChanged([]byte(`{`), []byte(`{"spec":{}}`))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
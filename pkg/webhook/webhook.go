@@ -0,0 +1,179 @@
+/*
+Copyright 2019 Ridecell, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/builder"
+
+	dbv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/db/v1beta1"
+	summonv1beta1 "github.com/Ridecell/ridecell-operator/pkg/apis/summon/v1beta1"
+	dbconfigcomponents "github.com/Ridecell/ridecell-operator/pkg/controller/dbconfig/components"
+	vhostcomponents "github.com/Ridecell/ridecell-operator/pkg/controller/rabbitmq_vhost/components"
+	rdscomponents "github.com/Ridecell/ridecell-operator/pkg/controller/rds/components"
+	summoncomponents "github.com/Ridecell/ridecell-operator/pkg/controller/summon/components"
+)
+
+// The port the webhook server listens on, the controller-manager-service forwards 443 to this.
+var Port int32 = 9876
+
+type validator struct {
+	name      string
+	newObject func() runtime.Object
+	validate  ValidateFunc
+}
+
+// All the types we validate on admission. Each one reuses the validation from its defaults component
+// so a spec rejected here would also have been rejected at reconcile time.
+var validators = []validator{
+	{
+		name:      "validate.summonplatform.summon.ridecell.io",
+		newObject: func() runtime.Object { return &summonv1beta1.SummonPlatform{} },
+		validate: func(obj runtime.Object) error {
+			return summoncomponents.Validate(obj.(*summonv1beta1.SummonPlatform))
+		},
+	},
+	{
+		name:      "validate.dbconfig.db.ridecell.io",
+		newObject: func() runtime.Object { return &dbv1beta1.DbConfig{} },
+		validate: func(obj runtime.Object) error {
+			return dbconfigcomponents.Validate(obj.(*dbv1beta1.DbConfig))
+		},
+	},
+	{
+		name:      "validate.rdsinstance.db.ridecell.io",
+		newObject: func() runtime.Object { return &dbv1beta1.RDSInstance{} },
+		validate: func(obj runtime.Object) error {
+			return rdscomponents.Validate(obj.(*dbv1beta1.RDSInstance))
+		},
+	},
+	{
+		name:      "validate.rabbitmqvhost.db.ridecell.io",
+		newObject: func() runtime.Object { return &dbv1beta1.RabbitmqVhost{} },
+		validate: func(obj runtime.Object) error {
+			return vhostcomponents.Validate(obj.(*dbv1beta1.RabbitmqVhost))
+		},
+	},
+}
+
+type defaulter struct {
+	name        string
+	newObject   func() runtime.Object
+	setDefaults DefaultFunc
+}
+
+// Types whose defaults are persisted on create when defaulting is enabled.
+var defaulters = []defaulter{
+	{
+		name:        "default.summonplatform.summon.ridecell.io",
+		newObject:   func() runtime.Object { return &summonv1beta1.SummonPlatform{} },
+		setDefaults: defaultSummonPlatform,
+	},
+}
+
+func defaultSummonPlatform(obj runtime.Object) {
+	instance := obj.(*summonv1beta1.SummonPlatform)
+	// Already defaulted, probably a restore from a backup, don't change what it was created with.
+	_, ok := instance.Annotations[summoncomponents.DefaultsVersionAnnotation]
+	if ok {
+		return
+	}
+	summoncomponents.SetDefaults(instance)
+	if instance.Annotations == nil {
+		instance.Annotations = map[string]string{}
+	}
+	instance.Annotations[summoncomponents.DefaultsVersionAnnotation] = summoncomponents.DefaultsVersion
+}
+
+type Options struct {
+	// Persist defaults into new objects with a mutating webhook. Off by default since it changes
+	// what gets stored, rather than only accepting or rejecting it.
+	Defaulting bool
+}
+
+// Create the webhook server and register all admission webhooks with the manager. The server
+// bootstraps its own serving certificate and webhook configurations.
+func AddToManager(mgr manager.Manager, options Options) error {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		namespace = "ridecell-operator-system"
+	}
+
+	server, err := webhook.NewServer("ridecell-operator-admission-server", mgr, webhook.ServerOptions{
+		Port:    Port,
+		CertDir: "/tmp/cert",
+		BootstrapOptions: &webhook.BootstrapOptions{
+			Secret: &apitypes.NamespacedName{Namespace: namespace, Name: "ridecell-operator-webhook-server-secret"},
+			Service: &webhook.Service{
+				Namespace: namespace,
+				Name:      "ridecell-operator-controller-manager-service",
+				Selectors: map[string]string{
+					"control-plane":           "controller-manager",
+					"controller-tools.k8s.io": "1.0",
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "error creating webhook server")
+	}
+
+	webhooks := []webhook.Webhook{}
+	for _, v := range validators {
+		wh, err := builder.NewWebhookBuilder().
+			Name(v.name).
+			Validating().
+			Operations(admissionregistrationv1beta1.Create, admissionregistrationv1beta1.Update).
+			// Don't block all changes while the operator is down, reconcile will still catch errors.
+			FailurePolicy(admissionregistrationv1beta1.Ignore).
+			WithManager(mgr).
+			ForType(v.newObject()).
+			Handlers(&validatingHandler{newObject: v.newObject, validate: v.validate}).
+			Build()
+		if err != nil {
+			return errors.Wrapf(err, "error building webhook %s", v.name)
+		}
+		webhooks = append(webhooks, wh)
+	}
+
+	if options.Defaulting {
+		for _, d := range defaulters {
+			wh, err := builder.NewWebhookBuilder().
+				Name(d.name).
+				Mutating().
+				Operations(admissionregistrationv1beta1.Create).
+				FailurePolicy(admissionregistrationv1beta1.Ignore).
+				WithManager(mgr).
+				ForType(d.newObject()).
+				Handlers(&defaultingHandler{newObject: d.newObject, setDefaults: d.setDefaults}).
+				Build()
+			if err != nil {
+				return errors.Wrapf(err, "error building webhook %s", d.name)
+			}
+			webhooks = append(webhooks, wh)
+		}
+	}
+
+	return server.Register(webhooks...)
+}
@@ -99,6 +99,7 @@ func App() *buffalo.App {
 		statusGroup := app.Group("/status")
 		statusGroup.GET("/", StatusBaseHandler)
 		statusGroup.GET("/{instance}", StatusHandler)
+		statusGroup.POST("/{instance}/wake", WakeHandler)
 
 		app.ServeFiles("/", assetsBox) // serve files from the public directory
 	}
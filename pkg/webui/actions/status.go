@@ -16,6 +16,9 @@ import (
 
 const sumoEndpointBase = "https://service.us2.sumologic.com/ui/index.html#section/search/"
 
+// How long the wake button keeps a hibernating instance up.
+const wakeDuration = 4 * time.Hour
+
 // StatusBaseHandler is a default handler to serve up statuses.
 func StatusBaseHandler(c buffalo.Context) error {
 	namespaces, err := kubernetes.ListNamespaces()
@@ -122,8 +125,53 @@ func StatusHandler(c buffalo.Context) error {
 		}
 	}
 
+	type historyStruct struct {
+		From      string
+		To        string
+		Time      string
+		Duration  string
+		Version   string
+		Component string
+		Message   string
+	}
+
+	// Newest first, with how long each status lasted.
+	var historyList []historyStruct
+	history := instance.Status.History
+	for i := len(history) - 1; i >= 0; i-- {
+		end := time.Now()
+		if i+1 < len(history) {
+			end, _ = time.Parse(time.RFC3339, history[i+1].Time)
+		}
+		duration := ""
+		start, err := time.Parse(time.RFC3339, history[i].Time)
+		if err == nil {
+			duration = end.Sub(start).Round(time.Second).String()
+		}
+		historyList = append(historyList, historyStruct{
+			From:      history[i].From,
+			To:        history[i].To,
+			Time:      history[i].Time,
+			Duration:  duration,
+			Version:   history[i].Version,
+			Component: history[i].Component,
+			Message:   history[i].Message,
+		})
+	}
+
 	c.Set("instance", instance)
 	c.Set("deployments", deploymentList)
 	c.Set("pods", podList)
+	c.Set("history", historyList)
 	return c.Render(200, r.HTML("status/status.html"))
 }
+
+// WakeHandler keeps a hibernating instance awake for a few hours.
+func WakeHandler(c buffalo.Context) error {
+	instanceName := c.Param("instance")
+	err := kubernetes.WakeSummonPlatform(instanceName, time.Now().Add(wakeDuration))
+	if err != nil {
+		return err
+	}
+	return c.Redirect(302, fmt.Sprintf("/status/%s", instanceName))
+}
@@ -2,12 +2,13 @@ package gcr
 
 import (
 	"net/http"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/heroku/docker-registry-client/registry"
+
+	"github.com/Ridecell/ridecell-operator/pkg/config"
 )
 
 type parsedTag struct {
@@ -22,7 +23,7 @@ func (a parsedTagList) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a parsedTagList) Less(i, j int) bool { return a[i].build > a[j].build }
 
 func GetLatestImageVersions() ([]string, error) {
-	key := os.Getenv("GOOGLE_SERVICE_ACCOUNT_KEY")
+	key := config.Get("GOOGLE_SERVICE_ACCOUNT_KEY")
 
 	transport := registry.WrapTransport(http.DefaultTransport, "https://us.gcr.io", "_json_key", key)
 	hub := &registry.Registry{
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Ridecell/ridecell-operator/pkg/apis"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -109,6 +110,23 @@ func GetSummonObject(name string) (*summonv1beta1.SummonPlatform, error) {
 	return instance, nil
 }
 
+// Keep an instance awake until the given time, regardless of its hibernation schedule.
+func WakeSummonPlatform(name string, until time.Time) error {
+	contextClient, err := getClient()
+	if err != nil {
+		return err
+	}
+	instance, err := GetSummonObject(name)
+	if err != nil {
+		return err
+	}
+	if instance.Annotations == nil {
+		instance.Annotations = map[string]string{}
+	}
+	instance.Annotations[summonv1beta1.WakeUntilAnnotation] = until.Format(time.RFC3339)
+	return contextClient.Update(context.Background(), instance)
+}
+
 func ListNamespaces() ([]string, error) {
 	contextClient, err := getClient()
 	if err != nil {